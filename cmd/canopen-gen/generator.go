@@ -0,0 +1,473 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+
+// dataTypesEnd is the end of the CiA 301 data types area (0x0001 - 0x025F),
+// which contain dummy objects and data types definitions, not device objects
+const dataTypesEnd uint16 = 0x0260
+
+// Options of the generated package
+type Options struct {
+	Package string
+	Type    string
+	Source  string
+}
+
+type genConst struct {
+	Name   string
+	GoType string
+	Value  int64
+	Doc    string
+}
+
+type genVariable struct {
+	Method   string
+	GoType   string
+	Index    uint16
+	SubIndex uint8
+	Name     string
+}
+
+type genObject struct {
+	Method   string
+	TypeName string
+	Kind     string
+	Index    uint16
+	Name     string
+	Members  []genVariable
+}
+
+type genPDOField struct {
+	Name     string
+	GoType   string
+	Offset   int
+	DataType byte
+	Index    uint16
+	SubIndex uint8
+}
+
+type genPDO struct {
+	TypeName  string
+	Receive   bool
+	Number    int
+	MapIndex  uint16
+	MapMethod string
+	Size      int
+	Fields    []genPDOField
+}
+
+type genFile struct {
+	Options
+	UseTime   bool
+	Variables []genVariable
+	Objects   []genObject
+	Consts    []genConst
+	PDOs      []genPDO
+}
+
+// goTypes map CANopen data types to Go types
+var goTypes = map[byte]string{
+	canopen.Boolean:        "bool",
+	canopen.Integer8:       "int8",
+	canopen.Integer16:      "int16",
+	canopen.Integer24:      "int32",
+	canopen.Integer32:      "int32",
+	canopen.Integer40:      "int64",
+	canopen.Integer48:      "int64",
+	canopen.Integer56:      "int64",
+	canopen.Integer64:      "int64",
+	canopen.Unsigned8:      "uint8",
+	canopen.Unsigned16:     "uint16",
+	canopen.Unsigned24:     "uint32",
+	canopen.Unsigned32:     "uint32",
+	canopen.Unsigned40:     "uint64",
+	canopen.Unsigned48:     "uint64",
+	canopen.Unsigned56:     "uint64",
+	canopen.Unsigned64:     "uint64",
+	canopen.Real32:         "float32",
+	canopen.Real64:         "float64",
+	canopen.VisibleString:  "string",
+	canopen.UnicodeString:  "string",
+	canopen.OctetString:    "[]byte",
+	canopen.Domain:         "[]byte",
+	canopen.TimeOfDay:      "time.Time",
+	canopen.TimeDifference: "time.Duration",
+}
+
+// goName convert an object name to an exported Go identifier, eg: "Vendor-ID" to "VendorID"
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	s := b.String()
+	if s == "" || !unicode.IsLetter([]rune(s)[0]) || []rune(s)[0] > unicode.MaxASCII {
+		s = "X" + s
+	}
+
+	return s
+}
+
+// nameSet give unique identifiers
+type nameSet map[string]bool
+
+// unique return name, or name with suffix if name is already used
+func (names nameSet) unique(name string, suffix string) string {
+	if names[name] {
+		name += suffix
+	}
+
+	base := name
+	for i := 2; names[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+
+	names[name] = true
+
+	return name
+}
+
+// Generate return the gofmt formatted Go source of typed bindings of objectDic
+func Generate(objectDic *canopen.DicObjectDic, opts Options) ([]byte, error) {
+	file := &genFile{Options: opts}
+
+	// Device methods, reserving Node field name, and package level types and constants
+	methods := nameSet{"Node": true}
+	scope := nameSet{opts.Type: true, "New" + opts.Type: true}
+
+	indexes := make([]int, 0, len(objectDic.Indexes))
+	for index := range objectDic.Indexes {
+		indexes = append(indexes, int(index))
+	}
+	sort.Ints(indexes)
+
+	for _, idx := range indexes {
+		index := uint16(idx)
+
+		// Dummy objects and data types definitions
+		if index < dataTypesEnd {
+			continue
+		}
+
+		switch object := objectDic.Indexes[index].(type) {
+		case *canopen.DicVariable:
+			variable, ok := file.variable(object, methods, scope, "")
+			if ok {
+				file.Variables = append(file.Variables, variable)
+			}
+		case *canopen.DicArray:
+			file.object(index, object.Name, "Array", object.SubIndexes, methods, scope)
+		case *canopen.DicRecord:
+			file.object(index, object.Name, "Record", object.SubIndexes, methods, scope)
+		}
+	}
+
+	for _, idx := range indexes {
+		index := uint16(idx)
+
+		switch {
+		case index >= 0x1600 && index < 0x1800:
+			file.pdo(objectDic, index, true, int(index-0x1600)+1, methods, scope)
+		case index >= 0x1A00 && index < 0x1C00:
+			file.pdo(objectDic, index, false, int(index-0x1A00)+1, methods, scope)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := genTemplate.Execute(buf, file); err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid generated source: %v", err)
+	}
+
+	return src, nil
+}
+
+func (file *genFile) goType(dataType byte) (string, bool) {
+	goType, ok := goTypes[dataType]
+	if ok && strings.HasPrefix(goType, "time.") {
+		file.UseTime = true
+	}
+
+	return goType, ok
+}
+
+// variable return the accessor of variable, and add its value descriptions constants
+func (file *genFile) variable(
+	variable *canopen.DicVariable,
+	methods, scope nameSet,
+	constPrefix string,
+) (genVariable, bool) {
+	goType, ok := file.goType(variable.DataType)
+	if !ok {
+		return genVariable{}, false
+	}
+
+	suffix := fmt.Sprintf("%04X", variable.Index)
+	if constPrefix != "" {
+		suffix = fmt.Sprintf("Sub%d", variable.SubIndex)
+	}
+
+	method := methods.unique(goName(variable.Name), suffix)
+
+	if canopen.IsIntegerType(variable.DataType) {
+		values := make([]int, 0, len(variable.ValueDescriptions))
+		for value := range variable.ValueDescriptions {
+			values = append(values, int(value))
+		}
+		sort.Ints(values)
+
+		for _, value := range values {
+			des := variable.ValueDescriptions[int64(value)]
+			file.Consts = append(file.Consts, genConst{
+				Name:   scope.unique(constPrefix+method+goName(des), fmt.Sprintf("%d", value)),
+				GoType: goType,
+				Value:  int64(value),
+				Doc:    des,
+			})
+		}
+	}
+
+	return genVariable{
+		Method:   method,
+		GoType:   goType,
+		Index:    variable.Index,
+		SubIndex: variable.SubIndex,
+		Name:     variable.Name,
+	}, true
+}
+
+// object add an array or record type and its accessor
+func (file *genFile) object(
+	index uint16,
+	name string,
+	kind string,
+	subIndexes map[uint8]canopen.DicObject,
+	methods, scope nameSet,
+) {
+	method := methods.unique(goName(name), fmt.Sprintf("%04X", index))
+	object := genObject{
+		Method:   method,
+		TypeName: scope.unique(method+kind, ""),
+		Kind:     strings.ToLower(kind),
+		Index:    index,
+		Name:     name,
+	}
+
+	members := nameSet{}
+	for sub := 0; sub <= 0xFF; sub++ {
+		member, ok := subIndexes[uint8(sub)].(*canopen.DicVariable)
+		if !ok {
+			continue
+		}
+
+		if variable, ok := file.variable(member, members, scope, method); ok {
+			object.Members = append(object.Members, variable)
+		}
+	}
+
+	file.Objects = append(file.Objects, object)
+}
+
+// pdo add the PDO struct of mapping object index default values
+func (file *genFile) pdo(
+	objectDic *canopen.DicObjectDic,
+	index uint16,
+	receive bool,
+	number int,
+	methods, scope nameSet,
+) {
+	kind := "TPDO"
+	if receive {
+		kind = "RPDO"
+	}
+
+	pdo := genPDO{
+		TypeName: scope.unique(fmt.Sprintf("%s%d", kind, number), "Mapping"),
+		Receive:  receive,
+		Number:   number,
+		MapIndex: index,
+	}
+
+	count := 0x40
+	if sub0 := objectDic.FindVariable(index, 0); sub0 != nil && len(sub0.Default) > 0 {
+		count = int(sub0.Default[0])
+	}
+
+	fields := nameSet{}
+	offset := 0
+
+	for sub := 1; sub <= count; sub++ {
+		entry := objectDic.FindVariable(index, uint8(sub))
+		if entry == nil || len(entry.Default) == 0 {
+			continue
+		}
+
+		value, err := canopen.Value[uint32](&canopen.DicVariable{DataType: entry.DataType, Data: entry.Default})
+		if err != nil || value == 0 {
+			continue
+		}
+
+		mappedIndex, mappedSub, size := uint16(value>>16), uint8(value>>8), int(value&0xFF)
+		fieldOffset := offset
+		offset += size
+
+		variable := objectDic.FindVariable(mappedIndex, mappedSub)
+
+		// Dummy entries, and entries not byte aligned, are only padding
+		if variable == nil || mappedIndex < dataTypesEnd || fieldOffset%8 != 0 || size != canopen.DataTypeSize(variable.DataType)*8 {
+			continue
+		}
+
+		goType, ok := file.goType(variable.DataType)
+		if !ok {
+			continue
+		}
+
+		pdo.Fields = append(pdo.Fields, genPDOField{
+			Name:     fields.unique(goName(variable.Name), fmt.Sprintf("%04X", mappedIndex)),
+			GoType:   goType,
+			Offset:   fieldOffset,
+			DataType: variable.DataType,
+			Index:    mappedIndex,
+			SubIndex: mappedSub,
+		})
+	}
+
+	if len(pdo.Fields) == 0 {
+		return
+	}
+
+	pdo.Size = (offset + 7) / 8
+	pdo.MapMethod = methods.unique(pdo.TypeName+"Map", "")
+	file.PDOs = append(file.PDOs, pdo)
+}
+
+var genTemplate = template.Must(template.New("bindings").Parse(`// Code generated by canopen-gen{{if .Source}} from {{.Source}}{{end}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .UseTime}}
+	"time"
+{{end}}
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+{{if .Consts}}
+// Value descriptions
+const (
+{{- range .Consts}}
+	{{.Name}} {{.GoType}} = {{.Value}} // {{.Doc}}
+{{- end}}
+)
+{{end}}
+// {{.Type}} is a typed binding of a node object dictionary
+type {{.Type}} struct {
+	Node *canopen.Node
+}
+
+// New{{.Type}} return a {{.Type}} binding of node
+func New{{.Type}}(node *canopen.Node) *{{.Type}} {
+	return &{{.Type}}{Node: node}
+}
+{{range .Variables}}
+// {{.Method}} return object 0x{{printf "%04X" .Index}} "{{.Name}}"
+func (d *{{$.Type}}) {{.Method}}() canopen.DicTypedVariable[{{.GoType}}] {
+	return canopen.NewDicTypedVariable[{{.GoType}}](d.Node, 0x{{printf "%04X" .Index}}, 0)
+}
+{{end}}
+{{- range .Objects}}
+{{- $object := .}}
+// {{.TypeName}} is the {{.Kind}} object 0x{{printf "%04X" .Index}} "{{.Name}}"
+type {{.TypeName}} struct {
+	node *canopen.Node
+}
+
+// {{.Method}} return object 0x{{printf "%04X" .Index}} "{{.Name}}"
+func (d *{{$.Type}}) {{.Method}}() {{.TypeName}} {
+	return {{.TypeName}}{node: d.Node}
+}
+{{range .Members}}
+// {{.Method}} return object 0x{{printf "%04X" .Index}} sub {{.SubIndex}} "{{.Name}}"
+func (o {{$object.TypeName}}) {{.Method}}() canopen.DicTypedVariable[{{.GoType}}] {
+	return canopen.NewDicTypedVariable[{{.GoType}}](o.node, 0x{{printf "%04X" .Index}}, {{.SubIndex}})
+}
+{{end}}
+{{- end}}
+{{- range .PDOs}}
+{{- $pdo := .}}
+// {{.TypeName}} is the default mapping of {{if .Receive}}RPDO{{else}}TPDO{{end}} {{.Number}}, object 0x{{printf "%04X" .MapIndex}}
+type {{.TypeName}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.GoType}} // 0x{{printf "%04X" .Index}} sub {{.SubIndex}}
+{{- end}}
+}
+
+// Decode set p fields from PDO data
+func (p *{{.TypeName}}) Decode(data []byte) error {
+	var err error
+{{range .Fields}}
+	if p.{{.Name}}, err = canopen.PDOFieldValue[{{.GoType}}](data, canopen.PDOField{Offset: {{.Offset}}, DataType: 0x{{printf "%02X" .DataType}}}); err != nil {
+		return err
+	}
+{{end}}
+	return nil
+}
+
+// Encode return PDO data from p fields
+func (p *{{.TypeName}}) Encode() ([]byte, error) {
+	data := make([]byte, {{.Size}})
+{{range .Fields}}
+	if err := canopen.PDOSetFieldValue(data, canopen.PDOField{Offset: {{.Offset}}, DataType: 0x{{printf "%02X" .DataType}}}, p.{{.Name}}); err != nil {
+		return nil, err
+	}
+{{end}}
+	return data, nil
+}
+
+// DecodeMap set p fields from last data of PDO map m
+func (p *{{.TypeName}}) DecodeMap(m *canopen.PDOMap) error {
+	m.Lock()
+	data := append([]byte{}, m.Data...)
+	m.Unlock()
+
+	return p.Decode(data)
+}
+{{if .Receive}}
+// Transmit send p fields using PDO map m
+func (p *{{.TypeName}}) Transmit(m *canopen.PDOMap) error {
+	data, err := p.Encode()
+	if err != nil {
+		return err
+	}
+
+	m.SetData(data)
+
+	return m.Transmit(false)
+}
+{{end}}
+// {{.MapMethod}} return the PDO map of {{.TypeName}}
+func (d *{{$.Type}}) {{.MapMethod}}() *canopen.PDOMap {
+	return d.Node.PDONode.{{if .Receive}}RX{{else}}TX{{end}}.FindIndex({{.Number}})
+}
+{{end}}`))
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+
+func TestGoName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Vendor-ID":             "VendorID",
+		"Position actual value": "PositionActualValue",
+		"1st parameter":         "X1stParameter",
+		"COB-ID used by RPDO":   "COBIDUsedByRPDO",
+	} {
+		if s := goName(name); s != expected {
+			t.Fatalf("goName(%q): expected %q, got %q", name, expected, s)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	objectDic, err := canopen.DicParse("testdata/drive.eds")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statusword := objectDic.FindIndex(0x6041).(*canopen.DicVariable)
+	statusword.AddValueDescription(8, "Fault")
+
+	// Data types definitions are not device objects
+	objectDic.AddObject(&canopen.DicVariable{Index: 0x0040, Name: "Custom type", DataType: canopen.Unsigned8})
+
+	src, err := Generate(objectDic, Options{Package: "drive", Type: "Drive", Source: "drive.eds"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"func (d *Drive) Controlword() canopen.DicTypedVariable[uint16]",
+		"func (o IdentityObjectRecord) VendorID() canopen.DicTypedVariable[uint32]",
+		"StatuswordFault uint16 = 8",
+		"type TPDO1 struct",
+		"canopen.PDOField{Offset: 24, DataType: 0x04}",
+		"func (p *RPDO1) Transmit(m *canopen.PDOMap) error",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Fatalf("%q not found in generated source:\n%s", expected, src)
+		}
+	}
+
+	if strings.Contains(string(src), "CustomType") {
+		t.Fatal("data type definition 0x0040 should not be generated")
+	}
+
+	if testing.Short() {
+		return
+	}
+
+	// Build generated package inside the module
+	dir, err := os.MkdirTemp(".", "_gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "drive.go"), src, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := exec.Command("go", "vet", "./"+dir).CombinedOutput(); err != nil {
+		t.Fatalf("generated package does not build: %v\n%s", err, out)
+	}
+}
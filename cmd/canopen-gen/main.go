@@ -0,0 +1,87 @@
+// Command canopen-gen generate typed Go bindings of an EDS, DCF, XDD or XDC file.
+//
+// Usage:
+//
+//	go run ./cmd/canopen-gen [-pkg name] [-type Device] [-o file.go] device.eds
+//
+// The generated package expose a typed accessor per object and sub object,
+// constants for value descriptions and PDO structs for default PDO mappings,
+// eg: drive.ControlWord().Set(0x0F)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+
+func main() {
+	pkg := flag.String("pkg", "", "generated package name, default to output directory name")
+	typeName := flag.String("type", "Device", "generated device type name")
+	output := flag.String("o", "", "output file, default to stdout")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] device.eds\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(flag.Arg(0), *output, *pkg, *typeName); err != nil {
+		fmt.Fprintln(os.Stderr, "canopen-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(input, output, pkg, typeName string) error {
+	objectDic, err := canopen.DicParse(input)
+	if err != nil {
+		return err
+	}
+
+	if pkg == "" {
+		pkg = "device"
+		if output != "" {
+			abs, err := filepath.Abs(output)
+			if err != nil {
+				return err
+			}
+
+			pkg = goPackageName(filepath.Base(filepath.Dir(abs)))
+		}
+	}
+
+	src, err := Generate(objectDic, Options{
+		Package: pkg,
+		Type:    typeName,
+		Source:  filepath.Base(input),
+	})
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err := os.Stdout.Write(src)
+		return err
+	}
+
+	return os.WriteFile(output, src, 0644)
+}
+
+// goPackageName convert a directory name to a package name
+func goPackageName(dir string) string {
+	name := strings.ToLower(goName(dir))
+	if name == "x" {
+		return "device"
+	}
+
+	return name
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/angelodlfrtr/go-can"
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+
+// emcy print emergency messages
+func (c *cli) emcy(ctx context.Context, args []string) error {
+	flags := c.subFlags("emcy")
+	duration := flags.Duration("duration", 0, "stop after duration, default to never")
+
+	if len(args) == 0 || args[0] != "watch" {
+		return fmt.Errorf("usage: emcy watch: %w", errUsage)
+	}
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	filter := func(frm *can.Frame) bool {
+		return frm.ArbitrationID > 0x80 && frm.ArbitrationID <= 0xFF
+	}
+
+	return c.watch(ctx, *duration, filter, func(frm *can.Frame) {
+		code := binary.LittleEndian.Uint16(frm.Data[0:])
+
+		c.printf(
+			"node %d emcy 0x%04X (%s) register 0x%02X data % X",
+			frm.ArbitrationID&0x7F, code, canopen.EMCYDescription(code), frm.Data[2], frm.Data[3:8],
+		)
+	})
+}
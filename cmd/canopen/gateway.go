@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+
+// gateway serve CiA 309-3 ASCII requests over TCP
+func (c *cli) gateway(ctx context.Context, args []string) error {
+	flags := c.subFlags("gateway")
+	listen := flags.String("listen", ":5000", "TCP listen address")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 0 {
+		return fmt.Errorf("usage: gateway [-listen addr]: %w", errUsage)
+	}
+
+	if err := c.open(); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+
+	gateway := canopen.NewGateway(c.network)
+	gateway.SDOTimeout = c.timeout
+
+	go func() {
+		<-ctx.Done()
+		gateway.Close()
+	}()
+
+	c.printf("CiA 309-3 gateway listening on %s", listener.Addr())
+
+	return gateway.Serve(listener)
+}
@@ -0,0 +1,275 @@
+// Command canopen is a command line tool for everyday CANopen bus work.
+//
+// Usage:
+//
+//	canopen [-bus uri] [-eds file] [-timeout d] command [arguments]
+//
+// Commands:
+//
+//	scan [-limit n] [-wait d]                    list nodes answering on the bus
+//	sdo read [-type t] node index[:sub]          read an object
+//	sdo write [-type t] node index[:sub] value   write an object
+//	nmt start|stop|preop|reset|reset-comm node|all
+//	pdo dump node                                print PDO configuration of a node
+//	emcy watch [-duration d]                     print emergency messages
+//	heartbeat watch [-duration d]                print heartbeats and node states
+//	monitor [-format f] [-duration d]            print decoded bus traffic, as a
+//	                                             table, JSON lines or candump
+//	record [-format f] [-duration d] file        write bus traffic to a candump or
+//	                                             ASC log, - for the output
+//	gateway [-listen addr]                       serve CiA 309-3 ASCII requests over TCP
+//
+// Nodes are decimal or 0x hexadecimal ids, indexes are hexadecimal, eg: 6041:0.
+// Values are in EDS notation, typed from the EDS given with -eds, or with -type,
+// eg: -type UNSIGNED16.
+//
+// The bus URI default to $CANOPEN_BUS, or socketcan://can0, see
+// canopen.NewTransport for supported transports, eg: usbcan:///dev/ttyUSB0.
+// Recorded logs are replayed with replay:///path/to/capture.log?speed=1.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angelodlfrtr/go-can"
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+
+const usage = `Usage: canopen [flags] command [arguments]
+
+Commands:
+  scan [-limit n] [-wait d]                    list nodes answering on the bus
+  sdo read [-type t] node index[:sub]          read an object
+  sdo write [-type t] node index[:sub] value   write an object
+  nmt start|stop|preop|reset|reset-comm node|all
+  pdo dump node                                print PDO configuration of a node
+  emcy watch [-duration d]                     print emergency messages
+  heartbeat watch [-duration d]                print heartbeats and node states
+  monitor [-format f] [-duration d]            print decoded bus traffic
+  record [-format f] [-duration d] file        write bus traffic to a candump or ASC log
+  gateway [-listen addr]                       serve CiA 309-3 ASCII requests over TCP
+
+Flags:
+`
+
+// errUsage is returned for invalid command lines
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, errUsage) && !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "canopen:", err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+// cli contain global flags values, and the network once opened
+type cli struct {
+	busURI  string
+	edsPath string
+	timeout time.Duration
+	out     io.Writer
+
+	bus       *can.Bus
+	network   *canopen.Network
+	objectDic *canopen.DicObjectDic
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	c := &cli{out: out}
+
+	busURI := os.Getenv("CANOPEN_BUS")
+	if busURI == "" {
+		busURI = "socketcan://can0"
+	}
+
+	flags := flag.NewFlagSet("canopen", flag.ContinueOnError)
+	flags.StringVar(&c.busURI, "bus", busURI, "bus transport URI")
+	flags.StringVar(&c.edsPath, "eds", "", "EDS, DCF, XDD, JSON or YAML object dictionary of the nodes")
+	flags.DurationVar(&c.timeout, "timeout", 500*time.Millisecond, "SDO response timeout")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	if c.edsPath != "" {
+		objectDic, err := canopen.DicParse(c.edsPath)
+		if err != nil {
+			return err
+		}
+
+		c.objectDic = objectDic
+	}
+
+	command, args := flags.Arg(0), flags.Args()[1:]
+
+	commands := map[string]func(context.Context, []string) error{
+		"scan":      c.scan,
+		"sdo":       c.sdo,
+		"nmt":       c.nmt,
+		"pdo":       c.pdo,
+		"emcy":      c.emcy,
+		"heartbeat": c.heartbeat,
+		"monitor":   c.monitor,
+		"record":    c.record,
+		"gateway":   c.gateway,
+	}
+
+	fn, ok := commands[command]
+	if !ok {
+		flags.Usage()
+		return fmt.Errorf("unknown command %q: %w", command, errUsage)
+	}
+
+	defer c.close()
+	return fn(ctx, args)
+}
+
+// open the bus and run the network
+func (c *cli) open() error {
+	if c.network != nil {
+		return nil
+	}
+
+	transport, err := canopen.NewTransport(c.busURI)
+	if err != nil {
+		return err
+	}
+
+	bus := can.NewBus(transport)
+	if err := bus.Open(); err != nil {
+		return err
+	}
+
+	network, err := canopen.NewNetwork(*bus)
+	if err != nil {
+		bus.Close()
+		return err
+	}
+
+	if err := network.Run(); err != nil {
+		bus.Close()
+		return err
+	}
+
+	c.bus = bus
+	c.network = network
+
+	return nil
+}
+
+func (c *cli) close() {
+	if c.network == nil {
+		return
+	}
+
+	c.network.Stop()
+	c.bus.Close()
+	c.network = nil
+}
+
+// node return a node of the opened network, using the object dictionary if any
+func (c *cli) node(nodeID int) *canopen.Node {
+	node := canopen.NewNode(nodeID, c.network, c.objectDic)
+	node.SDOClient = canopen.NewSDOClient(node)
+	node.SDOClient.Timeout = c.timeout
+	node.SDOClient.Strict = c.objectDic != nil
+
+	return node
+}
+
+// subFlags return a flag set for a sub command
+func (c *cli) subFlags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+
+	return flags
+}
+
+// printf write a line prefixed with current time
+func (c *cli) printf(format string, args ...interface{}) {
+	fmt.Fprintf(c.out, "%s "+format+"\n", append([]interface{}{time.Now().Format("15:04:05.000")}, args...)...)
+}
+
+// parseNodeID parse a decimal or 0x hexadecimal node id
+func parseNodeID(s string) (int, error) {
+	id, err := strconv.ParseUint(s, 0, 8)
+	if err != nil || id < 1 || id > 127 {
+		return 0, fmt.Errorf("invalid node id %q", s)
+	}
+
+	return int(id), nil
+}
+
+// parseObjectAddress parse an hexadecimal index with an optional sub index, eg: 6041 or 0x1018:1
+func parseObjectAddress(s string) (uint16, uint8, error) {
+	indexStr, subStr, hasSub := strings.Cut(s, ":")
+
+	index, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(indexStr), "0x"), 16, 16)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid index %q", indexStr)
+	}
+
+	if !hasSub {
+		return uint16(index), 0, nil
+	}
+
+	subIndex, err := strconv.ParseUint(subStr, 0, 8)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid sub index %q", subStr)
+	}
+
+	return uint16(index), uint8(subIndex), nil
+}
+
+// watch call fn with frames matching filter until ctx is done, or duration is elapsed
+func (c *cli) watch(ctx context.Context, duration time.Duration, filter func(*can.Frame) bool, fn func(*can.Frame)) error {
+	if err := c.open(); err != nil {
+		return err
+	}
+
+	if duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+
+	framesChan := c.network.AcquireFramesChan(&filter)
+	defer c.network.ReleaseFramesChan(framesChan.ID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case frm, ok := <-framesChan.C:
+			if !ok {
+				return nil
+			}
+
+			fn(frm)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/angelodlfrtr/go-can"
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+
+// testNode answer expedited SDO requests of a node on a virtual bus
+type testNode struct {
+	sync.Mutex
+
+	transport *canopen.VirtualTransport
+	id        int
+	objects   map[uint32][]byte
+	received  []*can.Frame
+}
+
+func newTestNode(t *testing.T, busName string, id int) *testNode {
+	node := &testNode{
+		transport: canopen.GetVirtualBus(busName).Transport(),
+		id:        id,
+		objects:   map[uint32][]byte{},
+	}
+
+	if err := node.transport.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { node.transport.Close() })
+
+	go func() {
+		for frm := range node.transport.ReadChan() {
+			node.handle(frm)
+		}
+	}()
+
+	return node
+}
+
+func (node *testNode) set(index uint16, subIndex uint8, data []byte) {
+	node.Lock()
+	defer node.Unlock()
+
+	node.objects[uint32(index)<<8|uint32(subIndex)] = data
+}
+
+func (node *testNode) get(index uint16, subIndex uint8) []byte {
+	node.Lock()
+	defer node.Unlock()
+
+	return node.objects[uint32(index)<<8|uint32(subIndex)]
+}
+
+func (node *testNode) send(data [8]byte) {
+	// Give the client time to wait for the response
+	time.Sleep(2 * time.Millisecond)
+	node.transport.Write(&can.Frame{ArbitrationID: uint32(0x580 + node.id), DLC: 8, Data: data})
+}
+
+func (node *testNode) handle(frm *can.Frame) {
+	node.Lock()
+	node.received = append(node.received, frm)
+	node.Unlock()
+
+	if frm.ArbitrationID != uint32(0x600+node.id) {
+		return
+	}
+
+	key := uint32(binary.LittleEndian.Uint16(frm.Data[1:]))<<8 | uint32(frm.Data[3])
+	res := [8]byte{}
+	copy(res[1:4], frm.Data[1:4])
+
+	node.Lock()
+	data, ok := node.objects[key]
+
+	switch frm.Data[0] & 0xE0 {
+	case canopen.SDORequestUpload:
+		if !ok {
+			res[0] = canopen.SDOResponseAbort
+			binary.LittleEndian.PutUint32(res[4:], canopen.SDOAbortObjectNotExist)
+			break
+		}
+
+		res[0] = canopen.SDOResponseUpload | canopen.SDOExpedited | canopen.SDOSizeSpecified | uint8(4-len(data))<<2
+		copy(res[4:], data)
+	case canopen.SDORequestDownload:
+		size := 4 - int((frm.Data[0]>>2)&0x3)
+		node.objects[key] = append([]byte{}, frm.Data[4:4+size]...)
+		res[0] = canopen.SDOResponseDownload
+	}
+	node.Unlock()
+
+	go node.send(res)
+}
+
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	out := &bytes.Buffer{}
+	err := run(context.Background(), append([]string{"-bus", "virtual://" + t.Name(), "-timeout", "50ms"}, args...), out)
+
+	return out.String(), err
+}
+
+func TestSDO(t *testing.T) {
+	node := newTestNode(t, t.Name(), 5)
+	node.set(0x1017, 0, []byte{0xE8, 0x03})
+
+	out, err := runCLI(t, "sdo", "read", "5", "1017")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out != "E8 03\n" {
+		t.Errorf("unexpected raw value %q", out)
+	}
+
+	out, err = runCLI(t, "sdo", "read", "-type", "UNSIGNED16", "5", "1017:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out != "0x03E8\n" {
+		t.Errorf("unexpected typed value %q", out)
+	}
+
+	if _, err := runCLI(t, "sdo", "write", "-type", "UNSIGNED16", "5", "0x1017", "2000"); err != nil {
+		t.Fatal(err)
+	}
+
+	if data := node.get(0x1017, 0); !bytes.Equal(data, []byte{0xD0, 0x07}) {
+		t.Errorf("unexpected written value %X", data)
+	}
+
+	if _, err := runCLI(t, "sdo", "read", "5", "2000"); err == nil || !strings.Contains(err.Error(), "0x06020000") {
+		t.Errorf("expected SDO abort, got %v", err)
+	}
+
+	if _, err := runCLI(t, "sdo", "write", "5", "2000", "1"); err == nil {
+		t.Error("expected unknown data type error")
+	}
+}
+
+func TestNMT(t *testing.T) {
+	node := newTestNode(t, t.Name(), 5)
+
+	if _, err := runCLI(t, "nmt", "start", "all"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runCLI(t, "nmt", "preop", "5"); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+
+	node.Lock()
+	defer node.Unlock()
+
+	expected := [][2]byte{{0x01, 0x00}, {0x80, 0x05}}
+	if len(node.received) != len(expected) {
+		t.Fatalf("expected %d NMT frames, got %d", len(expected), len(node.received))
+	}
+
+	for i, frm := range node.received {
+		if frm.ArbitrationID != 0 || frm.Data[0] != expected[i][0] || frm.Data[1] != expected[i][1] {
+			t.Errorf("unexpected NMT frame %v", frm)
+		}
+	}
+}
+
+func TestHeartbeatWatch(t *testing.T) {
+	node := newTestNode(t, t.Name(), 5)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		node.transport.Write(&can.Frame{ArbitrationID: 0x705, DLC: 1, Data: [8]byte{0x05}})
+		time.Sleep(5 * time.Millisecond)
+		node.transport.Write(&can.Frame{ArbitrationID: 0x085, DLC: 8, Data: [8]byte{0x10, 0x42, 0x11}})
+	}()
+
+	out := &bytes.Buffer{}
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+
+	if err := run(ctx, []string{"-bus", "virtual://" + t.Name(), "heartbeat", "watch"}, out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasSuffix(out.String(), "node 5 OPERATIONAL\n") {
+		t.Errorf("unexpected heartbeat output %q", out)
+	}
+}
+
+func TestParseObjectAddress(t *testing.T) {
+	for s, expected := range map[string][2]int{
+		"6041":     {0x6041, 0},
+		"0x1018:1": {0x1018, 1},
+		"1A00:0x2": {0x1A00, 2},
+	} {
+		index, subIndex, err := parseObjectAddress(s)
+		if err != nil || int(index) != expected[0] || int(subIndex) != expected[1] {
+			t.Errorf("parseObjectAddress(%q): got 0x%04X %d %v", s, index, subIndex, err)
+		}
+	}
+
+	if _, _, err := parseObjectAddress("xyz"); err == nil {
+		t.Error("expected invalid index error")
+	}
+}
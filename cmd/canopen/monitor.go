@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+
+// monitor print decoded bus traffic
+func (c *cli) monitor(ctx context.Context, args []string) error {
+	flags := c.subFlags("monitor")
+	formatName := flags.String("format", "table", "output format: table, json or candump")
+	duration := flags.Duration("duration", 0, "stop after duration, default to never")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 0 {
+		return fmt.Errorf("usage: monitor [-format f] [-duration d]: %w", errUsage)
+	}
+
+	format, err := canopen.ParseMonitorFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	if err := c.open(); err != nil {
+		return err
+	}
+
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	monitor := canopen.NewMonitor(c.network)
+	monitor.DefaultObjectDic = c.objectDic
+	writer := canopen.NewMonitorWriter(c.out, format)
+
+	return monitor.Run(ctx, func(event *canopen.MonitorEvent) {
+		writer.Write(event)
+	})
+}
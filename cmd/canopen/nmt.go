@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/angelodlfrtr/go-can"
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+
+// nmtActions map nmt sub commands to NMT commands
+var nmtActions = map[string]string{
+	"start":      "OPERATIONAL",
+	"stop":       "STOPPED",
+	"preop":      "PRE-OPERATIONAL",
+	"reset":      "RESET",
+	"reset-comm": "RESET COMMUNICATION",
+}
+
+// scan list nodes answering on the bus
+func (c *cli) scan(ctx context.Context, args []string) error {
+	flags := c.subFlags("scan")
+	limit := flags.Int("limit", 127, "highest node id to probe")
+	wait := flags.Duration("wait", time.Second, "time to wait for responses")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := c.open(); err != nil {
+		return err
+	}
+
+	nodes, err := c.network.Search(*limit, *wait)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.ID)
+	}
+
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		fmt.Fprintln(c.out, id)
+	}
+
+	return nil
+}
+
+// nmt send an NMT command to a node, or to all nodes
+func (c *cli) nmt(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: nmt start|stop|preop|reset|reset-comm node|all: %w", errUsage)
+	}
+
+	command, ok := nmtActions[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown nmt command %q: %w", args[0], errUsage)
+	}
+
+	nodeID := 0
+	if args[1] != "all" {
+		id, err := parseNodeID(args[1])
+		if err != nil {
+			return err
+		}
+
+		nodeID = id
+	}
+
+	if err := c.open(); err != nil {
+		return err
+	}
+
+	return canopen.NewNMTMaster(nodeID, c.network).SetState(command)
+}
+
+// heartbeat print heartbeats and node states
+func (c *cli) heartbeat(ctx context.Context, args []string) error {
+	flags := c.subFlags("heartbeat")
+	duration := flags.Duration("duration", 0, "stop after duration, default to never")
+
+	if len(args) == 0 || args[0] != "watch" {
+		return fmt.Errorf("usage: heartbeat watch: %w", errUsage)
+	}
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	filter := func(frm *can.Frame) bool {
+		return frm.ArbitrationID > 0x700 && frm.ArbitrationID <= 0x77F && frm.DLC >= 1
+	}
+
+	return c.watch(ctx, *duration, filter, func(frm *can.Frame) {
+		state := int(frm.Data[0] & 0x7F)
+
+		name, ok := canopen.NMTStates[state]
+		switch {
+		case state == 0:
+			name = "BOOT-UP"
+		case !ok:
+			name = fmt.Sprintf("UNKNOWN 0x%02X", state)
+		}
+
+		c.printf("node %d %s", frm.ArbitrationID&0x7F, name)
+	})
+}
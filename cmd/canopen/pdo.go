@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+
+// pdoKinds are the PDO communication and mapping parameters offsets
+var pdoKinds = []struct {
+	name    string
+	com     uint16
+	mapping uint16
+}{
+	{"RPDO", 0x1400, 0x1600},
+	{"TPDO", 0x1800, 0x1A00},
+}
+
+// pdo print the PDO configuration of a node, read with SDO
+func (c *cli) pdo(ctx context.Context, args []string) error {
+	if len(args) != 2 || args[0] != "dump" {
+		return fmt.Errorf("usage: pdo dump node: %w", errUsage)
+	}
+
+	nodeID, err := parseNodeID(args[1])
+	if err != nil {
+		return err
+	}
+
+	if err := c.open(); err != nil {
+		return err
+	}
+
+	sdoClient := c.node(nodeID).SDOClient
+	// Parameters are read whatever their EDS access type
+	sdoClient.Strict = false
+
+	for _, kind := range pdoKinds {
+		for n := uint16(0); n < 0x200; n++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			// Without EDS, PDOs are expected to be numbered contiguously
+			if c.objectDic != nil && c.objectDic.FindIndex(kind.com+n) == nil {
+				continue
+			}
+
+			done, err := c.dumpPDO(sdoClient, kind.name, n+1, kind.com+n, kind.mapping+n)
+			if err != nil {
+				return err
+			}
+
+			if done && c.objectDic == nil {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// dumpPDO print a PDO configuration, return true if the PDO does not exist
+func (c *cli) dumpPDO(sdoClient *canopen.SDOClient, name string, number, com, mapping uint16) (bool, error) {
+	cobIDData, err := sdoClient.Read(com, 1)
+	if err != nil {
+		var abortErr *canopen.SDOAbortError
+		if errors.As(err, &abortErr) {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	cobID := readUint(cobIDData)
+	state := "enabled"
+	if cobID&(1<<31) != 0 {
+		state = "disabled"
+	}
+
+	line := fmt.Sprintf("%s%d cob-id 0x%03X %s", name, number, cobID&0x1FFFFFFF, state)
+
+	if data, err := sdoClient.Read(com, 2); err == nil {
+		line += fmt.Sprintf(" transmission-type %d", readUint(data))
+	}
+
+	if data, err := sdoClient.Read(com, 5); err == nil && readUint(data) != 0 {
+		line += fmt.Sprintf(" event-timer %dms", readUint(data))
+	}
+
+	fmt.Fprintln(c.out, line)
+
+	countData, err := sdoClient.Read(mapping, 0)
+	if err != nil {
+		return false, nil
+	}
+
+	for sub := 1; sub <= int(readUint(countData)); sub++ {
+		data, err := sdoClient.Read(mapping, uint8(sub))
+		if err != nil {
+			fmt.Fprintf(c.out, "  %d: %v\n", sub, err)
+			continue
+		}
+
+		entry := readUint(data)
+		index, subIndex, bits := uint16(entry>>16), uint8(entry>>8), uint8(entry)
+
+		objectName := ""
+		if c.objectDic != nil {
+			if variable := c.objectDic.FindVariable(index, subIndex); variable != nil {
+				objectName = " " + variable.Name
+			}
+		}
+
+		fmt.Fprintf(c.out, "  %04X:%d %d bits%s\n", index, subIndex, bits, objectName)
+	}
+
+	return false, nil
+}
+
+// readUint decode an unsigned little endian value of up to 4 bytes
+func readUint(data []byte) uint32 {
+	buf := make([]byte, 4)
+	copy(buf, data)
+
+	return binary.LittleEndian.Uint32(buf)
+}
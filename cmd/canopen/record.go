@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+
+// record write bus traffic to a log file, or to the output with -
+func (c *cli) record(ctx context.Context, args []string) error {
+	flags := c.subFlags("record")
+	formatName := flags.String("format", "", "log format: candump or asc, default from the file extension")
+	duration := flags.Duration("duration", 0, "stop after duration, default to never")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: record [-format f] [-duration d] file: %w", errUsage)
+	}
+
+	path := flags.Arg(0)
+	format := canopen.RecordFormatFromPath(path)
+
+	if *formatName != "" {
+		f, err := canopen.ParseRecordFormat(*formatName)
+		if err != nil {
+			return err
+		}
+
+		format = f
+	}
+
+	var w io.Writer = c.out
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+		w = file
+	}
+
+	if err := c.open(); err != nil {
+		return err
+	}
+
+	if *duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *duration)
+		defer cancel()
+	}
+
+	recorder := canopen.NewRecorder(c.network, w, format)
+	if err := recorder.Start(); err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+
+	return recorder.Stop()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	canopen "github.com/angelodlfrtr/go-canopen"
+)
+
+// sdo read and write objects
+func (c *cli) sdo(ctx context.Context, args []string) error {
+	flags := c.subFlags("sdo")
+	dataTypeName := flags.String("type", "", "data type of the object, eg: UNSIGNED16, default to the EDS one")
+
+	if len(args) == 0 {
+		return fmt.Errorf("missing sdo read or write: %w", errUsage)
+	}
+
+	action := args[0]
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch {
+	case action == "read" && flags.NArg() == 2:
+	case action == "write" && flags.NArg() == 3:
+	default:
+		return fmt.Errorf("usage: sdo read node index[:sub], sdo write node index[:sub] value: %w", errUsage)
+	}
+
+	nodeID, err := parseNodeID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	index, subIndex, err := parseObjectAddress(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	// Data type from -type, else from the object dictionary. 0 means raw data
+	var dataType byte
+	var variable *canopen.DicVariable
+
+	if c.objectDic != nil {
+		variable = c.objectDic.FindVariable(index, subIndex)
+		if variable != nil {
+			dataType = variable.DataType
+		}
+	}
+
+	if *dataTypeName != "" {
+		if dataType, err = canopen.ParseDataType(*dataTypeName); err != nil {
+			return err
+		}
+	}
+
+	if err := c.open(); err != nil {
+		return err
+	}
+
+	sdoClient := c.node(nodeID).SDOClient
+
+	if action == "read" {
+		data, err := sdoClient.Read(index, subIndex)
+		if err != nil {
+			return err
+		}
+
+		if dataType == 0 {
+			fmt.Fprintf(c.out, "% X\n", data)
+			return nil
+		}
+
+		value := canopen.DicFormatValue(dataType, data)
+		if variable != nil && variable.Unit != "" && *dataTypeName == "" {
+			value += " " + variable.Unit
+		}
+
+		fmt.Fprintln(c.out, value)
+		return nil
+	}
+
+	if dataType == 0 {
+		return fmt.Errorf("unknown data type of 0x%04X sub %d, use -type or -eds", index, subIndex)
+	}
+
+	data, err := canopen.DicEncodeValue(dataType, flags.Arg(2), nodeID)
+	if err != nil {
+		return err
+	}
+
+	if variable != nil && dataType == variable.DataType {
+		if err := variable.Validate(data); err != nil {
+			return err
+		}
+	}
+
+	return sdoClient.Write(index, subIndex, false, data)
+}
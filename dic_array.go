@@ -1,5 +1,7 @@
 package canopen
 
+import "time"
+
 type DicArray struct {
 	Description string
 	Index       uint16
@@ -52,28 +54,34 @@ func (array *DicArray) FindName(name string) DicObject {
 	return nil
 }
 
-func (array *DicArray) GetDataType() byte     { return 0x00 }
-func (array *DicArray) GetDataLen() int       { return 0 }
-func (array *DicArray) SetSize(s int)         {}
-func (array *DicArray) SetOffset(s int)       {}
-func (array *DicArray) GetOffset() int        { return 0 }
-func (array *DicArray) Read() error           { return nil }
-func (array *DicArray) Save() error           { return nil }
-func (array *DicArray) GetData() []byte       { return nil }
-func (array *DicArray) SetData(data []byte)   {}
-func (array *DicArray) GetStringVal() *string { return nil }
-func (array *DicArray) GetFloatVal() *float64 { return nil }
-func (array *DicArray) GetUintVal() *uint64   { return nil }
-func (array *DicArray) GetIntVal() *int64     { return nil }
-func (array *DicArray) GetBoolVal() *bool     { return nil }
-func (array *DicArray) GetByteVal() *byte     { return nil }
-func (array *DicArray) SetStringVal(a string) {}
-func (array *DicArray) SetFloatVal(a float64) {}
-func (array *DicArray) SetUintVal(a uint64)   {}
-func (array *DicArray) SetIntVal(a int64)     {}
-func (array *DicArray) SetBoolVal(a bool)     {}
-func (array *DicArray) SetByteVal(a byte)     {}
-func (array *DicArray) IsDicVariable() bool   { return false }
+func (array *DicArray) GetDataType() byte                    { return 0x00 }
+func (array *DicArray) GetDataLen() int                      { return 0 }
+func (array *DicArray) SetSize(s int)                        {}
+func (array *DicArray) SetOffset(s int)                      {}
+func (array *DicArray) GetOffset() int                       { return 0 }
+func (array *DicArray) Read() error                          { return nil }
+func (array *DicArray) Save() error                          { return nil }
+func (array *DicArray) GetData() []byte                      { return nil }
+func (array *DicArray) SetData(data []byte)                  {}
+func (array *DicArray) GetStringVal() *string                { return nil }
+func (array *DicArray) GetFloatVal() *float64                { return nil }
+func (array *DicArray) GetUintVal() *uint64                  { return nil }
+func (array *DicArray) GetIntVal() *int64                    { return nil }
+func (array *DicArray) GetBoolVal() *bool                    { return nil }
+func (array *DicArray) GetByteVal() *byte                    { return nil }
+func (array *DicArray) GetTimeVal() *time.Time               { return nil }
+func (array *DicArray) GetDurationVal() *time.Duration       { return nil }
+func (array *DicArray) SetStringVal(a string) error          { return errDicNotVariable }
+func (array *DicArray) SetFloatVal(a float64) error          { return errDicNotVariable }
+func (array *DicArray) SetUintVal(a uint64) error            { return errDicNotVariable }
+func (array *DicArray) SetIntVal(a int64) error              { return errDicNotVariable }
+func (array *DicArray) SetBoolVal(a bool) error              { return errDicNotVariable }
+func (array *DicArray) SetByteVal(a byte) error              { return errDicNotVariable }
+func (array *DicArray) SetTimeVal(a time.Time) error         { return errDicNotVariable }
+func (array *DicArray) SetDurationVal(a time.Duration) error { return errDicNotVariable }
+func (array *DicArray) Value() (any, error)                  { return nil, errDicNotVariable }
+func (array *DicArray) SetValue(a any) error                 { return errDicNotVariable }
+func (array *DicArray) IsDicVariable() bool                  { return false }
 
 func (array *DicArray) SetSDO(sdo *SDOClient) {
 	array.SDOClient = sdo
@@ -0,0 +1,243 @@
+package canopen
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf16"
+)
+
+// dicTimeEpoch is the TIME_OF_DAY reference date
+var dicTimeEpoch = time.Date(1984, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+const dicDayMs = 24 * 60 * 60 * 1000
+
+// errDicDataLen is returned when data is too short for its data type
+var errDicDataLen = errors.New("data too short for data type")
+
+// dicTypeSize return the size of a fixed size dataType, or an error
+func dicTypeSize(dataType byte) (int, error) {
+	size := DataTypeSize(dataType)
+	if size == 0 {
+		return 0, fmt.Errorf("data type 0x%X has no fixed size", dataType)
+	}
+
+	return size, nil
+}
+
+// decodeDicUint decode an unsigned little endian integer of dataType size
+func decodeDicUint(dataType byte, data []byte) (uint64, error) {
+	size, err := dicTypeSize(dataType)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(data) < size {
+		return 0, errDicDataLen
+	}
+
+	buf := make([]byte, 8)
+	copy(buf, data[:size])
+
+	return binary.LittleEndian.Uint64(buf), nil
+}
+
+// decodeDicInt decode a signed little endian integer of dataType size, with sign extension
+func decodeDicInt(dataType byte, data []byte) (int64, error) {
+	v, err := decodeDicUint(dataType, data)
+	if err != nil {
+		return 0, err
+	}
+
+	if bits := uint(DataTypeSize(dataType)) * 8; bits < 64 {
+		shift := 64 - bits
+		return int64(v<<shift) >> shift, nil
+	}
+
+	return int64(v), nil
+}
+
+// encodeDicUint encode v as an integer of dataType, returning an error if v is out of range
+func encodeDicUint(dataType byte, v uint64) ([]byte, error) {
+	size, err := dicTypeSize(dataType)
+	if err != nil {
+		return nil, err
+	}
+
+	max := uint64(math.MaxUint64)
+	if IsSignedType(dataType) {
+		max = uint64(1)<<(uint(size)*8-1) - 1
+	} else if size < 8 {
+		max = uint64(1)<<(uint(size)*8) - 1
+	}
+
+	if v > max {
+		return nil, fmt.Errorf("value %d out of range for data type 0x%X", v, dataType)
+	}
+
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, v)
+
+	return buf[:size], nil
+}
+
+// encodeDicInt encode v as an integer of dataType, returning an error if v is out of range
+func encodeDicInt(dataType byte, v int64) ([]byte, error) {
+	if !IsSignedType(dataType) {
+		if v < 0 {
+			return nil, fmt.Errorf("value %d out of range for data type 0x%X", v, dataType)
+		}
+
+		return encodeDicUint(dataType, uint64(v))
+	}
+
+	size, err := dicTypeSize(dataType)
+	if err != nil {
+		return nil, err
+	}
+
+	if bits := uint(size) * 8; bits < 64 {
+		min, max := -int64(1)<<(bits-1), int64(1)<<(bits-1)-1
+		if v < min || v > max {
+			return nil, fmt.Errorf("value %d out of range for data type 0x%X", v, dataType)
+		}
+	}
+
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, uint64(v))
+
+	return buf[:size], nil
+}
+
+// decodeDicFloat decode a REAL32 or REAL64
+func decodeDicFloat(dataType byte, data []byte) (float64, error) {
+	switch dataType {
+	case Real32:
+		if len(data) < 4 {
+			return 0, errDicDataLen
+		}
+
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(data))), nil
+	case Real64:
+		if len(data) < 8 {
+			return 0, errDicDataLen
+		}
+
+		return math.Float64frombits(binary.LittleEndian.Uint64(data)), nil
+	}
+
+	return 0, fmt.Errorf("data type 0x%X is not a float type", dataType)
+}
+
+// encodeDicFloat encode v as a REAL32 or REAL64, returning an error if v is out of range
+func encodeDicFloat(dataType byte, v float64) ([]byte, error) {
+	switch dataType {
+	case Real32:
+		if !math.IsInf(v, 0) && !math.IsNaN(v) && math.Abs(v) > math.MaxFloat32 {
+			return nil, fmt.Errorf("value %g out of range for REAL32", v)
+		}
+
+		data := make([]byte, 4)
+		binary.LittleEndian.PutUint32(data, math.Float32bits(float32(v)))
+
+		return data, nil
+	case Real64:
+		data := make([]byte, 8)
+		binary.LittleEndian.PutUint64(data, math.Float64bits(v))
+
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("data type 0x%X is not a float type", dataType)
+}
+
+// decodeDicUnicode decode an UTF-16 little endian UNICODE_STRING
+func decodeDicUnicode(data []byte) string {
+	src := make([]uint16, len(data)/2)
+	for i := range src {
+		src[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+
+	return string(utf16.Decode(src))
+}
+
+// encodeDicUnicode encode s as an UTF-16 little endian UNICODE_STRING
+func encodeDicUnicode(s string) []byte {
+	src := utf16.Encode([]rune(s))
+	data := make([]byte, len(src)*2)
+	for i, c := range src {
+		binary.LittleEndian.PutUint16(data[i*2:], c)
+	}
+
+	return data
+}
+
+// decodeDicTimeParts decode ms and days of a TIME_OF_DAY or TIME_DIFFERENCE
+func decodeDicTimeParts(data []byte) (ms uint32, days uint16, err error) {
+	if len(data) < 6 {
+		return 0, 0, errDicDataLen
+	}
+
+	// Upper 4 bits of ms are reserved
+	ms = binary.LittleEndian.Uint32(data) & 0x0FFFFFFF
+	days = binary.LittleEndian.Uint16(data[4:])
+
+	return ms, days, nil
+}
+
+// encodeDicTimeParts encode ms and days of a TIME_OF_DAY or TIME_DIFFERENCE
+func encodeDicTimeParts(ms int64, days int64) ([]byte, error) {
+	if days < 0 || days > math.MaxUint16 {
+		return nil, fmt.Errorf("days %d out of range", days)
+	}
+
+	data := make([]byte, 6)
+	binary.LittleEndian.PutUint32(data, uint32(ms))
+	binary.LittleEndian.PutUint16(data[4:], uint16(days))
+
+	return data, nil
+}
+
+// decodeDicTime decode a TIME_OF_DAY, ms after midnight and days since January 1, 1984
+func decodeDicTime(data []byte) (time.Time, error) {
+	ms, days, err := decodeDicTimeParts(data)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return dicTimeEpoch.AddDate(0, 0, int(days)).Add(time.Duration(ms) * time.Millisecond), nil
+}
+
+// encodeDicTime encode t as a TIME_OF_DAY
+func encodeDicTime(t time.Time) ([]byte, error) {
+	if t.Before(dicTimeEpoch) {
+		return nil, fmt.Errorf("time %s before TIME_OF_DAY epoch", t)
+	}
+
+	ms := t.Sub(dicTimeEpoch).Milliseconds()
+
+	return encodeDicTimeParts(ms%dicDayMs, ms/dicDayMs)
+}
+
+// decodeDicDuration decode a TIME_DIFFERENCE
+func decodeDicDuration(data []byte) (time.Duration, error) {
+	ms, days, err := decodeDicTimeParts(data)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(days)*24*time.Hour + time.Duration(ms)*time.Millisecond, nil
+}
+
+// encodeDicDuration encode d as a TIME_DIFFERENCE
+func encodeDicDuration(d time.Duration) ([]byte, error) {
+	if d < 0 {
+		return nil, fmt.Errorf("negative duration %s", d)
+	}
+
+	ms := d.Milliseconds()
+
+	return encodeDicTimeParts(ms%dicDayMs, ms/dicDayMs)
+}
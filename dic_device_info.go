@@ -0,0 +1,51 @@
+package canopen
+
+// DicFileInfo contain an EDS / DCF [FileInfo] section
+type DicFileInfo struct {
+	FileName         string `json:"fileName,omitempty" yaml:"fileName,omitempty"`
+	FileVersion      int    `json:"fileVersion,omitempty" yaml:"fileVersion,omitempty"`
+	FileRevision     int    `json:"fileRevision,omitempty" yaml:"fileRevision,omitempty"`
+	EDSVersion       string `json:"edsVersion,omitempty" yaml:"edsVersion,omitempty"`
+	Description      string `json:"description,omitempty" yaml:"description,omitempty"`
+	CreationTime     string `json:"creationTime,omitempty" yaml:"creationTime,omitempty"`
+	CreationDate     string `json:"creationDate,omitempty" yaml:"creationDate,omitempty"`
+	CreatedBy        string `json:"createdBy,omitempty" yaml:"createdBy,omitempty"`
+	ModificationTime string `json:"modificationTime,omitempty" yaml:"modificationTime,omitempty"`
+	ModificationDate string `json:"modificationDate,omitempty" yaml:"modificationDate,omitempty"`
+	ModifiedBy       string `json:"modifiedBy,omitempty" yaml:"modifiedBy,omitempty"`
+}
+
+// DicDeviceInfo contain an EDS / DCF [DeviceInfo] section
+type DicDeviceInfo struct {
+	VendorName     string `json:"vendorName,omitempty" yaml:"vendorName,omitempty"`
+	VendorNumber   uint32 `json:"vendorNumber,omitempty" yaml:"vendorNumber,omitempty"`
+	ProductName    string `json:"productName,omitempty" yaml:"productName,omitempty"`
+	ProductNumber  uint32 `json:"productNumber,omitempty" yaml:"productNumber,omitempty"`
+	RevisionNumber uint32 `json:"revisionNumber,omitempty" yaml:"revisionNumber,omitempty"`
+	OrderCode      string `json:"orderCode,omitempty" yaml:"orderCode,omitempty"`
+
+	// BaudRates contain supported baud rates in kbit/s
+	BaudRates []int `json:"baudRates,omitempty" yaml:"baudRates,omitempty"`
+
+	SimpleBootUpMaster bool `json:"simpleBootUpMaster,omitempty" yaml:"simpleBootUpMaster,omitempty"`
+	SimpleBootUpSlave  bool `json:"simpleBootUpSlave,omitempty" yaml:"simpleBootUpSlave,omitempty"`
+	Granularity        int  `json:"granularity,omitempty" yaml:"granularity,omitempty"`
+	GroupMessaging     bool `json:"groupMessaging,omitempty" yaml:"groupMessaging,omitempty"`
+	NrOfRXPDO          int  `json:"nrOfRXPDO,omitempty" yaml:"nrOfRXPDO,omitempty"`
+	NrOfTXPDO          int  `json:"nrOfTXPDO,omitempty" yaml:"nrOfTXPDO,omitempty"`
+	LSSSupported       bool `json:"lssSupported,omitempty" yaml:"lssSupported,omitempty"`
+}
+
+// DicStandardBaudRates contain baud rates (in kbit/s) an EDS [DeviceInfo] section can declare
+var DicStandardBaudRates = []int{10, 20, 50, 125, 250, 500, 800, 1000}
+
+// SupportBaudRate return true if baudRate (in kbit/s) is in deviceInfo.BaudRates
+func (deviceInfo *DicDeviceInfo) SupportBaudRate(baudRate int) bool {
+	for _, b := range deviceInfo.BaudRates {
+		if b == baudRate {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,206 @@
+package canopen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DicDiffKind is the kind of a DicDifference
+type DicDiffKind int
+
+// Kinds of differences
+const (
+	DicDiffAdded DicDiffKind = iota
+	DicDiffRemoved
+	DicDiffDataType
+	DicDiffAccessType
+	DicDiffDefault
+	DicDiffValue
+)
+
+var dicDiffKindNames = map[DicDiffKind]string{
+	DicDiffAdded:      "added",
+	DicDiffRemoved:    "removed",
+	DicDiffDataType:   "data type",
+	DicDiffAccessType: "access type",
+	DicDiffDefault:    "default value",
+	DicDiffValue:      "parameter value",
+}
+
+func (kind DicDiffKind) String() string {
+	return dicDiffKindNames[kind]
+}
+
+// DicDifference is a difference of an object between two dictionaries. Old and
+// New are in EDS notation, and empty for added and removed objects
+type DicDifference struct {
+	Index    uint16
+	SubIndex uint8
+	Name     string
+	Kind     DicDiffKind
+	Old      string
+	New      string
+}
+
+func (d DicDifference) String() string {
+	switch d.Kind {
+	case DicDiffAdded:
+		return fmt.Sprintf("+ 0x%04X sub %d %s", d.Index, d.SubIndex, d.Name)
+	case DicDiffRemoved:
+		return fmt.Sprintf("- 0x%04X sub %d %s", d.Index, d.SubIndex, d.Name)
+	}
+
+	return fmt.Sprintf("~ 0x%04X sub %d %s: %s %q -> %q", d.Index, d.SubIndex, d.Name, d.Kind, d.Old, d.New)
+}
+
+// DicDiff is a list of differences ordered by index and sub index
+type DicDiff []DicDifference
+
+func (diff DicDiff) String() string {
+	b := &strings.Builder{}
+	for _, d := range diff {
+		b.WriteString(d.String())
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func dicDiffKey(index uint16, subIndex uint8) uint32 {
+	return uint32(index)<<8 | uint32(subIndex)
+}
+
+// dicVariablesMap return objectDic variables by index and sub index
+func dicVariablesMap(objectDic *DicObjectDic) map[uint32]*DicVariable {
+	variables := map[uint32]*DicVariable{}
+	for _, variable := range objectDic.Variables() {
+		variables[dicDiffKey(variable.Index, variable.SubIndex)] = variable
+	}
+
+	return variables
+}
+
+// DicCompare return differences from dictionary from to dictionary to: added and
+// removed variables, and data type, access type, default value and parameter value changes
+func DicCompare(from, to *DicObjectDic) DicDiff {
+	oldVariables := dicVariablesMap(from)
+	newVariables := dicVariablesMap(to)
+
+	keys := []uint32{}
+	for key := range oldVariables {
+		keys = append(keys, key)
+	}
+
+	for key := range newVariables {
+		if _, ok := oldVariables[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	diff := DicDiff{}
+
+	for _, key := range keys {
+		o, n := oldVariables[key], newVariables[key]
+
+		switch {
+		case o == nil:
+			diff = append(diff, DicDifference{Index: n.Index, SubIndex: n.SubIndex, Name: n.Name, Kind: DicDiffAdded})
+			continue
+		case n == nil:
+			diff = append(diff, DicDifference{Index: o.Index, SubIndex: o.SubIndex, Name: o.Name, Kind: DicDiffRemoved})
+			continue
+		}
+
+		change := func(kind DicDiffKind, oldValue, newValue string) {
+			diff = append(diff, DicDifference{
+				Index:    n.Index,
+				SubIndex: n.SubIndex,
+				Name:     n.Name,
+				Kind:     kind,
+				Old:      oldValue,
+				New:      newValue,
+			})
+		}
+
+		if o.DataType != n.DataType {
+			change(DicDiffDataType, fmt.Sprintf("0x%04X", o.DataType), fmt.Sprintf("0x%04X", n.DataType))
+		}
+
+		if o.AccessType != n.AccessType {
+			change(DicDiffAccessType, o.AccessType, n.AccessType)
+		}
+
+		if !bytes.Equal(o.Default, n.Default) {
+			change(DicDiffDefault, formatEDSValue(o.DataType, o.Default), formatEDSValue(n.DataType, n.Default))
+		}
+
+		if !bytes.Equal(o.Data, n.Data) {
+			change(DicDiffValue, formatEDSValue(o.DataType, o.Data), formatEDSValue(n.DataType, n.Data))
+		}
+	}
+
+	return diff
+}
+
+// DicCompareNode read with SDO every readable variable of objectDic from node,
+// and return differences from objectDic parameter values, or default values for
+// variables without parameter value. $NODEID default values are evaluated for
+// node.ID, objectDic being left unchanged. Variables missing on node are reported
+// as removed, and variables aborting the read for another reason are ignored
+func DicCompareNode(objectDic *DicObjectDic, node *Node) (DicDiff, error) {
+	sdoClient := node.sdo()
+	diff := DicDiff{}
+
+	objectDic = objectDic.Copy()
+	objectDic.SetNodeID(node.ID)
+
+	for _, variable := range objectDic.Variables() {
+		if !variable.IsReadable() || variable.DataType == Domain {
+			continue
+		}
+
+		data, err := sdoClient.Read(variable.Index, variable.SubIndex)
+		if err != nil {
+			var abortErr *SDOAbortError
+			if !errors.As(err, &abortErr) {
+				return diff, err
+			}
+
+			if isSDOAbortNotFound(err) {
+				diff = append(diff, DicDifference{
+					Index:    variable.Index,
+					SubIndex: variable.SubIndex,
+					Name:     variable.Name,
+					Kind:     DicDiffRemoved,
+				})
+			}
+
+			continue
+		}
+
+		expected := variable.Data
+		if expected == nil {
+			expected = variable.Default
+		}
+
+		if expected == nil || bytes.Equal(expected, data) {
+			continue
+		}
+
+		diff = append(diff, DicDifference{
+			Index:    variable.Index,
+			SubIndex: variable.SubIndex,
+			Name:     variable.Name,
+			Kind:     DicDiffValue,
+			Old:      formatEDSValue(variable.DataType, expected),
+			New:      formatEDSValue(variable.DataType, data),
+		})
+	}
+
+	return diff, nil
+}
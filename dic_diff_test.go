@@ -0,0 +1,96 @@
+package canopen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDicCompare(t *testing.T) {
+	from := newTestObjectDic()
+	to := newTestObjectDic()
+
+	to.FindVariable(0x2000, 0).Data = []byte{0x0A, 0x00}
+	to.FindVariable(0x2000, 0).AccessType = "ro"
+	to.FindVariable(0x1000, 0).DataType = Integer32
+	delete(to.FindIndex(0x1018).(*DicRecord).SubIndexes, 1)
+	to.AddObject(&DicVariable{Index: 0x2001, Name: "Mode", DataType: Unsigned8, AccessType: "rw"})
+
+	diff := DicCompare(from, to)
+
+	expected := []struct {
+		index uint16
+		kind  DicDiffKind
+		old   string
+		new   string
+	}{
+		{0x1000, DicDiffDataType, "0x0007", "0x0004"},
+		{0x1018, DicDiffRemoved, "", ""},
+		{0x2000, DicDiffAccessType, "rw", "ro"},
+		{0x2000, DicDiffValue, "-10", "10"},
+		{0x2001, DicDiffAdded, "", ""},
+	}
+
+	if len(diff) != len(expected) {
+		t.Fatalf("unexpected diff\n%s", diff)
+	}
+
+	for i, e := range expected {
+		d := diff[i]
+		if d.Index != e.index || d.Kind != e.kind || d.Old != e.old || d.New != e.new {
+			t.Errorf("unexpected difference %d: %s", i, d)
+		}
+	}
+
+	if !strings.Contains(diff.String(), `~ 0x2000 sub 0 Temperature offset: parameter value "-10" -> "10"`) {
+		t.Errorf("unexpected diff text\n%s", diff)
+	}
+
+	if len(DicCompare(from, newTestObjectDic())) != 0 {
+		t.Error("expected no difference")
+	}
+}
+
+func TestDicCompareNode(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 11)
+	server.Set(0x1000, 0, []byte{0x91, 0x01, 0x00, 0x00})
+	server.Set(0x1018, 0, []byte{0x01})
+	server.Set(0x2000, 0, []byte{0x05, 0x00})
+
+	diff, err := DicCompareNode(newTestObjectDic(), NewNode(11, network, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "- 0x1018 sub 1 Vendor-ID\n~ 0x2000 sub 0 Temperature offset: parameter value \"-10\" -> \"5\"\n"
+	if diff.String() != expected {
+		t.Errorf("unexpected diff\n%s", diff)
+	}
+}
+
+func TestDicCompareNodeNodeIDDefault(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 11)
+	server.Set(0x1800, 1, []byte{0x8B, 0x01, 0x00, 0x00})
+
+	objectDic, err := DicEDSParse([]byte("[1800]\nParameterName=TPDO 1 communication parameter\nObjectType=0x9\nSubNumber=0x2\n\n" +
+		"[1800sub1]\nParameterName=COB-ID\nObjectType=0x7\nDataType=0x0007\nAccessType=rw\nDefaultValue=$NODEID+0x180\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := DicCompareNode(objectDic, NewNode(11, network, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diff) != 0 {
+		t.Errorf("unexpected diff\n%s", diff)
+	}
+
+	// objectDic is left unchanged
+	if data := objectDic.FindVariable(0x1800, 1).Default; !bytes.Equal(data, []byte{0x80, 0x01, 0x00, 0x00}) {
+		t.Errorf("unexpected default % X", data)
+	}
+}
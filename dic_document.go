@@ -0,0 +1,316 @@
+package canopen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dicDocument is the JSON and YAML representation of a DicObjectDic
+type dicDocument struct {
+	NodeID     int             `json:"nodeId,omitempty" yaml:"nodeId,omitempty"`
+	Baudrate   int             `json:"baudrate,omitempty" yaml:"baudrate,omitempty"`
+	FileInfo   *DicFileInfo    `json:"fileInfo,omitempty" yaml:"fileInfo,omitempty"`
+	DeviceInfo *DicDeviceInfo  `json:"deviceInfo,omitempty" yaml:"deviceInfo,omitempty"`
+	Objects    []*dicDocObject `json:"objects" yaml:"objects"`
+}
+
+// dicDocObject is a variable, array or record. Indexes are hex strings, data
+// types are CiA 301 names, and values are in EDS notation
+type dicDocObject struct {
+	Index       string `json:"index,omitempty" yaml:"index,omitempty"`
+	SubIndex    uint8  `json:"subIndex,omitempty" yaml:"subIndex,omitempty"`
+	Name        string `json:"name" yaml:"name"`
+	ObjectType  string `json:"objectType,omitempty" yaml:"objectType,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	DataType   string `json:"dataType,omitempty" yaml:"dataType,omitempty"`
+	AccessType string `json:"accessType,omitempty" yaml:"accessType,omitempty"`
+	PDOMapping bool   `json:"pdoMapping,omitempty" yaml:"pdoMapping,omitempty"`
+	LowLimit   string `json:"lowLimit,omitempty" yaml:"lowLimit,omitempty"`
+	HighLimit  string `json:"highLimit,omitempty" yaml:"highLimit,omitempty"`
+	Default    string `json:"default,omitempty" yaml:"default,omitempty"`
+	Value      string `json:"value,omitempty" yaml:"value,omitempty"`
+
+	Unit        string  `json:"unit,omitempty" yaml:"unit,omitempty"`
+	Factor      float64 `json:"factor,omitempty" yaml:"factor,omitempty"`
+	ScaleOffset float64 `json:"scaleOffset,omitempty" yaml:"scaleOffset,omitempty"`
+
+	ValueDescriptions map[int64]string `json:"valueDescriptions,omitempty" yaml:"valueDescriptions,omitempty"`
+	BitDefinitions    map[string][]int `json:"bitDefinitions,omitempty" yaml:"bitDefinitions,omitempty"`
+
+	Links   []string        `json:"links,omitempty" yaml:"links,omitempty"`
+	Members []*dicDocObject `json:"members,omitempty" yaml:"members,omitempty"`
+}
+
+// Object types of dicDocObject, variables have none or "var"
+const (
+	dicDocArray  = "array"
+	dicDocRecord = "record"
+)
+
+// DicJSONExport write objectDic as JSON to w
+func DicJSONExport(objectDic *DicObjectDic, w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(newDicDocument(objectDic))
+}
+
+// DicYAMLExport write objectDic as YAML to w
+func DicYAMLExport(objectDic *DicObjectDic, w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(newDicDocument(objectDic)); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// DicJSONParse parse an object dictionary written by DicJSONExport, from a file
+// path, []byte or io.Reader
+func DicJSONParse(in interface{}) (*DicObjectDic, error) {
+	data, err := dicReadSource(in)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &dicDocument{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+
+	return doc.objectDic()
+}
+
+// DicYAMLParse parse an object dictionary written by DicYAMLExport, or by hand,
+// from a file path, []byte or io.Reader
+func DicYAMLParse(in interface{}) (*DicObjectDic, error) {
+	data, err := dicReadSource(in)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &dicDocument{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+
+	return doc.objectDic()
+}
+
+func newDicDocument(objectDic *DicObjectDic) *dicDocument {
+	doc := &dicDocument{
+		NodeID:   objectDic.NodeID,
+		Baudrate: objectDic.Baudrate,
+		Objects:  []*dicDocObject{},
+	}
+
+	if objectDic.FileInfo != (DicFileInfo{}) {
+		fileInfo := objectDic.FileInfo
+		doc.FileInfo = &fileInfo
+	}
+
+	if !reflect.DeepEqual(objectDic.DeviceInfo, DicDeviceInfo{}) {
+		deviceInfo := objectDic.DeviceInfo
+		doc.DeviceInfo = &deviceInfo
+	}
+
+	for _, index := range dicSortedIndexes(objectDic) {
+		var obj *dicDocObject
+
+		switch object := objectDic.Indexes[index].(type) {
+		case *DicVariable:
+			obj = newDicDocVariable(object)
+		case *DicArray:
+			obj = &dicDocObject{Name: object.Name, ObjectType: dicDocArray, Description: object.Description}
+			for _, member := range dicSortedMembers(object.SubIndexes) {
+				obj.Members = append(obj.Members, newDicDocVariable(member))
+			}
+		case *DicRecord:
+			obj = &dicDocObject{Name: object.Name, ObjectType: dicDocRecord, Description: object.Description}
+			for _, member := range dicSortedMembers(object.SubIndexes) {
+				obj.Members = append(obj.Members, newDicDocVariable(member))
+			}
+		default:
+			continue
+		}
+
+		obj.Index = fmt.Sprintf("0x%04X", index)
+
+		for _, link := range objectDic.ObjectLinks[index] {
+			obj.Links = append(obj.Links, fmt.Sprintf("0x%04X", link))
+		}
+
+		doc.Objects = append(doc.Objects, obj)
+	}
+
+	return doc
+}
+
+func newDicDocVariable(variable *DicVariable) *dicDocObject {
+	obj := &dicDocObject{
+		SubIndex:          variable.SubIndex,
+		Name:              variable.Name,
+		Description:       variable.Description,
+		DataType:          DataTypeName(variable.DataType),
+		AccessType:        variable.AccessType,
+		PDOMapping:        variable.PDOMapping,
+		LowLimit:          variable.limitNotation(false),
+		HighLimit:         variable.limitNotation(true),
+		Default:           variable.DefaultValue,
+		Unit:              variable.Unit,
+		Factor:            variable.Factor,
+		ScaleOffset:       variable.ScaleOffset,
+		ValueDescriptions: variable.ValueDescriptions,
+	}
+
+	if obj.Default == "" && len(variable.Default) > 0 {
+		obj.Default = formatEDSValue(variable.DataType, variable.Default)
+	}
+
+	if len(variable.Data) > 0 {
+		obj.Value = formatEDSValue(variable.DataType, variable.Data)
+	}
+
+	if len(variable.BitDefinitions) > 0 {
+		obj.BitDefinitions = map[string][]int{}
+		for name, bits := range variable.BitDefinitions {
+			for _, bit := range bits {
+				obj.BitDefinitions[name] = append(obj.BitDefinitions[name], int(bit))
+			}
+		}
+	}
+
+	return obj
+}
+
+func (doc *dicDocument) objectDic() (*DicObjectDic, error) {
+	objectDic := NewDicObjectDic()
+	objectDic.NodeID = doc.NodeID
+	objectDic.Baudrate = doc.Baudrate
+
+	if doc.FileInfo != nil {
+		objectDic.FileInfo = *doc.FileInfo
+	}
+
+	if doc.DeviceInfo != nil {
+		objectDic.DeviceInfo = *doc.DeviceInfo
+	}
+
+	for _, obj := range doc.Objects {
+		i, err := strconv.ParseUint(obj.Index, 0, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index %q of %s", obj.Index, obj.Name)
+		}
+
+		index := uint16(i)
+
+		switch obj.ObjectType {
+		case "", "var":
+			variable, err := obj.variable(index, 0, doc.NodeID)
+			if err != nil {
+				return nil, err
+			}
+
+			objectDic.AddObject(variable)
+		case dicDocArray, dicDocRecord:
+			var object DicObject = &DicRecord{Index: index, Name: obj.Name, Description: obj.Description}
+			if obj.ObjectType == dicDocArray {
+				object = &DicArray{Index: index, Name: obj.Name, Description: obj.Description}
+			}
+
+			for _, member := range obj.Members {
+				variable, err := member.variable(index, member.SubIndex, doc.NodeID)
+				if err != nil {
+					return nil, err
+				}
+
+				object.AddMember(variable)
+			}
+
+			objectDic.AddObject(object)
+		default:
+			return nil, fmt.Errorf("invalid object type %q of 0x%04X", obj.ObjectType, index)
+		}
+
+		for _, link := range obj.Links {
+			l, err := strconv.ParseUint(link, 0, 16)
+			if err != nil {
+				return nil, fmt.Errorf("invalid link %q of 0x%04X", link, index)
+			}
+
+			objectDic.ObjectLinks[index] = append(objectDic.ObjectLinks[index], uint16(l))
+		}
+	}
+
+	return objectDic, nil
+}
+
+func (obj *dicDocObject) variable(index uint16, subIndex uint8, nodeID int) (*DicVariable, error) {
+	dataType, err := ParseDataType(obj.DataType)
+	if err != nil {
+		return nil, fmt.Errorf("0x%04X sub %d: %v", index, subIndex, err)
+	}
+
+	variable := &DicVariable{
+		Index:             index,
+		SubIndex:          subIndex,
+		Name:              obj.Name,
+		Description:       obj.Description,
+		DataType:          dataType,
+		AccessType:        obj.AccessType,
+		PDOMapping:        obj.PDOMapping,
+		Unit:              obj.Unit,
+		Factor:            obj.Factor,
+		ScaleOffset:       obj.ScaleOffset,
+		ValueDescriptions: obj.ValueDescriptions,
+	}
+
+	if err := variable.setLimit(obj.LowLimit, nodeID, false); err != nil {
+		return nil, fmt.Errorf("invalid low limit of 0x%04X sub %d: %v", index, subIndex, err)
+	}
+
+	if err := variable.setLimit(obj.HighLimit, nodeID, true); err != nil {
+		return nil, fmt.Errorf("invalid high limit of 0x%04X sub %d: %v", index, subIndex, err)
+	}
+
+	if err := variable.setDefaultValue(obj.Default, nodeID); err != nil {
+		return nil, fmt.Errorf("invalid default of 0x%04X sub %d: %v", index, subIndex, err)
+	}
+
+	if obj.Value != "" {
+		if variable.Data, err = decodeDicDocValue(dataType, obj.Value, nodeID); err != nil {
+			return nil, fmt.Errorf("invalid value of 0x%04X sub %d: %v", index, subIndex, err)
+		}
+	}
+
+	for name, bits := range obj.BitDefinitions {
+		b := make([]byte, len(bits))
+		for i, bit := range bits {
+			b[i] = byte(bit)
+		}
+
+		variable.AddBitDefinition(name, b)
+	}
+
+	return variable, nil
+}
+
+// decodeDicDocValue encode a value in EDS notation, keeping strings spaces
+func decodeDicDocValue(dataType byte, s string, nodeID int) ([]byte, error) {
+	switch dataType {
+	case VisibleString:
+		return []byte(s), nil
+	case UnicodeString:
+		return encodeDicUnicode(s), nil
+	}
+
+	return encodeDicValue(dataType, s, nodeID)
+}
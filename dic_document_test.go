@@ -0,0 +1,130 @@
+package canopen
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func newTestDocumentObjectDic() *DicObjectDic {
+	dic := newTestObjectDic()
+	dic.FileInfo.FileName = "test.eds"
+	dic.FindVariable(0x1018, 1).Default = []byte{0x34, 0x12, 0x00, 0x00}
+
+	status := &DicVariable{
+		Index:       0x2001,
+		Name:        "Status",
+		Description: "Drive status",
+		DataType:    Unsigned16,
+		AccessType:  "ro",
+		Data:        []byte{0x05, 0x00},
+	}
+	status.AddValueDescription(0, "Idle")
+	status.AddBitDefinition("Ready", []byte{0})
+	status.AddBitDefinition("Mode", []byte{1, 2})
+	dic.AddObject(status)
+
+	dic.AddObject(&DicVariable{Index: 0x2002, Name: "Label", DataType: VisibleString, AccessType: "rw", Data: []byte(" padded ")})
+	dic.AddObject(&DicVariable{Index: 0x2003, Name: "Blob", DataType: Domain, AccessType: "rw", Data: []byte{0x00, 0xFF}})
+	dic.AddObject(&DicVariable{Index: 0x2004, Name: "Uptime", DataType: TimeDifference, AccessType: "ro", Data: []byte{1, 2, 3, 4, 5, 6}})
+
+	values := &DicArray{Index: 0x2100, Name: "Values"}
+	values.AddMember(&DicVariable{Index: 0x2100, SubIndex: 0, Name: "Number of entries", DataType: Unsigned8, AccessType: "ro", Default: []byte{0x01}})
+	values.AddMember(&DicVariable{Index: 0x2100, SubIndex: 1, Name: "Value 1", DataType: Real32, AccessType: "rw", Data: []byte{0x00, 0x00, 0xC0, 0x3F}})
+	dic.AddObject(values)
+
+	dic.ObjectLinks[0x2100] = []uint16{0x2001}
+
+	return dic
+}
+
+func TestDicDocumentRoundTrip(t *testing.T) {
+	formats := map[string]struct {
+		export func(*DicObjectDic, *bytes.Buffer) error
+		parse  DicParseFunc
+	}{
+		"json": {func(d *DicObjectDic, b *bytes.Buffer) error { return DicJSONExport(d, b) }, DicJSONParse},
+		"yaml": {func(d *DicObjectDic, b *bytes.Buffer) error { return DicYAMLExport(d, b) }, DicYAMLParse},
+	}
+
+	for name, format := range formats {
+		dic := newTestDocumentObjectDic()
+		buf := &bytes.Buffer{}
+		if err := format.export(dic, buf); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		parsed, err := format.parse(buf.Bytes())
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", name, err, buf)
+		}
+
+		if diff := DicCompare(dic, parsed); len(diff) != 0 {
+			t.Errorf("%s: unexpected differences\n%s", name, diff)
+		}
+
+		for _, variable := range dic.Variables() {
+			p := parsed.FindVariable(variable.Index, variable.SubIndex)
+			if p.Name != variable.Name || p.Description != variable.Description || p.Unit != variable.Unit ||
+				p.Factor != variable.Factor || p.HasMin != variable.HasMin || p.Min != variable.Min ||
+				p.PDOMapping != variable.PDOMapping ||
+				!reflect.DeepEqual(p.ValueDescriptions, variable.ValueDescriptions) ||
+				!reflect.DeepEqual(p.BitDefinitions, variable.BitDefinitions) {
+				t.Errorf("%s: 0x%04X sub %d not preserved: %+v", name, variable.Index, variable.SubIndex, p)
+			}
+		}
+
+		if _, ok := parsed.FindIndex(0x2100).(*DicArray); !ok {
+			t.Errorf("%s: 0x2100 should be an array", name)
+		}
+
+		if !reflect.DeepEqual(parsed.ObjectLinks, dic.ObjectLinks) || parsed.DeviceInfo.VendorName != "go-canopen" ||
+			parsed.FileInfo.FileName != "test.eds" || parsed.NodeID != 5 {
+			t.Errorf("%s: dictionary info not preserved", name)
+		}
+	}
+}
+
+func TestDicYAMLParse(t *testing.T) {
+	src := `
+nodeId: 3
+objects:
+  - index: 0x1017
+    name: Producer heartbeat time
+    dataType: UNSIGNED16
+    default: 1000
+  - index: 0x1200
+    name: SDO server parameter
+    objectType: record
+    members:
+      - name: Highest sub-index supported
+        dataType: UNSIGNED8
+        accessType: ro
+        default: 2
+      - subIndex: 1
+        name: COB-ID client to server
+        dataType: UNSIGNED32
+        accessType: ro
+        default: $NODEID+0x600
+`
+
+	dic, err := DicYAMLParse([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	heartbeat := dic.FindVariable(0x1017, 0)
+	// Missing access type is kept empty, like in EDS files
+	if heartbeat == nil || heartbeat.AccessType != "" || !bytes.Equal(heartbeat.Default, []byte{0xE8, 0x03}) {
+		t.Errorf("unexpected heartbeat %+v", heartbeat)
+	}
+
+	cobID := dic.FindVariable(0x1200, 1)
+	if cobID == nil || !bytes.Equal(cobID.Default, []byte{0x03, 0x06, 0x00, 0x00}) {
+		t.Errorf("unexpected COB-ID %+v", cobID)
+	}
+
+	if _, err := DicYAMLParse([]byte("objects:\n  - index: 0x2000\n    name: Bad\n    dataType: FOO\n")); err == nil {
+		t.Error("expected invalid data type error")
+	}
+}
@@ -9,13 +9,19 @@ import (
 	"gopkg.in/ini.v1"
 )
 
+var (
+	matchIdxRegexp    = regexp.MustCompile(`^[0-9a-f]{4}$`)
+	matchSubIdxRegexp = regexp.MustCompile(`^([0-9a-f]{4})sub([0-9a-f]+)$`)
+	matchLinksRegexp  = regexp.MustCompile(`^([0-9a-f]{4})objectlinks$`)
+)
+
 // DicEDSParse If in is string, it must be a path to a file
 // else if in must be eds data as []byte
 func DicEDSParse(in interface{}) (*DicObjectDic, error) {
-	// Load ini file
-	iniData, err := ini.Load(in)
+	// Load ini file. EDS sections and keys names are case insensitive
+	iniData, err := ini.LoadSources(ini.LoadOptions{Insensitive: true}, in)
 	if err != nil {
-		return nil, nil
+		return nil, err
 	}
 
 	// Create object dictionary
@@ -36,8 +42,22 @@ func DicEDSParse(in interface{}) (*DicObjectDic, error) {
 		}
 	}
 
-	matchIdxRegexp := regexp.MustCompile(`^[0-9A-Fa-f]{4}$`)
-	matchSubIdxRegexp := regexp.MustCompile(`^([0-9A-Fa-f]{4})sub([0-9A-Fa-f]+)$`)
+	if sec, err := iniData.GetSection("FileInfo"); err == nil {
+		buildFileInfo(&ddic.FileInfo, sec)
+	}
+
+	if sec, err := iniData.GetSection("DeviceInfo"); err == nil {
+		buildDeviceInfo(&ddic.DeviceInfo, sec)
+	}
+
+	// Dummy objects usable in PDO mappings
+	if sec, err := iniData.GetSection("DummyUsage"); err == nil {
+		for i := uint16(1); i <= 7; i++ {
+			if edsKeyUint(sec, fmt.Sprintf("Dummy%04X", i)) != 0 {
+				addDummyObject(ddic, i)
+			}
+		}
+	}
 
 	// Iterate over sections
 	for _, sec := range iniData.Sections() {
@@ -53,11 +73,18 @@ func DicEDSParse(in interface{}) (*DicObjectDic, error) {
 			index := uint16(idx)
 
 			name := sec.Key("ParameterName").String()
-			objectType, _ := strconv.ParseUint(sec.Key("ObjectType").String(), 0, 8)
+			objectType := byte(DicVar)
+			if key, err := sec.GetKey("ObjectType"); err == nil && key.String() != "" {
+				ot, err := strconv.ParseUint(key.String(), 0, 8)
+				if err != nil {
+					return nil, fmt.Errorf("invalid ObjectType for index 0x%04X: %v", index, err)
+				}
+				objectType = byte(ot)
+			}
 
-			// Object type == VARIABLE
-			if byte(objectType) == DicVar || byte(objectType) == Domain {
-				variable, err := buildVariable(index, 0, name, sec, iniData)
+			// Object type == VARIABLE or DOMAIN
+			if objectType == DicVar || objectType == DicDomain {
+				variable, err := buildVariable(index, 0, name, sec, iniData, ddic.NodeID)
 				if err != nil {
 					return nil, err
 				}
@@ -65,13 +92,21 @@ func DicEDSParse(in interface{}) (*DicObjectDic, error) {
 			}
 
 			// Object type == ARRAY
-			if byte(objectType) == DicArr {
+			if objectType == DicArr {
 				array := &DicArray{Index: index, Name: name}
+
+				// Sub objects are implied by CompactSubObj
+				if edsKeyUint(sec, "CompactSubObj") != 0 {
+					if err := buildCompactSubObj(array, sec, iniData, ddic.NodeID); err != nil {
+						return nil, err
+					}
+				}
+
 				ddic.AddObject(array)
 			}
 
 			// Object type == RECORD
-			if byte(objectType) == DicRec {
+			if objectType == DicRec {
 				record := &DicRecord{Index: index, Name: name}
 				ddic.AddObject(record)
 			}
@@ -100,26 +135,159 @@ func DicEDSParse(in interface{}) (*DicObjectDic, error) {
 				return nil, fmt.Errorf("index with id %d not found", index)
 			}
 
-			variable, err := buildVariable(index, subIndex, name, sec, iniData)
+			variable, err := buildVariable(index, subIndex, name, sec, iniData, ddic.NodeID)
 			if err != nil {
 				return nil, err
 			}
 			object.AddMember(variable)
+
+			continue
 		}
 
-		// @TODO: Match [index]Name
+		// Match [index]ObjectLinks
+		if matches := matchLinksRegexp.FindStringSubmatch(sectionName); matches != nil {
+			idx, err := strconv.ParseUint(matches[1], 16, 16)
+			if err != nil {
+				return nil, err
+			}
+
+			links := []uint16{}
+			nofLinks := int(edsKeyUint(sec, "ObjectLinks"))
+
+			for i := 1; i <= nofLinks; i++ {
+				if link := edsKeyUint(sec, strconv.Itoa(i)); link != 0 {
+					links = append(links, uint16(link))
+				}
+			}
+
+			ddic.ObjectLinks[uint16(idx)] = links
+		}
+
+		// [index]Name and [index]Value are handled with their CompactSubObj array,
+		// [index]ValueDescriptions and [index]BitDefinitions with their variable
 	}
 
 	return ddic, nil
 }
 
-// @TODO: check working
+// buildCompactSubObj add to array sub-objects implied by the CompactSubObj key.
+// Each sub-object share the array section definition, names are read from
+// [indexName] section and values from [indexValue] section when they exist
+func buildCompactSubObj(array *DicArray, sec *ini.Section, iniData *ini.File, nodeID int) error {
+	nofEntries := uint8(edsKeyUint(sec, "CompactSubObj"))
+
+	// Sub 0 contain the number of entries
+	array.AddMember(&DicVariable{
+		Index:        array.Index,
+		SubIndex:     0,
+		Name:         "Number of entries",
+		DataType:     Unsigned8,
+		AccessType:   "ro",
+		Default:      []byte{nofEntries},
+		DefaultValue: strconv.Itoa(int(nofEntries)),
+	})
+
+	namesSec, _ := iniData.GetSection(fmt.Sprintf("%04XName", array.Index))
+	valuesSec, _ := iniData.GetSection(fmt.Sprintf("%04XValue", array.Index))
+
+	for subIndex := uint8(1); subIndex <= nofEntries && subIndex != 0; subIndex++ {
+		name := fmt.Sprintf("%s%d", array.Name, subIndex)
+		if namesSec != nil {
+			if key, err := namesSec.GetKey(strconv.Itoa(int(subIndex))); err == nil {
+				name = key.String()
+			}
+		}
+
+		variable, err := buildVariable(array.Index, subIndex, name, sec, iniData, nodeID)
+		if err != nil {
+			return err
+		}
+
+		// The array section ParameterValue has no meaning for sub-objects
+		variable.Data = nil
+
+		if valuesSec != nil {
+			if key, err := valuesSec.GetKey(strconv.Itoa(int(subIndex))); err == nil {
+				data, err := encodeDicValue(variable.DataType, key.String(), nodeID)
+				if err != nil {
+					return fmt.Errorf("invalid value for index 0x%04Xsub%d: %v", array.Index, subIndex, err)
+				}
+				variable.Data = data
+			}
+		}
+
+		array.AddMember(variable)
+	}
+
+	return nil
+}
+
+// addDummyObject add a dummy object to ddic. Dummy objects index is their data type
+func addDummyObject(ddic *DicObjectDic, index uint16) {
+	ddic.AddObject(&DicVariable{
+		Index:      index,
+		Name:       fmt.Sprintf("Dummy%04X", index),
+		DataType:   byte(index),
+		AccessType: "rw",
+		PDOMapping: true,
+	})
+}
+
+func buildFileInfo(fileInfo *DicFileInfo, sec *ini.Section) {
+	fileInfo.FileName = sec.Key("FileName").String()
+	fileInfo.FileVersion = int(edsKeyUint(sec, "FileVersion"))
+	fileInfo.FileRevision = int(edsKeyUint(sec, "FileRevision"))
+	fileInfo.EDSVersion = sec.Key("EDSVersion").String()
+	fileInfo.Description = sec.Key("Description").String()
+	fileInfo.CreationTime = sec.Key("CreationTime").String()
+	fileInfo.CreationDate = sec.Key("CreationDate").String()
+	fileInfo.CreatedBy = sec.Key("CreatedBy").String()
+	fileInfo.ModificationTime = sec.Key("ModificationTime").String()
+	fileInfo.ModificationDate = sec.Key("ModificationDate").String()
+	fileInfo.ModifiedBy = sec.Key("ModifiedBy").String()
+}
+
+func buildDeviceInfo(deviceInfo *DicDeviceInfo, sec *ini.Section) {
+	deviceInfo.VendorName = sec.Key("VendorName").String()
+	deviceInfo.VendorNumber = uint32(edsKeyUint(sec, "VendorNumber"))
+	deviceInfo.ProductName = sec.Key("ProductName").String()
+	deviceInfo.ProductNumber = uint32(edsKeyUint(sec, "ProductNumber"))
+	deviceInfo.RevisionNumber = uint32(edsKeyUint(sec, "RevisionNumber"))
+	deviceInfo.OrderCode = sec.Key("OrderCode").String()
+
+	for _, baudRate := range DicStandardBaudRates {
+		if edsKeyUint(sec, fmt.Sprintf("BaudRate_%d", baudRate)) != 0 {
+			deviceInfo.BaudRates = append(deviceInfo.BaudRates, baudRate)
+		}
+	}
+
+	deviceInfo.SimpleBootUpMaster = edsKeyUint(sec, "SimpleBootUpMaster") != 0
+	deviceInfo.SimpleBootUpSlave = edsKeyUint(sec, "SimpleBootUpSlave") != 0
+	deviceInfo.Granularity = int(edsKeyUint(sec, "Granularity"))
+	deviceInfo.GroupMessaging = edsKeyUint(sec, "GroupMessaging") != 0
+	deviceInfo.NrOfRXPDO = int(edsKeyUint(sec, "NrOfRXPDO"))
+	deviceInfo.NrOfTXPDO = int(edsKeyUint(sec, "NrOfTXPDO"))
+	deviceInfo.LSSSupported = edsKeyUint(sec, "LSS_Supported") != 0
+}
+
+// edsKeyUint return a key value as uint, or 0 if key don't exist or is invalid
+func edsKeyUint(sec *ini.Section, name string) uint64 {
+	key, err := sec.GetKey(name)
+	if err != nil {
+		return 0
+	}
+
+	v, _ := strconv.ParseUint(strings.TrimSpace(key.String()), 0, 64)
+	return v
+}
+
 func buildVariable(
 	index uint16,
 	subIndex uint8,
 	name string,
 	sec *ini.Section,
 	iniData *ini.File,
+	nodeID int,
 ) (*DicVariable, error) {
 	variable := &DicVariable{
 		Index:      index,
@@ -129,14 +297,14 @@ func buildVariable(
 	}
 
 	// Get & set DataType
-	i, err := sec.Key("DataType").Int()
+	dataType, err := strconv.ParseUint(sec.Key("DataType").String(), 0, 16)
 	if err != nil {
 		return nil, err
 	}
-	variable.DataType = byte(i)
+	variable.DataType = byte(dataType)
 
 	if variable.DataType > 0x1B {
-		dTypeStr := fmt.Sprintf("%dsub1", variable.DataType)
+		dTypeStr := fmt.Sprintf("%04Xsub1", variable.DataType)
 
 		i, err := iniData.Section(dTypeStr).Key("DefaultValue").Uint()
 		if err != nil {
@@ -146,25 +314,92 @@ func buildVariable(
 		variable.DataType = byte(i)
 	}
 
-	if lowl, err := sec.GetKey("LowLimit"); err == nil {
-		i, err := lowl.Int()
+	// Invalid limits and default values are kept in their original notation only,
+	// like tools do, instead of rejecting the whole file
+	variable.setLimit(sec.Key("LowLimit").String(), nodeID, false)
+	variable.setLimit(sec.Key("HighLimit").String(), nodeID, true)
+
+	if pdoMapping, err := sec.GetKey("PDOMapping"); err == nil {
+		i, _ := strconv.ParseUint(pdoMapping.String(), 0, 8)
+		variable.PDOMapping = i != 0
+	}
+
+	// Engineering unit scaling, not part of CiA 306 but commonly used by tools
+	variable.Unit = sec.Key("Unit").String()
+
+	if factor, err := sec.GetKey("Factor"); err == nil && factor.String() != "" {
+		f, err := factor.Float64()
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid Factor for index 0x%04Xsub%d: %v", index, subIndex, err)
 		}
-		variable.Min = i
+		variable.Factor = f
 	}
 
-	if howl, err := sec.GetKey("HighLimit"); err == nil {
-		i, err := howl.Int()
+	if offset, err := sec.GetKey("Offset"); err == nil && offset.String() != "" {
+		f, err := offset.Float64()
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid Offset for index 0x%04Xsub%d: %v", index, subIndex, err)
 		}
-		variable.Max = i
+		variable.ScaleOffset = f
 	}
 
 	if def, err := sec.GetKey("DefaultValue"); err == nil {
-		variable.Default = []byte(def.Value())
+		variable.setDefaultValue(def.Value(), nodeID)
+	}
+
+	// DCF parameter value
+	if param, err := sec.GetKey("ParameterValue"); err == nil && param.String() != "" {
+		data, err := encodeDicValue(variable.DataType, param.String(), nodeID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ParameterValue for index 0x%04Xsub%d: %v", index, subIndex, err)
+		}
+		variable.Data = data
+	}
+
+	if err := buildVariableDescriptions(variable, sec.Name(), iniData); err != nil {
+		return nil, fmt.Errorf("invalid descriptions for index 0x%04Xsub%d: %v", index, subIndex, err)
 	}
 
 	return variable, nil
 }
+
+// buildVariableDescriptions set variable ValueDescriptions and BitDefinitions from
+// the [{section}ValueDescriptions] and [{section}BitDefinitions] sections, keyed
+// by raw values and bit numbers. Like Unit and Factor, they are not part of CiA 306:
+//
+//	[2000ValueDescriptions]
+//	0=Ok
+//	1=Fault
+//
+//	[2001sub1BitDefinitions]
+//	0=Ready
+//	4,5=Mode
+func buildVariableDescriptions(variable *DicVariable, sectionName string, iniData *ini.File) error {
+	if sec, err := iniData.GetSection(sectionName + "ValueDescriptions"); err == nil {
+		for _, key := range sec.Keys() {
+			v, err := evalDicInt(key.Name(), 0, 64)
+			if err != nil {
+				return fmt.Errorf("invalid value %q: %v", key.Name(), err)
+			}
+
+			variable.AddValueDescription(v, key.String())
+		}
+	}
+
+	if sec, err := iniData.GetSection(sectionName + "BitDefinitions"); err == nil {
+		for _, key := range sec.Keys() {
+			bits := []byte{}
+			for _, bit := range strings.Split(key.Name(), ",") {
+				b, err := strconv.ParseUint(strings.TrimSpace(bit), 0, 8)
+				if err != nil {
+					return fmt.Errorf("invalid bits %q: %v", key.Name(), err)
+				}
+				bits = append(bits, byte(b))
+			}
+
+			variable.AddBitDefinition(key.String(), bits)
+		}
+	}
+
+	return nil
+}
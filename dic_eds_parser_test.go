@@ -1,6 +1,8 @@
 package canopen
 
 import (
+	"bytes"
+	"errors"
 	"testing"
 )
 
@@ -1963,3 +1965,209 @@ func TestDicEDSParse(t *testing.T) {
 
 	t.Log(dic)
 }
+
+const TestEDSCompactFile string = `
+[DeviceInfo]
+VendorName=go-canopen
+VendorNumber=0x00001234
+ProductName=IO
+BaudRate_250=1
+BaudRate_500=1
+Granularity=8
+NrOfRXPDO=1
+NrOfTXPDO=2
+
+[DummyUsage]
+Dummy0001=0
+Dummy0002=1
+Dummy0005=1
+
+[OptionalObjects]
+SupportedObjects=1
+1=0x6000
+
+[6000]
+ParameterName=Read input 8-bit
+ObjectType=0x8
+DataType=0x0005
+AccessType=ro
+PDOMapping=1
+CompactSubObj=3
+
+[6000Name]
+NrOfEntries=2
+1=Inputs 1-8
+2=Inputs 9-16
+
+[6000Value]
+NrOfEntries=1
+3=0x0F
+
+[6000ObjectLinks]
+ObjectLinks=2
+1=0x6002
+2=0x6003
+
+[6002]
+ParameterName=Polarity input 8-bit
+DataType=0x0005
+AccessType=rw
+ParameterValue=0x01
+`
+
+func TestDicEDSParseCompact(t *testing.T) {
+	dic, err := DicEDSParse([]byte(TestEDSCompactFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dic.DeviceInfo.VendorNumber != 0x1234 || !dic.DeviceInfo.SupportBaudRate(500) || dic.DeviceInfo.NrOfTXPDO != 2 {
+		t.Fatalf("invalid device info %+v", dic.DeviceInfo)
+	}
+
+	if dic.FindIndex(0x0001) != nil || dic.FindIndex(0x0002) == nil || dic.FindIndex(0x0005).GetDataType() != Unsigned8 {
+		t.Fatal("invalid dummy objects")
+	}
+
+	array, ok := dic.FindIndex(0x6000).(*DicArray)
+	if !ok {
+		t.Fatal("0x6000 should be an array")
+	}
+
+	if len(array.SubIndexes) != 4 {
+		t.Fatalf("invalid number of sub-objects %d", len(array.SubIndexes))
+	}
+
+	if array.FindIndex(2).GetName() != "Inputs 9-16" || array.FindIndex(3).GetName() != "Read input 8-bit3" {
+		t.Fatal("invalid sub-objects names")
+	}
+
+	if sub3 := array.FindIndex(3).(*DicVariable); sub3.DataType != Unsigned8 || !sub3.PDOMapping || sub3.Data[0] != 0x0F {
+		t.Fatalf("invalid sub-object %+v", sub3)
+	}
+
+	if links := dic.ObjectLinks[0x6000]; len(links) != 2 || links[1] != 0x6003 {
+		t.Fatalf("invalid object links %v", links)
+	}
+
+	// ObjectType default to VAR
+	polarity, ok := dic.FindIndex(0x6002).(*DicVariable)
+	if !ok || polarity.Data[0] != 0x01 {
+		t.Fatal("invalid 0x6002 variable")
+	}
+}
+
+func TestDicEDSParseLimits(t *testing.T) {
+	dic, err := DicEDSParse([]byte("[2000]\nParameterName=Gain\nObjectType=0x7\nDataType=0x0008\nAccessType=rw\nLowLimit=-1.5\nHighLimit=2.5\n\n" +
+		"[2001]\nParameterName=Offset\nObjectType=0x7\nDataType=0x0003\nAccessType=rw\nLowLimit=-0x10\nHighLimit=$NODEID+1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gain := dic.FindIndex(0x2000).(*DicVariable)
+	if !gain.HasMin || gain.MinFloat != -1.5 || !gain.HasMax || gain.MaxFloat != 2.5 {
+		t.Fatalf("invalid REAL32 limits %+v", gain)
+	}
+
+	if err := gain.Validate([]byte{0x00, 0x00, 0xA0, 0xBF}); err != nil {
+		t.Fatalf("-1.25 should be in range, got %v", err)
+	}
+
+	if err := gain.Validate([]byte{0x00, 0x00, 0x00, 0xC0}); !errors.Is(err, ErrDicOutOfRange) {
+		t.Fatalf("-2 should be out of range, got %v", err)
+	}
+
+	offset := dic.FindIndex(0x2001).(*DicVariable)
+	if offset.Min != -16 || offset.Max != 1 {
+		t.Fatalf("invalid INTEGER16 limits %d / %d", offset.Min, offset.Max)
+	}
+
+	// $NODEID limits are evaluated again for the node
+	dic.SetNodeID(5)
+	if offset.Max != 6 || offset.HighLimit != "$NODEID+1" {
+		t.Fatalf("invalid INTEGER16 high limit %d %q", offset.Max, offset.HighLimit)
+	}
+}
+
+func TestDicEDSParseInvalidValues(t *testing.T) {
+	dic, err := DicEDSParse([]byte("[2000]\nParameterName=Gain\nObjectType=0x7\nDataType=0x0008\nAccessType=rw\nDefaultValue=0x3F800000\nLowLimit=0xBF800000\n\n" +
+		"[2001]\nParameterName=Mode\nObjectType=0x7\nDataType=0x0005\nAccessType=rw\nDefaultValue=auto\nHighLimit=max\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gain := dic.FindIndex(0x2000).(*DicVariable)
+	if !bytes.Equal(gain.Default, []byte{0x00, 0x00, 0x80, 0x3F}) || !gain.HasMin || gain.MinFloat != -1 {
+		t.Fatalf("invalid REAL32 hexadecimal values %+v", gain)
+	}
+
+	// Invalid values are kept in their original notation only
+	mode := dic.FindIndex(0x2001).(*DicVariable)
+	if mode.Default != nil || mode.DefaultValue != "auto" || mode.HasMax || mode.HighLimit != "max" {
+		t.Fatalf("invalid values should be kept as is %+v", mode)
+	}
+}
+
+const TestEDSDescriptionsFile string = `
+[2000]
+ParameterName=State
+ObjectType=0x7
+DataType=0x0005
+AccessType=ro
+DefaultValue=1
+
+[2000ValueDescriptions]
+0=Ok
+1=Fault
+0xFF=Unknown
+
+[2001]
+ParameterName=Status
+ObjectType=0x9
+SubNumber=2
+
+[2001sub0]
+ParameterName=Number of entries
+ObjectType=0x7
+DataType=0x0005
+AccessType=ro
+DefaultValue=1
+
+[2001sub1]
+ParameterName=Status 1
+ObjectType=0x7
+DataType=0x0006
+AccessType=ro
+DefaultValue=0x0021
+
+[2001sub1BitDefinitions]
+0=Ready
+4,5=Mode
+`
+
+func TestDicEDSParseDescriptions(t *testing.T) {
+	dic, err := DicEDSParse([]byte(TestEDSDescriptionsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := dic.FindIndex(0x2000).(*DicVariable)
+	if len(state.ValueDescriptions) != 3 || state.ValueDescriptions[0xFF] != "Unknown" {
+		t.Fatalf("invalid value descriptions %v", state.ValueDescriptions)
+	}
+
+	state.Data = state.Default
+	if des, err := state.DescribedValue(); err != nil || des != "Fault" {
+		t.Fatalf("invalid described value %q: %v", des, err)
+	}
+
+	status := dic.FindIndex(0x2001).FindIndex(1).(*DicVariable)
+	if bits := status.BitDefinitions["Mode"]; len(bits) != 2 || bits[0] != 4 || bits[1] != 5 {
+		t.Fatalf("invalid bit definitions %v", status.BitDefinitions)
+	}
+
+	status.Data = status.Default
+	if mode, err := status.BitField("Mode"); err != nil || mode != 2 {
+		t.Fatalf("invalid bit field value %d: %v", mode, err)
+	}
+}
@@ -0,0 +1,342 @@
+package canopen
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const edsLineEnd = "\r\n"
+
+// DicEDSExport write objectDic as an EDS file to w
+func DicEDSExport(objectDic *DicObjectDic, w io.Writer) error {
+	return dicEDSWrite(objectDic, w, false)
+}
+
+// DicDCFExport write objectDic as a DCF file to w. Variables Data are written
+// as ParameterValue, and objectDic NodeID / Baudrate in [DeviceComissioning]
+func DicDCFExport(objectDic *DicObjectDic, w io.Writer) error {
+	return dicEDSWrite(objectDic, w, true)
+}
+
+// edsWriter write ini sections and keys, keeping first error
+type edsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (ew *edsWriter) section(name string) {
+	if ew.err != nil {
+		return
+	}
+
+	_, ew.err = fmt.Fprintf(ew.w, "%s[%s]%s", edsLineEnd, name, edsLineEnd)
+}
+
+func (ew *edsWriter) key(name string, value interface{}) {
+	if ew.err != nil {
+		return
+	}
+
+	_, ew.err = fmt.Fprintf(ew.w, "%s=%v%s", name, value, edsLineEnd)
+}
+
+func dicEDSWrite(objectDic *DicObjectDic, w io.Writer, dcf bool) error {
+	ew := &edsWriter{w: bufio.NewWriter(w)}
+
+	writeEDSFileInfo(ew, objectDic)
+	writeEDSDeviceInfo(ew, objectDic)
+
+	if dcf {
+		ew.section("DeviceComissioning")
+		ew.key("NodeId", fmt.Sprintf("0x%02X", objectDic.NodeID))
+		ew.key("Baudrate", objectDic.Baudrate)
+	}
+
+	// Dummy objects usage
+	ew.section("DummyUsage")
+	for i := uint16(1); i <= 7; i++ {
+		used := 0
+		if objectDic.FindIndex(i) != nil {
+			used = 1
+		}
+
+		ew.key(fmt.Sprintf("Dummy%04X", i), used)
+	}
+
+	// Sort objects in mandatory, optional, manufacturer lists
+	mandatory, optional, manufacturer := []uint16{}, []uint16{}, []uint16{}
+	for _, index := range dicSortedIndexes(objectDic) {
+		switch {
+		case index < 0x1000:
+			// Dummy objects are declared in [DummyUsage]
+		case index == 0x1000 || index == 0x1001 || index == 0x1018:
+			mandatory = append(mandatory, index)
+		case index >= 0x2000 && index < 0x6000:
+			manufacturer = append(manufacturer, index)
+		default:
+			optional = append(optional, index)
+		}
+	}
+
+	for _, list := range []struct {
+		name    string
+		indexes []uint16
+	}{
+		{"MandatoryObjects", mandatory},
+		{"OptionalObjects", optional},
+		{"ManufacturerObjects", manufacturer},
+	} {
+		ew.section(list.name)
+		ew.key("SupportedObjects", len(list.indexes))
+
+		for i, index := range list.indexes {
+			ew.key(strconv.Itoa(i+1), fmt.Sprintf("0x%04X", index))
+		}
+
+		for _, index := range list.indexes {
+			writeEDSObject(ew, objectDic.FindIndex(index), dcf)
+		}
+	}
+
+	if ew.err != nil {
+		return ew.err
+	}
+
+	return ew.w.Flush()
+}
+
+func writeEDSFileInfo(ew *edsWriter, objectDic *DicObjectDic) {
+	fileInfo := objectDic.FileInfo
+	now := time.Now()
+
+	if fileInfo.EDSVersion == "" {
+		fileInfo.EDSVersion = "4.0"
+	}
+
+	if fileInfo.CreationTime == "" {
+		fileInfo.CreationTime = now.Format("03:04PM")
+	}
+
+	if fileInfo.CreationDate == "" {
+		fileInfo.CreationDate = now.Format("01-02-2006")
+	}
+
+	if fileInfo.ModificationTime == "" {
+		fileInfo.ModificationTime = fileInfo.CreationTime
+	}
+
+	if fileInfo.ModificationDate == "" {
+		fileInfo.ModificationDate = fileInfo.CreationDate
+	}
+
+	ew.section("FileInfo")
+	ew.key("FileName", fileInfo.FileName)
+	ew.key("FileVersion", fileInfo.FileVersion)
+	ew.key("FileRevision", fileInfo.FileRevision)
+	ew.key("EDSVersion", fileInfo.EDSVersion)
+	ew.key("Description", fileInfo.Description)
+	ew.key("CreationTime", fileInfo.CreationTime)
+	ew.key("CreationDate", fileInfo.CreationDate)
+	ew.key("CreatedBy", fileInfo.CreatedBy)
+	ew.key("ModificationTime", fileInfo.ModificationTime)
+	ew.key("ModificationDate", fileInfo.ModificationDate)
+	ew.key("ModifiedBy", fileInfo.ModifiedBy)
+}
+
+func writeEDSDeviceInfo(ew *edsWriter, objectDic *DicObjectDic) {
+	deviceInfo := objectDic.DeviceInfo
+
+	ew.section("DeviceInfo")
+	ew.key("VendorName", deviceInfo.VendorName)
+	ew.key("VendorNumber", fmt.Sprintf("0x%08X", deviceInfo.VendorNumber))
+	ew.key("ProductName", deviceInfo.ProductName)
+	ew.key("ProductNumber", fmt.Sprintf("0x%08X", deviceInfo.ProductNumber))
+	ew.key("RevisionNumber", fmt.Sprintf("0x%08X", deviceInfo.RevisionNumber))
+	ew.key("OrderCode", deviceInfo.OrderCode)
+
+	for _, baudRate := range DicStandardBaudRates {
+		ew.key(fmt.Sprintf("BaudRate_%d", baudRate), edsBool(deviceInfo.SupportBaudRate(baudRate)))
+	}
+
+	ew.key("SimpleBootUpMaster", edsBool(deviceInfo.SimpleBootUpMaster))
+	ew.key("SimpleBootUpSlave", edsBool(deviceInfo.SimpleBootUpSlave))
+	ew.key("Granularity", deviceInfo.Granularity)
+	ew.key("DynamicChannelsSupported", 0)
+	ew.key("GroupMessaging", edsBool(deviceInfo.GroupMessaging))
+	ew.key("NrOfRXPDO", deviceInfo.NrOfRXPDO)
+	ew.key("NrOfTXPDO", deviceInfo.NrOfTXPDO)
+	ew.key("LSS_Supported", edsBool(deviceInfo.LSSSupported))
+}
+
+func writeEDSObject(ew *edsWriter, object DicObject, dcf bool) {
+	sectionName := fmt.Sprintf("%04X", object.GetIndex())
+
+	if object.IsDicVariable() {
+		ew.section(sectionName)
+		writeEDSVariable(ew, object.(*DicVariable), dcf)
+		writeEDSDescriptions(ew, sectionName, object.(*DicVariable))
+		return
+	}
+
+	var subIndexes map[uint8]DicObject
+	objectType := DicRec
+
+	switch o := object.(type) {
+	case *DicArray:
+		subIndexes = o.SubIndexes
+		objectType = DicArr
+	case *DicRecord:
+		subIndexes = o.SubIndexes
+	}
+
+	ew.section(sectionName)
+	ew.key("ParameterName", object.GetName())
+	ew.key("ObjectType", fmt.Sprintf("0x%X", objectType))
+	ew.key("SubNumber", fmt.Sprintf("0x%X", len(subIndexes)))
+
+	for _, member := range dicSortedMembers(subIndexes) {
+		memberSectionName := fmt.Sprintf("%ssub%X", sectionName, member.SubIndex)
+		ew.section(memberSectionName)
+		writeEDSVariable(ew, member, dcf)
+		writeEDSDescriptions(ew, memberSectionName, member)
+	}
+}
+
+// writeEDSDescriptions write variable ValueDescriptions and BitDefinitions
+// sections, see buildVariableDescriptions
+func writeEDSDescriptions(ew *edsWriter, sectionName string, variable *DicVariable) {
+	if len(variable.ValueDescriptions) > 0 {
+		values := make([]int64, 0, len(variable.ValueDescriptions))
+		for value := range variable.ValueDescriptions {
+			values = append(values, value)
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+		ew.section(sectionName + "ValueDescriptions")
+		for _, value := range values {
+			ew.key(strconv.FormatInt(value, 10), variable.ValueDescriptions[value])
+		}
+	}
+
+	if len(variable.BitDefinitions) > 0 {
+		names := make([]string, 0, len(variable.BitDefinitions))
+		for name := range variable.BitDefinitions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		ew.section(sectionName + "BitDefinitions")
+		for _, name := range names {
+			bits := make([]string, len(variable.BitDefinitions[name]))
+			for i, bit := range variable.BitDefinitions[name] {
+				bits[i] = strconv.Itoa(int(bit))
+			}
+
+			ew.key(strings.Join(bits, ","), name)
+		}
+	}
+}
+
+func writeEDSVariable(ew *edsWriter, variable *DicVariable, dcf bool) {
+	ew.key("ParameterName", variable.Name)
+	ew.key("ObjectType", fmt.Sprintf("0x%X", DicVar))
+	ew.key("DataType", fmt.Sprintf("0x%04X", variable.DataType))
+	ew.key("AccessType", variable.AccessType)
+
+	// Keep original notation, which can contain $NODEID expressions
+	if lowLimit := variable.limitNotation(false); lowLimit != "" {
+		ew.key("LowLimit", lowLimit)
+	}
+
+	if highLimit := variable.limitNotation(true); highLimit != "" {
+		ew.key("HighLimit", highLimit)
+	}
+
+	// Keep original notation, which can contain $NODEID expressions
+	defaultValue := variable.DefaultValue
+	if defaultValue == "" && len(variable.Default) > 0 {
+		defaultValue = formatEDSValue(variable.DataType, variable.Default)
+	}
+
+	ew.key("DefaultValue", defaultValue)
+	ew.key("PDOMapping", edsBool(variable.PDOMapping))
+
+	if variable.Unit != "" {
+		ew.key("Unit", variable.Unit)
+	}
+
+	if variable.Factor != 0 {
+		ew.key("Factor", strconv.FormatFloat(variable.Factor, 'g', -1, 64))
+	}
+
+	if variable.ScaleOffset != 0 {
+		ew.key("Offset", strconv.FormatFloat(variable.ScaleOffset, 'g', -1, 64))
+	}
+
+	if dcf && len(variable.Data) > 0 && variable.DataType != Domain {
+		ew.key("ParameterValue", formatEDSValue(variable.DataType, variable.Data))
+	}
+}
+
+// dicSortedIndexes return objectDic indexes in ascending order
+func dicSortedIndexes(objectDic *DicObjectDic) []uint16 {
+	indexes := make([]int, 0, len(objectDic.Indexes))
+	for index := range objectDic.Indexes {
+		indexes = append(indexes, int(index))
+	}
+	sort.Ints(indexes)
+
+	r := make([]uint16, len(indexes))
+	for i, index := range indexes {
+		r[i] = uint16(index)
+	}
+
+	return r
+}
+
+func edsBool(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// formatEDSValue format data encoded as dataType in EDS notation
+func formatEDSValue(dataType byte, data []byte) string {
+	switch {
+	case dataType == Boolean:
+		return strconv.Itoa(edsBool(len(data) > 0 && data[0] != 0))
+	case IsUnsignedType(dataType):
+		if v, err := decodeDicUint(dataType, data); err == nil {
+			return fmt.Sprintf("0x%0*X", len(data)*2, v)
+		}
+	case IsSignedType(dataType):
+		if v, err := decodeDicInt(dataType, data); err == nil {
+			return strconv.FormatInt(v, 10)
+		}
+	case IsFloatType(dataType):
+		if v, err := decodeDicFloat(dataType, data); err == nil {
+			bitSize := 64
+			if dataType == Real32 {
+				bitSize = 32
+			}
+
+			return strconv.FormatFloat(v, 'g', -1, bitSize)
+		}
+	case (dataType == TimeOfDay || dataType == TimeDifference) && len(data) == 6:
+		return fmt.Sprintf("0x%012X", binary.LittleEndian.Uint64(append(append([]byte{}, data...), 0, 0)))
+	case dataType == VisibleString:
+		return string(data)
+	case dataType == UnicodeString:
+		return decodeDicUnicode(data)
+	}
+
+	return strings.ToUpper(fmt.Sprintf("%x", data))
+}
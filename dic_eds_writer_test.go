@@ -0,0 +1,133 @@
+package canopen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func newTestObjectDic() *DicObjectDic {
+	dic := NewDicObjectDic()
+	dic.NodeID = 5
+	dic.Baudrate = 250
+	dic.DeviceInfo = DicDeviceInfo{
+		VendorName:   "go-canopen",
+		VendorNumber: 0x1234,
+		BaudRates:    []int{125, 250},
+		NrOfRXPDO:    1,
+		NrOfTXPDO:    1,
+	}
+
+	dic.AddObject(&DicVariable{
+		Index:      0x1000,
+		Name:       "Device type",
+		DataType:   Unsigned32,
+		AccessType: "ro",
+		Default:    []byte{0x91, 0x01, 0x00, 0x00},
+		Data:       []byte{0x91, 0x01, 0x00, 0x00},
+	})
+
+	identity := &DicRecord{Index: 0x1018, Name: "Identity object"}
+	identity.AddMember(&DicVariable{Index: 0x1018, SubIndex: 0, Name: "Number of entries", DataType: Unsigned8, AccessType: "ro", Default: []byte{0x01}})
+	identity.AddMember(&DicVariable{Index: 0x1018, SubIndex: 1, Name: "Vendor-ID", DataType: Unsigned32, AccessType: "ro", DefaultValue: "0x1234"})
+	dic.AddObject(identity)
+
+	dic.AddObject(&DicVariable{
+		Index:      0x2000,
+		Name:       "Temperature offset",
+		DataType:   Integer16,
+		AccessType: "rw",
+		PDOMapping: true,
+		Unit:       "°C",
+		Factor:     0.1,
+		Min:        -100,
+		Max:        100,
+		HasMin:     true,
+		HasMax:     true,
+		Default:    []byte{0x00, 0x00},
+		Data:       []byte{0xF6, 0xFF},
+
+		ValueDescriptions: map[int64]string{0: "Off", -100: "Minimum"},
+		BitDefinitions:    map[string][]byte{"Sign": {15}},
+	})
+	return dic
+}
+
+func TestDicEDSExport(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := DicEDSExport(newTestObjectDic(), buf); err != nil {
+		t.Fatal(err)
+	}
+
+	eds := buf.String()
+	for _, expected := range []string{
+		"[MandatoryObjects]\r\nSupportedObjects=2\r\n1=0x1000\r\n2=0x1018",
+		"[ManufacturerObjects]\r\nSupportedObjects=1\r\n1=0x2000",
+		"[2000ValueDescriptions]\r\n-100=Minimum\r\n0=Off",
+		"[2000BitDefinitions]\r\n15=Sign",
+		"[1018sub1]\r\nParameterName=Vendor-ID",
+		"BaudRate_250=1",
+		"LowLimit=-100",
+		"DefaultValue=0x00000191",
+		"DefaultValue=0x1234",
+	} {
+		if !strings.Contains(eds, expected) {
+			t.Fatalf("%q not found in EDS:\n%s", expected, eds)
+		}
+	}
+
+	if strings.Contains(eds, "ParameterValue") || strings.Contains(eds, "DeviceComissioning") {
+		t.Fatal("EDS should not contain DCF entries")
+	}
+
+	// Parse exported EDS
+	dic, err := DicEDSParse(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	variable, ok := dic.FindIndex(0x2000).(*DicVariable)
+	if !ok {
+		t.Fatal("0x2000 not found in parsed EDS")
+	}
+
+	if variable.DataType != Integer16 || !variable.PDOMapping || variable.Min != -100 || variable.Max != 100 ||
+		variable.Unit != "°C" || variable.Factor != 0.1 {
+		t.Fatalf("invalid parsed variable %+v", variable)
+	}
+
+	if dic.FindIndex(0x1018).FindIndex(1).GetName() != "Vendor-ID" {
+		t.Fatal("invalid parsed record")
+	}
+
+	if variable.ValueDescriptions[-100] != "Minimum" || len(variable.BitDefinitions["Sign"]) != 1 {
+		t.Fatalf("invalid parsed descriptions %v / %v", variable.ValueDescriptions, variable.BitDefinitions)
+	}
+}
+
+func TestDicDCFExport(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := DicDCFExport(newTestObjectDic(), buf); err != nil {
+		t.Fatal(err)
+	}
+
+	dcf := buf.String()
+	for _, expected := range []string{
+		"[DeviceComissioning]\r\nNodeId=0x05\r\nBaudrate=250",
+		"ParameterValue=0x00000191",
+		"ParameterValue=-10",
+	} {
+		if !strings.Contains(dcf, expected) {
+			t.Fatalf("%q not found in DCF:\n%s", expected, dcf)
+		}
+	}
+
+	dic, err := DicEDSParse(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dic.NodeID != 5 || dic.Baudrate != 250 {
+		t.Fatalf("invalid parsed comissioning %d / %d", dic.NodeID, dic.Baudrate)
+	}
+}
@@ -1,5 +1,7 @@
 package canopen
 
+import "time"
+
 type DicObject interface {
 	// For DicRecord and DicArray
 
@@ -31,13 +33,20 @@ type DicObject interface {
 	GetIntVal() *int64
 	GetBoolVal() *bool
 	GetByteVal() *byte
-
-	SetStringVal(string)
-	SetFloatVal(float64)
-	SetUintVal(uint64)
-	SetIntVal(int64)
-	SetBoolVal(bool)
-	SetByteVal(byte)
+	GetTimeVal() *time.Time
+	GetDurationVal() *time.Duration
+
+	SetStringVal(string) error
+	SetFloatVal(float64) error
+	SetUintVal(uint64) error
+	SetIntVal(int64) error
+	SetBoolVal(bool) error
+	SetByteVal(byte) error
+	SetTimeVal(time.Time) error
+	SetDurationVal(time.Duration) error
+
+	Value() (any, error)
+	SetValue(any) error
 
 	// For All DicObject
 
@@ -4,17 +4,25 @@ type DicObjectDic struct {
 	Baudrate int
 	NodeID   int
 
+	// FileInfo and DeviceInfo describe the EDS file and the device
+	FileInfo   DicFileInfo
+	DeviceInfo DicDeviceInfo
+
 	// Map of Object ids to objects
 	Indexes map[uint16]DicObject
 
 	// Index to map objects names to objects indexs
 	NamesIndex map[string]uint16
+
+	// ObjectLinks map an object index to its linked objects indexes
+	ObjectLinks map[uint16][]uint16
 }
 
 func NewDicObjectDic() *DicObjectDic {
 	return &DicObjectDic{
-		Indexes:    map[uint16]DicObject{},
-		NamesIndex: map[string]uint16{},
+		Indexes:     map[uint16]DicObject{},
+		NamesIndex:  map[string]uint16{},
+		ObjectLinks: map[uint16][]uint16{},
 	}
 }
 
@@ -38,3 +46,121 @@ func (objectDic *DicObjectDic) FindName(name string) DicObject {
 
 	return nil
 }
+
+// Variables return all objectDic variables, including arrays and records members,
+// ordered by index and sub index
+func (objectDic *DicObjectDic) Variables() []*DicVariable {
+	variables := []*DicVariable{}
+
+	for _, index := range dicSortedIndexes(objectDic) {
+		switch object := objectDic.Indexes[index].(type) {
+		case *DicVariable:
+			variables = append(variables, object)
+		case *DicArray:
+			variables = append(variables, dicSortedMembers(object.SubIndexes)...)
+		case *DicRecord:
+			variables = append(variables, dicSortedMembers(object.SubIndexes)...)
+		}
+	}
+
+	return variables
+}
+
+// Copy return a deep copy of objectDic, variables data included. Objects of the
+// copy are not bound to an SDOClient
+func (objectDic *DicObjectDic) Copy() *DicObjectDic {
+	c := NewDicObjectDic()
+	c.Baudrate = objectDic.Baudrate
+	c.NodeID = objectDic.NodeID
+	c.FileInfo = objectDic.FileInfo
+	c.DeviceInfo = objectDic.DeviceInfo
+	c.DeviceInfo.BaudRates = append([]int(nil), objectDic.DeviceInfo.BaudRates...)
+
+	for index, object := range objectDic.Indexes {
+		c.Indexes[index] = dicCopyObject(object)
+	}
+
+	for name, index := range objectDic.NamesIndex {
+		c.NamesIndex[name] = index
+	}
+
+	for index, links := range objectDic.ObjectLinks {
+		c.ObjectLinks[index] = append([]uint16(nil), links...)
+	}
+
+	return c
+}
+
+// dicCopyObject return a deep copy of object
+func dicCopyObject(object DicObject) DicObject {
+	switch object := object.(type) {
+	case *DicVariable:
+		return object.copy()
+	case *DicArray:
+		c := *object
+		c.SDOClient = nil
+		c.SubIndexes, c.SubNames = dicCopyMembers(object.SubIndexes, object.SubNames)
+		return &c
+	case *DicRecord:
+		c := *object
+		c.SDOClient = nil
+		c.SubIndexes, c.SubNames = dicCopyMembers(object.SubIndexes, object.SubNames)
+		return &c
+	}
+
+	return object
+}
+
+// dicCopyMembers return deep copies of arrays and records members maps
+func dicCopyMembers(subIndexes map[uint8]DicObject, subNames map[string]uint8) (map[uint8]DicObject, map[string]uint8) {
+	if subIndexes == nil {
+		return nil, nil
+	}
+
+	copiedIndexes := map[uint8]DicObject{}
+	for subIndex, object := range subIndexes {
+		copiedIndexes[subIndex] = dicCopyObject(object)
+	}
+
+	copiedNames := map[string]uint8{}
+	for name, subIndex := range subNames {
+		copiedNames[name] = subIndex
+	}
+
+	return copiedIndexes, copiedNames
+}
+
+// SetNodeID set objectDic NodeID, and evaluate again variables default values
+// and limits containing a $NODEID expression
+func (objectDic *DicObjectDic) SetNodeID(nodeID int) {
+	objectDic.NodeID = nodeID
+
+	for _, variable := range objectDic.Variables() {
+		if hasNodeIDVar(variable.DefaultValue) {
+			if data, err := encodeDicValue(variable.DataType, variable.DefaultValue, nodeID); err == nil {
+				variable.Default = data
+			}
+		}
+
+		if hasNodeIDVar(variable.LowLimit) {
+			variable.setLimit(variable.LowLimit, nodeID, false)
+		}
+
+		if hasNodeIDVar(variable.HighLimit) {
+			variable.setLimit(variable.HighLimit, nodeID, true)
+		}
+	}
+}
+
+// dicSortedMembers return variables in subIndexes ordered by sub index
+func dicSortedMembers(subIndexes map[uint8]DicObject) []*DicVariable {
+	variables := []*DicVariable{}
+
+	for i := 0; i <= 0xFF; i++ {
+		if variable, ok := subIndexes[uint8(i)].(*DicVariable); ok {
+			variables = append(variables, variable)
+		}
+	}
+
+	return variables
+}
@@ -1,5 +1,14 @@
 package canopen
 
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// DicParseFunc parse an object dictionary from a file path, []byte or io.Reader
+type DicParseFunc func(in interface{}) (*DicObjectDic, error)
+
 func DicMustParse(a *DicObjectDic, err error) *DicObjectDic {
 	if err != nil {
 		panic(err)
@@ -7,3 +16,46 @@ func DicMustParse(a *DicObjectDic, err error) *DicObjectDic {
 
 	return a
 }
+
+// DicParse parse an object dictionary from an EDS, DCF, XDD, XDC, JSON or YAML source.
+// If in is a string, it must be a path to a file, and the format is chosen from
+// file extension. Else in must be file data as []byte or io.Reader, and format is
+// detected from data
+func DicParse(in interface{}) (*DicObjectDic, error) {
+	if path, ok := in.(string); ok {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".xdd", ".xdc", ".xml":
+			return DicXDDParse(path)
+		case ".eds", ".dcf":
+			return DicEDSParse(path)
+		case ".json":
+			return DicJSONParse(path)
+		case ".yaml", ".yml":
+			return DicYAMLParse(path)
+		}
+	}
+
+	data, err := dicReadSource(in)
+	if err != nil {
+		return nil, err
+	}
+
+	return dicParserFor(data)(data)
+}
+
+// dicParserFor return the parser for data. XML data start with '<' and JSON data
+// with '{', after an optional BOM. YAML data are detected from file extension only
+func dicParserFor(data []byte) DicParseFunc {
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 && data[0] == '<' {
+		return DicXDDParse
+	}
+
+	if len(data) > 0 && data[0] == '{' {
+		return DicJSONParse
+	}
+
+	return DicEDSParse
+}
@@ -1,5 +1,7 @@
 package canopen
 
+import "time"
+
 type DicRecord struct {
 	Description string
 	Index       uint16
@@ -57,28 +59,34 @@ func (record *DicRecord) FindName(name string) DicObject {
 	return nil
 }
 
-func (record *DicRecord) GetDataType() byte     { return 0x00 }
-func (record *DicRecord) GetDataLen() int       { return 0 }
-func (record *DicRecord) SetSize(s int)         {}
-func (record *DicRecord) SetOffset(s int)       {}
-func (record *DicRecord) GetOffset() int        { return 0 }
-func (record *DicRecord) Read() error           { return nil }
-func (record *DicRecord) Save() error           { return nil }
-func (record *DicRecord) GetData() []byte       { return nil }
-func (record *DicRecord) SetData(data []byte)   {}
-func (record *DicRecord) GetStringVal() *string { return nil }
-func (record *DicRecord) GetFloatVal() *float64 { return nil }
-func (record *DicRecord) GetUintVal() *uint64   { return nil }
-func (record *DicRecord) GetIntVal() *int64     { return nil }
-func (record *DicRecord) GetBoolVal() *bool     { return nil }
-func (record *DicRecord) GetByteVal() *byte     { return nil }
-func (record *DicRecord) SetStringVal(a string) {}
-func (record *DicRecord) SetFloatVal(a float64) {}
-func (record *DicRecord) SetUintVal(a uint64)   {}
-func (record *DicRecord) SetIntVal(a int64)     {}
-func (record *DicRecord) SetBoolVal(a bool)     {}
-func (record *DicRecord) SetByteVal(a byte)     {}
-func (record *DicRecord) IsDicVariable() bool   { return false }
+func (record *DicRecord) GetDataType() byte                    { return 0x00 }
+func (record *DicRecord) GetDataLen() int                      { return 0 }
+func (record *DicRecord) SetSize(s int)                        {}
+func (record *DicRecord) SetOffset(s int)                      {}
+func (record *DicRecord) GetOffset() int                       { return 0 }
+func (record *DicRecord) Read() error                          { return nil }
+func (record *DicRecord) Save() error                          { return nil }
+func (record *DicRecord) GetData() []byte                      { return nil }
+func (record *DicRecord) SetData(data []byte)                  {}
+func (record *DicRecord) GetStringVal() *string                { return nil }
+func (record *DicRecord) GetFloatVal() *float64                { return nil }
+func (record *DicRecord) GetUintVal() *uint64                  { return nil }
+func (record *DicRecord) GetIntVal() *int64                    { return nil }
+func (record *DicRecord) GetBoolVal() *bool                    { return nil }
+func (record *DicRecord) GetByteVal() *byte                    { return nil }
+func (record *DicRecord) GetTimeVal() *time.Time               { return nil }
+func (record *DicRecord) GetDurationVal() *time.Duration       { return nil }
+func (record *DicRecord) SetStringVal(a string) error          { return errDicNotVariable }
+func (record *DicRecord) SetFloatVal(a float64) error          { return errDicNotVariable }
+func (record *DicRecord) SetUintVal(a uint64) error            { return errDicNotVariable }
+func (record *DicRecord) SetIntVal(a int64) error              { return errDicNotVariable }
+func (record *DicRecord) SetBoolVal(a bool) error              { return errDicNotVariable }
+func (record *DicRecord) SetByteVal(a byte) error              { return errDicNotVariable }
+func (record *DicRecord) SetTimeVal(a time.Time) error         { return errDicNotVariable }
+func (record *DicRecord) SetDurationVal(a time.Duration) error { return errDicNotVariable }
+func (record *DicRecord) Value() (any, error)                  { return nil, errDicNotVariable }
+func (record *DicRecord) SetValue(a any) error                 { return errDicNotVariable }
+func (record *DicRecord) IsDicVariable() bool                  { return false }
 
 // SetSDO to DicRecord
 func (record *DicRecord) SetSDO(sdo *SDOClient) {
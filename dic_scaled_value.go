@@ -0,0 +1,152 @@
+package canopen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// DicScaledValue is a variable value in engineering unit
+type DicScaledValue struct {
+	Value float64
+	Unit  string
+}
+
+// String format scaled value with its unit, eg: "42.5 °C"
+func (scaled DicScaledValue) String() string {
+	s := strconv.FormatFloat(scaled.Value, 'f', -1, 64)
+	if scaled.Unit == "" {
+		return s
+	}
+
+	return s + " " + scaled.Unit
+}
+
+// factor return variable Factor, 1 if not set
+func (variable *DicVariable) factor() float64 {
+	if variable.Factor == 0 {
+		return 1
+	}
+
+	return variable.Factor
+}
+
+// ScaledValue return variable value converted in engineering unit
+func (variable *DicVariable) ScaledValue() (DicScaledValue, error) {
+	raw, err := Value[float64](variable)
+	if err != nil {
+		return DicScaledValue{}, err
+	}
+
+	// Round to 15 significant digits, to drop binary representation noise of
+	// decimal factors, eg: -12 * 0.1 = -1.2000000000000002
+	v := raw*variable.factor() + variable.ScaleOffset
+	v, _ = strconv.ParseFloat(strconv.FormatFloat(v, 'g', 15, 64), 64)
+
+	return DicScaledValue{Value: v, Unit: variable.Unit}, nil
+}
+
+// SetScaledValue set variable value from a value in engineering unit. Integer
+// raw values are rounded to the nearest integer
+func (variable *DicVariable) SetScaledValue(v float64) error {
+	raw := (v - variable.ScaleOffset) / variable.factor()
+
+	if IsIntegerType(variable.DataType) {
+		raw = math.Round(raw)
+	}
+
+	return variable.SetValue(raw)
+}
+
+// DescribedValue return the description of variable value from ValueDescriptions,
+// or the decimal value if it has no description
+func (variable *DicVariable) DescribedValue() (string, error) {
+	raw, err := Value[int64](variable)
+	if err != nil {
+		return "", err
+	}
+
+	if des, ok := variable.ValueDescriptions[raw]; ok {
+		return des, nil
+	}
+
+	return strconv.FormatInt(raw, 10), nil
+}
+
+// bitDefinition return bits of bit field name, checked against variable size
+func (variable *DicVariable) bitDefinition(name string) ([]byte, error) {
+	bits, ok := variable.BitDefinitions[name]
+	if !ok {
+		return nil, fmt.Errorf("0x%X sub %d: bit field %q not defined", variable.Index, variable.SubIndex, name)
+	}
+
+	size := DataTypeSize(variable.DataType)
+	if size == 0 {
+		return nil, variable.errInvalidType(name)
+	}
+
+	for _, bit := range bits {
+		if int(bit) >= size*8 {
+			return nil, fmt.Errorf("0x%X sub %d: bit %d of field %q out of range", variable.Index, variable.SubIndex, bit, name)
+		}
+	}
+
+	return bits, nil
+}
+
+// BitField return the value of bit field name
+func (variable *DicVariable) BitField(name string) (uint64, error) {
+	bits, err := variable.bitDefinition(name)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(variable.Data) < DataTypeSize(variable.DataType) {
+		return 0, errDicDataLen
+	}
+
+	buf := make([]byte, 8)
+	copy(buf, variable.Data)
+	raw := binary.LittleEndian.Uint64(buf)
+
+	var v uint64
+	for i, bit := range bits {
+		v |= (raw >> bit & 1) << uint(i)
+	}
+
+	return v, nil
+}
+
+// BitFieldSet return true if bit field name is not 0
+func (variable *DicVariable) BitFieldSet(name string) (bool, error) {
+	v, err := variable.BitField(name)
+	return v != 0, err
+}
+
+// SetBitField set the value of bit field name, keeping others bits
+func (variable *DicVariable) SetBitField(name string, v uint64) error {
+	bits, err := variable.bitDefinition(name)
+	if err != nil {
+		return err
+	}
+
+	if len(bits) < 64 && v >= 1<<uint(len(bits)) {
+		return fmt.Errorf("0x%X sub %d: value %d out of range for bit field %q", variable.Index, variable.SubIndex, v, name)
+	}
+
+	size := DataTypeSize(variable.DataType)
+	buf := make([]byte, 8)
+	copy(buf, variable.Data)
+	raw := binary.LittleEndian.Uint64(buf)
+
+	for i, bit := range bits {
+		raw &^= 1 << bit
+		raw |= (v >> uint(i) & 1) << bit
+	}
+
+	binary.LittleEndian.PutUint64(buf, raw)
+	variable.Data = buf[:size]
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package canopen
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDicVariableScaledValue(t *testing.T) {
+	variable := &DicVariable{
+		DataType:    Integer16,
+		Unit:        "°C",
+		Factor:      0.5,
+		ScaleOffset: -40,
+		Data:        []byte{0xA5, 0x00},
+	}
+
+	scaled, err := variable.ScaledValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if scaled.String() != "42.5 °C" {
+		t.Fatalf("invalid scaled value %s", scaled)
+	}
+
+	if err := variable.SetScaledValue(-41.2); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := *variable.GetIntVal(); v != -2 {
+		t.Fatalf("invalid raw value %d", v)
+	}
+
+	if err := variable.SetScaledValue(1e6); err == nil {
+		t.Fatal("1e6 °C should be out of range")
+	}
+
+	variable = &DicVariable{DataType: VisibleString, Data: []byte("abc")}
+	if _, err := variable.ScaledValue(); err == nil {
+		t.Fatal("strings should not be scaled")
+	}
+}
+
+func TestDicVariableDescribedValue(t *testing.T) {
+	variable := &DicVariable{DataType: Unsigned8, Data: []byte{0x01}}
+	variable.AddValueDescription(1, "Fault")
+
+	if des, err := variable.DescribedValue(); err != nil || des != "Fault" {
+		t.Fatalf("invalid description %q: %v", des, err)
+	}
+
+	variable.Data = []byte{0x05}
+	if des, err := variable.DescribedValue(); err != nil || des != "5" {
+		t.Fatalf("invalid description %q: %v", des, err)
+	}
+}
+
+func TestDicVariableBitField(t *testing.T) {
+	variable := &DicVariable{DataType: Unsigned16, Data: []byte{0x00, 0x80}}
+	variable.AddBitDefinition("Fault", []byte{15})
+	variable.AddBitDefinition("Mode", []byte{2, 3, 4})
+	variable.AddBitDefinition("Invalid", []byte{16})
+
+	if fault, err := variable.BitFieldSet("Fault"); err != nil || !fault {
+		t.Fatalf("invalid Fault bit: %v", err)
+	}
+
+	if err := variable.SetBitField("Mode", 5); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(variable.Data, []byte{0x14, 0x80}) {
+		t.Fatalf("invalid data %v", variable.Data)
+	}
+
+	if mode, err := variable.BitField("Mode"); err != nil || mode != 5 {
+		t.Fatalf("invalid Mode %d: %v", mode, err)
+	}
+
+	if err := variable.SetBitField("Mode", 8); err == nil {
+		t.Fatal("8 should be out of range for a 3 bits field")
+	}
+
+	if _, err := variable.BitField("Invalid"); err == nil {
+		t.Fatal("bit 16 should be out of range for UNSIGNED16")
+	}
+
+	if _, err := variable.BitField("Unknown"); err == nil {
+		t.Fatal("unknown bit field should return an error")
+	}
+}
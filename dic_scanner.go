@@ -0,0 +1,246 @@
+package canopen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"unicode"
+)
+
+// DicScanRange is an inclusive range of indexes probed by DicScanner
+type DicScanRange struct {
+	From uint16
+	To   uint16
+}
+
+// Object dictionary areas probed by default
+var (
+	DicScanCommunicationArea = DicScanRange{From: 0x1000, To: 0x1FFF}
+	DicScanManufacturerArea  = DicScanRange{From: 0x2000, To: 0x5FFF}
+	DicScanProfileArea       = DicScanRange{From: 0x6000, To: 0x9FFF}
+)
+
+// DicScanner build a best effort object dictionary of a node without EDS, by
+// reading its objects with SDO. Data types are inferred from data lengths, and
+// all objects are read only.
+//
+// A scan stopped by an error or a context cancellation can be resumed by
+// calling Scan again
+type DicScanner struct {
+	SDOClient *SDOClient
+
+	// Ranges to probe, in ascending order
+	Ranges []DicScanRange
+
+	// Interval is the minimum delay between two SDO requests
+	Interval time.Duration
+
+	// ObjectDic contain objects found so far
+	ObjectDic *DicObjectDic
+
+	// Next is the next index to probe, and Done is true once all ranges are probed
+	Next uint16
+	Done bool
+
+	lastRequest time.Time
+}
+
+// NewDicScanner return a DicScanner probing communication, manufacturer and
+// profile areas of sdoClient node
+func NewDicScanner(sdoClient *SDOClient) *DicScanner {
+	objectDic := NewDicObjectDic()
+	objectDic.NodeID = sdoClient.Node.ID
+
+	return &DicScanner{
+		SDOClient: sdoClient,
+		Ranges:    []DicScanRange{DicScanCommunicationArea, DicScanManufacturerArea, DicScanProfileArea},
+		ObjectDic: objectDic,
+	}
+}
+
+// Scan probe remaining indexes until all ranges are done, ctx is canceled or
+// a request fails without an SDO abort. Index in progress is probed again on resume
+func (scanner *DicScanner) Scan(ctx context.Context) (*DicObjectDic, error) {
+	for _, r := range scanner.Ranges {
+		if scanner.Done || r.To < scanner.Next {
+			continue
+		}
+
+		from := r.From
+		if from < scanner.Next {
+			from = scanner.Next
+		}
+
+		for index := int(from); index <= int(r.To); index++ {
+			object, err := scanner.probe(ctx, uint16(index))
+			if err != nil {
+				return scanner.ObjectDic, err
+			}
+
+			if object != nil {
+				scanner.ObjectDic.AddObject(object)
+			}
+
+			// Next would wrap to 0 after the last index
+			if index == 0xFFFF {
+				scanner.Done = true
+				return scanner.ObjectDic, nil
+			}
+
+			scanner.Next = uint16(index + 1)
+		}
+	}
+
+	scanner.Done = true
+	return scanner.ObjectDic, nil
+}
+
+// read an object, waiting Interval since previous request
+func (scanner *DicScanner) read(ctx context.Context, index uint16, subIndex uint8) ([]byte, error) {
+	if wait := time.Until(scanner.lastRequest.Add(scanner.Interval)); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	} else if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	defer func() { scanner.lastRequest = time.Now() }()
+	return scanner.SDOClient.Read(index, subIndex)
+}
+
+// probe index, return nil if it does not exist
+func (scanner *DicScanner) probe(ctx context.Context, index uint16) (DicObject, error) {
+	data, err := scanner.read(ctx, index, 0)
+	if err != nil {
+		if isSDOAbortNotFound(err) {
+			return nil, nil
+		}
+
+		return scanner.unreadableVariable(index, 0, err)
+	}
+
+	// Sub 0 of arrays and records is the UNSIGNED8 highest sub index
+	if len(data) != 1 {
+		return scanner.variable(index, 0, data), nil
+	}
+
+	sub1, err := scanner.read(ctx, index, 1)
+	if isSDOAbortNotFound(err) {
+		return scanner.variable(index, 0, data), nil
+	}
+
+	record := &DicRecord{Index: index, Name: fmt.Sprintf("Object %04Xh", index)}
+	record.AddMember(&DicVariable{
+		Index:      index,
+		SubIndex:   0,
+		Name:       "Highest sub-index supported",
+		DataType:   Unsigned8,
+		AccessType: "ro",
+		Data:       data,
+	})
+
+	last := int(data[0])
+	if last == 0 {
+		last = 1
+	}
+
+	for subIndex := 1; subIndex <= last; subIndex++ {
+		if subIndex > 1 {
+			sub1, err = scanner.read(ctx, index, uint8(subIndex))
+		}
+
+		var member DicObject
+		switch {
+		case err == nil:
+			member = scanner.variable(index, uint8(subIndex), sub1)
+		case isSDOAbortNotFound(err):
+			continue
+		default:
+			if member, err = scanner.unreadableVariable(index, uint8(subIndex), err); err != nil {
+				return nil, err
+			}
+		}
+
+		record.AddMember(member)
+	}
+
+	return record, nil
+}
+
+// variable return a read only variable of data, typed from data length
+func (scanner *DicScanner) variable(index uint16, subIndex uint8, data []byte) *DicVariable {
+	name := fmt.Sprintf("Object %04Xh", index)
+	if subIndex > 0 {
+		name = fmt.Sprintf("Sub %Xh", subIndex)
+	}
+
+	return &DicVariable{
+		Index:      index,
+		SubIndex:   subIndex,
+		Name:       name,
+		DataType:   dicScanDataType(data),
+		AccessType: "ro",
+		Data:       data,
+	}
+}
+
+// unreadableVariable return a DOMAIN variable for an object existing but
+// aborting reads, or err if it was not an SDO abort
+func (scanner *DicScanner) unreadableVariable(index uint16, subIndex uint8, err error) (*DicVariable, error) {
+	var abortErr *SDOAbortError
+	if !errors.As(err, &abortErr) {
+		return nil, err
+	}
+
+	variable := scanner.variable(index, subIndex, nil)
+	if abortErr.Code == SDOAbortWriteOnly {
+		variable.AccessType = "wo"
+	}
+
+	return variable, nil
+}
+
+// dicScanDataType infer a data type from data. Printable data of 3 bytes or
+// more are strings, as integers rarely have no zero or control byte
+func dicScanDataType(data []byte) byte {
+	if len(data) >= 3 && dicScanPrintable(data) {
+		return VisibleString
+	}
+
+	switch len(data) {
+	case 1:
+		return Unsigned8
+	case 2:
+		return Unsigned16
+	case 3:
+		return Unsigned24
+	case 4:
+		return Unsigned32
+	case 5:
+		return Unsigned40
+	case 6:
+		return Unsigned48
+	case 7:
+		return Unsigned56
+	case 8:
+		return Unsigned64
+	}
+
+	return Domain
+}
+
+func dicScanPrintable(data []byte) bool {
+	for _, b := range data {
+		if b > unicode.MaxASCII || !unicode.IsPrint(rune(b)) {
+			return false
+		}
+	}
+
+	return true
+}
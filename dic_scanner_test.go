@@ -0,0 +1,119 @@
+package canopen
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDicScanner(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 9)
+	server.Set(0x1000, 0, []byte{0x91, 0x01, 0x02, 0x00})
+	server.Set(0x1008, 0, []byte("Drive X1"))
+	server.Set(0x1017, 0, []byte{0xE8, 0x03})
+	server.Set(0x1018, 0, []byte{0x04})
+	server.Set(0x1018, 1, []byte{0x2A, 0x00, 0x00, 0x00})
+	server.Set(0x1018, 2, []byte{0x01, 0x00, 0x00, 0x00})
+	server.Set(0x1018, 4, []byte{0x0F, 0x00, 0x00, 0x00})
+	server.Set(0x1001, 0, []byte{0x00})
+
+	node := NewNode(9, network, nil)
+	scanner := NewDicScanner(NewSDOClient(node))
+	scanner.Ranges = []DicScanRange{{From: 0x1000, To: 0x1008}, {From: 0x1017, To: 0x1018}}
+	scanner.Interval = time.Millisecond
+
+	// Stop before first request, then resume
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := scanner.Scan(ctx); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	if scanner.Done || scanner.Next != 0 {
+		t.Fatalf("unexpected progress %d %v", scanner.Next, scanner.Done)
+	}
+
+	objectDic, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !scanner.Done || len(objectDic.Indexes) != 5 {
+		t.Fatalf("expected 5 objects, got %d", len(objectDic.Indexes))
+	}
+
+	expected := []struct {
+		index    uint16
+		subIndex uint8
+		dataType byte
+	}{
+		{0x1000, 0, Unsigned32},
+		{0x1001, 0, Unsigned8},
+		{0x1008, 0, VisibleString},
+		{0x1017, 0, Unsigned16},
+		{0x1018, 0, Unsigned8},
+		{0x1018, 1, Unsigned32},
+		{0x1018, 4, Unsigned32},
+	}
+
+	for _, e := range expected {
+		variable := objectDic.FindVariable(e.index, e.subIndex)
+		if variable == nil {
+			t.Fatalf("0x%04X sub %d not found", e.index, e.subIndex)
+		}
+
+		if variable.DataType != e.dataType || variable.AccessType != "ro" {
+			t.Errorf("0x%04X sub %d: unexpected type 0x%X %s", e.index, e.subIndex, variable.DataType, variable.AccessType)
+		}
+	}
+
+	if objectDic.FindVariable(0x1018, 3) != nil {
+		t.Error("0x1018 sub 3 should not exist")
+	}
+
+	if _, ok := objectDic.FindIndex(0x1018).(*DicRecord); !ok {
+		t.Error("0x1018 should be a record")
+	}
+
+	// Export and parse again
+	buf := &bytes.Buffer{}
+	if err := DicDCFExport(objectDic, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := DicEDSParse(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := parsed.FindVariable(0x1008, 0); v == nil || string(v.Data) != "Drive X1" {
+		t.Errorf("unexpected exported 0x1008: %v", v)
+	}
+}
+
+func TestDicScannerLastIndex(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 9)
+	server.Set(0xFFFF, 0, []byte{0x01})
+
+	scanner := NewDicScanner(NewSDOClient(NewNode(9, network, nil)))
+	scanner.Ranges = []DicScanRange{{From: 0xFFFE, To: 0xFFFF}}
+
+	objectDic, err := scanner.Scan(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !scanner.Done || scanner.Next != 0xFFFF || objectDic.FindVariable(0xFFFF, 0) == nil {
+		t.Fatalf("unexpected progress %d %v", scanner.Next, scanner.Done)
+	}
+
+	// Scanning again must not probe from 0
+	written := len(transport.Written())
+	if _, err := scanner.Scan(context.Background()); err != nil || len(transport.Written()) != written {
+		t.Fatalf("done scanner should not send requests: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package canopen
+
+import "fmt"
+
+// DicTypedVariable is a typed accessor of a node variable, used by generated bindings
+type DicTypedVariable[T DicValueType] struct {
+	Index    uint16
+	SubIndex uint8
+	Variable *DicVariable
+}
+
+// NewDicTypedVariable return a typed accessor of node variable at index / subIndex
+func NewDicTypedVariable[T DicValueType](node *Node, index uint16, subIndex uint8) DicTypedVariable[T] {
+	v := DicTypedVariable[T]{Index: index, SubIndex: subIndex}
+
+	if node.ObjectDic != nil {
+		v.Variable = node.ObjectDic.FindVariable(index, subIndex)
+	}
+
+	if v.Variable != nil && node.SDOClient != nil {
+		v.Variable.SetSDO(node.SDOClient)
+	}
+
+	return v
+}
+
+func (v DicTypedVariable[T]) variable() (*DicVariable, error) {
+	if v.Variable == nil {
+		return nil, fmt.Errorf("0x%04X sub %d: variable not found in node object dictionary", v.Index, v.SubIndex)
+	}
+
+	return v.Variable, nil
+}
+
+// Get read variable value using SDO
+func (v DicTypedVariable[T]) Get() (T, error) {
+	var r T
+
+	variable, err := v.variable()
+	if err != nil {
+		return r, err
+	}
+
+	if err := variable.Read(); err != nil {
+		return r, err
+	}
+
+	return Value[T](variable)
+}
+
+// Set write variable value using SDO
+func (v DicTypedVariable[T]) Set(value T) error {
+	variable, err := v.variable()
+	if err != nil {
+		return err
+	}
+
+	// Encode in a copy, to keep variable Data unchanged if write fails
+	encoded := *variable
+	if err := SetValue[T](&encoded, value); err != nil {
+		return err
+	}
+
+	return variable.Write(encoded.Data)
+}
+
+// Value return last read or written variable value, without using SDO
+func (v DicTypedVariable[T]) Value() (T, error) {
+	var r T
+
+	variable, err := v.variable()
+	if err != nil {
+		return r, err
+	}
+
+	return Value[T](variable)
+}
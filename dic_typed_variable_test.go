@@ -0,0 +1,62 @@
+package canopen
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDicTypedVariable(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 5)
+
+	dic := NewDicObjectDic()
+	dic.AddObject(&DicVariable{Index: 0x6040, Name: "Controlword", DataType: Unsigned16, AccessType: "rw"})
+
+	node := NewNode(5, nil, nil)
+	if _, err := network.AddNode(node, dic, false); err != nil {
+		t.Fatal(err)
+	}
+
+	controlword := NewDicTypedVariable[uint16](node, 0x6040, 0)
+	if err := controlword.Set(0x0F); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(server.Get(0x6040, 0), []byte{0x0F, 0x00}) {
+		t.Fatalf("invalid written value %v", server.Get(0x6040, 0))
+	}
+
+	server.Set(0x6040, 0, []byte{0x06, 0x00})
+	if v, err := controlword.Get(); err != nil || v != 0x06 {
+		t.Fatalf("invalid read value %d: %v", v, err)
+	}
+
+	if _, err := NewDicTypedVariable[uint16](node, 0x6041, 0).Get(); err == nil {
+		t.Fatal("unknown variable should return an error")
+	}
+}
+
+func TestPDOFieldValue(t *testing.T) {
+	data := make([]byte, 6)
+	field := PDOField{Offset: 16, DataType: Integer32}
+
+	if err := PDOSetFieldValue(data, field, int32(-2)); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(data, []byte{0, 0, 0xFE, 0xFF, 0xFF, 0xFF}) {
+		t.Fatalf("invalid data %v", data)
+	}
+
+	if v, err := PDOFieldValue[int64](data, field); err != nil || v != -2 {
+		t.Fatalf("invalid value %d: %v", v, err)
+	}
+
+	if _, err := PDOFieldValue[int32](data, PDOField{Offset: 4, DataType: Integer32}); err == nil {
+		t.Fatal("unaligned field should return an error")
+	}
+
+	if _, err := PDOFieldValue[int32](data[:4], field); err == nil {
+		t.Fatal("short data should return an error")
+	}
+}
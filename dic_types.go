@@ -1,24 +1,37 @@
 package canopen
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/angelodlfrtr/go-canopen/utils"
 )
 
 const (
-	DicVar byte = 0x07
-	DicArr byte = 0x08
-	DicRec byte = 0x09
+	DicDomain byte = 0x02
+	DicVar    byte = 0x07
+	DicArr    byte = 0x08
+	DicRec    byte = 0x09
 )
 
 const (
 	Boolean    byte = 0x1
 	Integer8   byte = 0x2
 	Integer16  byte = 0x3
+	Integer24  byte = 0x10
 	Integer32  byte = 0x4
+	Integer40  byte = 0x12
+	Integer48  byte = 0x13
+	Integer56  byte = 0x14
 	Integer64  byte = 0x15
 	Unsigned8  byte = 0x5
 	Unsigned16 byte = 0x6
+	Unsigned24 byte = 0x16
 	Unsigned32 byte = 0x7
+	Unsigned40 byte = 0x18
+	Unsigned48 byte = 0x19
+	Unsigned56 byte = 0x1a
 	Unsigned64 byte = 0x1b
 
 	Real32 byte = 0x8
@@ -28,13 +41,20 @@ const (
 	OctetString   byte = 0xa
 	UnicodeString byte = 0xb
 	Domain        byte = 0xf
+
+	TimeOfDay      byte = 0xc
+	TimeDifference byte = 0xd
 )
 
 func IsSignedType(t byte) bool {
 	return utils.ContainsByte([]byte{
 		Integer8,
 		Integer16,
+		Integer24,
 		Integer32,
+		Integer40,
+		Integer48,
+		Integer56,
 		Integer64,
 	}, t)
 }
@@ -43,7 +63,11 @@ func IsUnsignedType(t byte) bool {
 	return utils.ContainsByte([]byte{
 		Unsigned8,
 		Unsigned16,
+		Unsigned24,
 		Unsigned32,
+		Unsigned40,
+		Unsigned48,
+		Unsigned56,
 		Unsigned64,
 	}, t)
 }
@@ -52,11 +76,19 @@ func IsIntegerType(t byte) bool {
 	return utils.ContainsByte([]byte{
 		Unsigned8,
 		Unsigned16,
+		Unsigned24,
 		Unsigned32,
+		Unsigned40,
+		Unsigned48,
+		Unsigned56,
 		Unsigned64,
 		Integer8,
 		Integer16,
+		Integer24,
 		Integer32,
+		Integer40,
+		Integer48,
+		Integer56,
 		Integer64,
 	}, t)
 }
@@ -72,11 +104,19 @@ func IsNumberType(t byte) bool {
 	return utils.ContainsByte([]byte{
 		Unsigned8,
 		Unsigned16,
+		Unsigned24,
 		Unsigned32,
+		Unsigned40,
+		Unsigned48,
+		Unsigned56,
 		Unsigned64,
 		Integer8,
 		Integer16,
+		Integer24,
 		Integer32,
+		Integer40,
+		Integer48,
+		Integer56,
 		Integer64,
 		Real32,
 		Real64,
@@ -99,3 +139,82 @@ func IsDataType(t byte) bool {
 		Domain,
 	}, t)
 }
+
+// DataTypeSize return the size in bytes of a data type, or 0 for variable length types
+func DataTypeSize(t byte) int {
+	switch t {
+	case Boolean, Integer8, Unsigned8:
+		return 1
+	case Integer16, Unsigned16:
+		return 2
+	case Integer24, Unsigned24:
+		return 3
+	case Integer32, Unsigned32, Real32:
+		return 4
+	case Integer40, Unsigned40:
+		return 5
+	case Integer48, Unsigned48, TimeOfDay, TimeDifference:
+		return 6
+	case Integer56, Unsigned56:
+		return 7
+	case Integer64, Unsigned64, Real64:
+		return 8
+	}
+
+	return 0
+}
+
+var dataTypeNames = map[byte]string{
+	Boolean:        "BOOLEAN",
+	Integer8:       "INTEGER8",
+	Integer16:      "INTEGER16",
+	Integer24:      "INTEGER24",
+	Integer32:      "INTEGER32",
+	Integer40:      "INTEGER40",
+	Integer48:      "INTEGER48",
+	Integer56:      "INTEGER56",
+	Integer64:      "INTEGER64",
+	Unsigned8:      "UNSIGNED8",
+	Unsigned16:     "UNSIGNED16",
+	Unsigned24:     "UNSIGNED24",
+	Unsigned32:     "UNSIGNED32",
+	Unsigned40:     "UNSIGNED40",
+	Unsigned48:     "UNSIGNED48",
+	Unsigned56:     "UNSIGNED56",
+	Unsigned64:     "UNSIGNED64",
+	Real32:         "REAL32",
+	Real64:         "REAL64",
+	VisibleString:  "VISIBLE_STRING",
+	OctetString:    "OCTET_STRING",
+	UnicodeString:  "UNICODE_STRING",
+	Domain:         "DOMAIN",
+	TimeOfDay:      "TIME_OF_DAY",
+	TimeDifference: "TIME_DIFFERENCE",
+}
+
+// DataTypeName return the CiA 301 name of a data type, eg: UNSIGNED8, or its hex
+// value if unknown
+func DataTypeName(t byte) string {
+	if name, ok := dataTypeNames[t]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("0x%04X", t)
+}
+
+// ParseDataType return the data type of a CiA 301 name, or of a number
+func ParseDataType(s string) (byte, error) {
+	s = strings.TrimSpace(s)
+	for t, name := range dataTypeNames {
+		if strings.EqualFold(name, s) {
+			return t, nil
+		}
+	}
+
+	t, err := strconv.ParseUint(s, 0, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid data type %q", s)
+	}
+
+	return byte(t), nil
+}
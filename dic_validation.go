@@ -0,0 +1,138 @@
+package canopen
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Validation errors, wrapped in DicValidationError
+var (
+	ErrDicNotReadable = errors.New("object is not readable")
+	ErrDicNotWritable = errors.New("object is not writable")
+	ErrDicOutOfRange  = errors.New("value out of range")
+	ErrDicInvalidSize = errors.New("invalid data size")
+)
+
+// DicValidationError is returned when an access or a value is refused by the object
+// dictionary before using the bus. Use errors.Is to check its cause
+type DicValidationError struct {
+	Index    uint16
+	SubIndex uint8
+	Err      error
+	Detail   string
+}
+
+func (e *DicValidationError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("0x%04X sub %d: %v", e.Index, e.SubIndex, e.Err)
+	}
+
+	return fmt.Sprintf("0x%04X sub %d: %v: %s", e.Index, e.SubIndex, e.Err, e.Detail)
+}
+
+func (e *DicValidationError) Unwrap() error {
+	return e.Err
+}
+
+func (variable *DicVariable) validationError(err error, format string, args ...interface{}) error {
+	return &DicValidationError{
+		Index:    variable.Index,
+		SubIndex: variable.SubIndex,
+		Err:      err,
+		Detail:   fmt.Sprintf(format, args...),
+	}
+}
+
+// IsReadable return false for write only objects
+func (variable *DicVariable) IsReadable() bool {
+	return variable.AccessType != "wo"
+}
+
+// IsWritable return false for read only and constant objects
+func (variable *DicVariable) IsWritable() bool {
+	return variable.AccessType != "ro" && variable.AccessType != "const"
+}
+
+// CheckAccess return a DicValidationError if variable AccessType does not allow
+// to read, or to write if write is true
+func (variable *DicVariable) CheckAccess(write bool) error {
+	if write && !variable.IsWritable() {
+		return variable.validationError(ErrDicNotWritable, "access type %q", variable.AccessType)
+	}
+
+	if !write && !variable.IsReadable() {
+		return variable.validationError(ErrDicNotReadable, "access type %q", variable.AccessType)
+	}
+
+	return nil
+}
+
+// Validate return a DicValidationError if data size does not match variable DataType,
+// or if data value is out of variable limits
+func (variable *DicVariable) Validate(data []byte) error {
+	size := DataTypeSize(variable.DataType)
+	if size == 0 {
+		return nil
+	}
+
+	if len(data) != size {
+		return variable.validationError(ErrDicInvalidSize, "%d bytes, expected %d", len(data), size)
+	}
+
+	if !variable.HasMin && !variable.HasMax {
+		return nil
+	}
+
+	switch {
+	case IsSignedType(variable.DataType):
+		v, _ := decodeDicInt(variable.DataType, data)
+		if variable.HasMin && v < int64(variable.Min) || variable.HasMax && v > int64(variable.Max) {
+			return variable.rangeError(v)
+		}
+	case IsUnsignedType(variable.DataType):
+		// Limits of UNSIGNED64 above math.MaxInt64 are stored in two's complement
+		v, _ := decodeDicUint(variable.DataType, data)
+		if variable.HasMin && variable.Min > 0 && v < uint64(variable.Min) || variable.HasMax && v > uint64(variable.Max) {
+			return variable.rangeError(v)
+		}
+	case IsFloatType(variable.DataType):
+		v, _ := decodeDicFloat(variable.DataType, data)
+		if math.IsNaN(v) || variable.HasMin && v < variable.MinFloat || variable.HasMax && v > variable.MaxFloat {
+			return variable.rangeError(v)
+		}
+	}
+
+	return nil
+}
+
+func (variable *DicVariable) rangeError(v interface{}) error {
+	limits := ""
+	if variable.HasMin {
+		limits += " min " + variable.limitValue(false)
+	}
+
+	if variable.HasMax {
+		limits += " max " + variable.limitValue(true)
+	}
+
+	return variable.validationError(ErrDicOutOfRange, "%v not in limits%s", v, limits)
+}
+
+// FindVariable return the variable at index / subIndex, or nil
+func (objectDic *DicObjectDic) FindVariable(index uint16, subIndex uint8) *DicVariable {
+	switch object := objectDic.FindIndex(index).(type) {
+	case *DicVariable:
+		if subIndex == 0 {
+			return object
+		}
+	case *DicArray:
+		variable, _ := object.SubIndexes[subIndex].(*DicVariable)
+		return variable
+	case *DicRecord:
+		variable, _ := object.SubIndexes[subIndex].(*DicVariable)
+		return variable
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package canopen
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDicVariableValidate(t *testing.T) {
+	variable := &DicVariable{DataType: Integer16, Min: -100, Max: 100, HasMin: true, HasMax: true}
+
+	if err := variable.Validate([]byte{0x9C, 0xFF}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := variable.Validate([]byte{0x9B, 0xFF}); !errors.Is(err, ErrDicOutOfRange) {
+		t.Fatalf("-101 should be out of range, got %v", err)
+	}
+
+	if err := variable.Validate([]byte{0x01}); !errors.Is(err, ErrDicInvalidSize) {
+		t.Fatalf("1 byte should be an invalid size, got %v", err)
+	}
+
+	variable = &DicVariable{DataType: Unsigned64, Max: -1, HasMax: true}
+	if err := variable.Validate([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}); err != nil {
+		t.Fatal(err)
+	}
+
+	variable = &DicVariable{DataType: Unsigned8, Min: 1, HasMin: true}
+	if err := variable.Validate([]byte{0x00}); !errors.Is(err, ErrDicOutOfRange) {
+		t.Fatalf("0 should be out of range, got %v", err)
+	}
+
+	variable = &DicVariable{DataType: VisibleString, Max: 1, HasMax: true}
+	if err := variable.Validate([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSDOClientStrict(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 4)
+
+	dic := NewDicObjectDic()
+	dic.AddObject(&DicVariable{Index: 0x1000, Name: "Device type", DataType: Unsigned32, AccessType: "ro"})
+	dic.AddObject(&DicVariable{Index: 0x2000, Name: "Password", DataType: Unsigned32, AccessType: "wo"})
+	dic.AddObject(&DicVariable{Index: 0x2001, Name: "Setpoint", DataType: Unsigned8, AccessType: "rw", Max: 10, HasMax: true})
+
+	node := NewNode(4, nil, nil)
+	if _, err := network.AddNode(node, dic, false); err != nil {
+		t.Fatal(err)
+	}
+	node.SDOClient.Strict = true
+
+	err := node.SDOClient.Write(0x1000, 0, false, []byte{0x01, 0x00, 0x00, 0x00})
+	if !errors.Is(err, ErrDicNotWritable) {
+		t.Fatalf("0x1000 should not be writable, got %v", err)
+	}
+
+	if _, err := node.SDOClient.Read(0x2000, 0); !errors.Is(err, ErrDicNotReadable) {
+		t.Fatalf("0x2000 should not be readable, got %v", err)
+	}
+
+	// Variables accesses are checked once, by the SDO client
+	if err := node.SDOClient.FindName("Password").Read(); !errors.Is(err, ErrDicNotReadable) {
+		t.Fatalf("Password should not be readable, got %v", err)
+	}
+
+	setpoint := node.SDOClient.FindName("Setpoint")
+	if err := setpoint.(*DicVariable).Write([]byte{11}); !errors.Is(err, ErrDicOutOfRange) {
+		t.Fatalf("11 should be out of range, got %v", err)
+	}
+
+	if len(transport.Written()) != 0 {
+		t.Fatalf("refused accesses should not use the bus, %d frames written", len(transport.Written()))
+	}
+
+	if err := setpoint.(*DicVariable).Write([]byte{10}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(server.Get(0x2001, 0), []byte{10}) {
+		t.Fatalf("invalid written value %v", server.Get(0x2001, 0))
+	}
+}
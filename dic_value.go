@@ -0,0 +1,268 @@
+package canopen
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// errDicNotVariable is returned by value accessors of arrays and records
+var errDicNotVariable = errors.New("object is not a variable")
+
+var dicTimeType = reflect.TypeOf(time.Time{})
+
+// DicValueType is the set of Go types usable with Value and SetValue
+type DicValueType interface {
+	~bool |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64 |
+		~string | ~[]byte |
+		time.Time
+}
+
+// Value return object value converted to T, or an error if object value
+// cannot be represented as T
+func Value[T DicValueType](object DicObject) (T, error) {
+	var r T
+
+	v, err := object.Value()
+	if err != nil {
+		return r, err
+	}
+
+	if err := dicConvertValue(v, reflect.ValueOf(&r).Elem()); err != nil {
+		return r, fmt.Errorf("0x%X sub %d: %v", object.GetIndex(), object.GetSubIndex(), err)
+	}
+
+	return r, nil
+}
+
+// SetValue set object value from v, converted to object DataType. An error is
+// returned if v is out of DataType range
+func SetValue[T DicValueType](object DicObject, v T) error {
+	return object.SetValue(v)
+}
+
+// Value return variable value as bool, int64, uint64, float64, string, []byte,
+// time.Time or time.Duration depending on variable DataType
+func (variable *DicVariable) Value() (any, error) {
+	var v any
+	var err error
+
+	switch dataType := variable.DataType; {
+	case dataType == Boolean:
+		if len(variable.Data) < 1 {
+			return nil, errDicDataLen
+		}
+
+		v = variable.Data[0] != 0
+	case IsSignedType(dataType):
+		v, err = decodeDicInt(dataType, variable.Data)
+	case IsUnsignedType(dataType):
+		v, err = decodeDicUint(dataType, variable.Data)
+	case IsFloatType(dataType):
+		v, err = decodeDicFloat(dataType, variable.Data)
+	case dataType == VisibleString:
+		v = string(variable.Data)
+	case dataType == UnicodeString:
+		v = decodeDicUnicode(variable.Data)
+	case dataType == OctetString || dataType == Domain:
+		v = append([]byte{}, variable.Data...)
+	case dataType == TimeOfDay:
+		v, err = decodeDicTime(variable.Data)
+	case dataType == TimeDifference:
+		v, err = decodeDicDuration(variable.Data)
+	default:
+		err = fmt.Errorf("unsupported data type 0x%X", dataType)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("0x%X sub %d: %v", variable.Index, variable.SubIndex, err)
+	}
+
+	return v, nil
+}
+
+// SetValue set variable value from any DicValueType value, converted to
+// variable DataType with range checks
+func (variable *DicVariable) SetValue(v any) error {
+	rv := reflect.ValueOf(v)
+	dataType := variable.DataType
+
+	switch {
+	case !rv.IsValid():
+		return variable.errInvalidType(v)
+	case rv.Type() == dicTimeType:
+		return variable.SetTimeVal(v.(time.Time))
+	case dataType == TimeDifference && rv.Kind() == reflect.Int64:
+		return variable.SetDurationVal(time.Duration(rv.Int()))
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return variable.SetBoolVal(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if IsFloatType(dataType) {
+			return variable.SetFloatVal(float64(rv.Int()))
+		}
+
+		return variable.SetIntVal(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if IsFloatType(dataType) {
+			return variable.SetFloatVal(float64(rv.Uint()))
+		}
+
+		return variable.SetUintVal(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+
+		if !IsIntegerType(dataType) {
+			return variable.SetFloatVal(f)
+		}
+
+		if f != math.Trunc(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("0x%X sub %d: %g is not an integer", variable.Index, variable.SubIndex, f)
+		}
+
+		if f < 0 {
+			return variable.SetIntVal(int64(f))
+		}
+
+		if f >= math.MaxUint64 {
+			return fmt.Errorf("0x%X sub %d: value %g out of range", variable.Index, variable.SubIndex, f)
+		}
+
+		return variable.SetUintVal(uint64(f))
+	case reflect.String:
+		if dataType == Domain {
+			variable.Data = []byte(rv.String())
+			return nil
+		}
+
+		return variable.SetStringVal(rv.String())
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			break
+		}
+
+		switch dataType {
+		case OctetString, Domain:
+			variable.Data = append([]byte{}, rv.Bytes()...)
+			return nil
+		case VisibleString, UnicodeString:
+			return variable.SetStringVal(string(rv.Bytes()))
+		}
+	}
+
+	return variable.errInvalidType(v)
+}
+
+// dicConvertValue convert v, as returned by DicObject.Value, to out
+func dicConvertValue(v any, out reflect.Value) error {
+	in := reflect.ValueOf(v)
+	outType := out.Type()
+
+	if outType == dicTimeType || in.Type() == dicTimeType {
+		if in.Type() != outType {
+			return fmt.Errorf("cannot convert %T to %s", v, outType)
+		}
+
+		out.Set(in)
+		return nil
+	}
+
+	switch out.Kind() {
+	case reflect.Bool:
+		if in.Kind() == reflect.Bool {
+			out.SetBool(in.Bool())
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+
+		switch in.Kind() {
+		case reflect.Int64:
+			i = in.Int()
+		case reflect.Uint64:
+			if in.Uint() > math.MaxInt64 {
+				return fmt.Errorf("value %d overflows %s", in.Uint(), outType)
+			}
+
+			i = int64(in.Uint())
+		default:
+			return fmt.Errorf("cannot convert %T to %s", v, outType)
+		}
+
+		if out.OverflowInt(i) {
+			return fmt.Errorf("value %d overflows %s", i, outType)
+		}
+
+		out.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+
+		switch in.Kind() {
+		case reflect.Uint64:
+			u = in.Uint()
+		case reflect.Int64:
+			if in.Int() < 0 {
+				return fmt.Errorf("negative value %d overflows %s", in.Int(), outType)
+			}
+
+			u = uint64(in.Int())
+		default:
+			return fmt.Errorf("cannot convert %T to %s", v, outType)
+		}
+
+		if out.OverflowUint(u) {
+			return fmt.Errorf("value %d overflows %s", u, outType)
+		}
+
+		out.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		var f float64
+
+		switch in.Kind() {
+		case reflect.Float64:
+			f = in.Float()
+		case reflect.Int64:
+			f = float64(in.Int())
+		case reflect.Uint64:
+			f = float64(in.Uint())
+		default:
+			return fmt.Errorf("cannot convert %T to %s", v, outType)
+		}
+
+		if out.OverflowFloat(f) {
+			return fmt.Errorf("value %g overflows %s", f, outType)
+		}
+
+		out.SetFloat(f)
+		return nil
+	case reflect.String:
+		switch in.Kind() {
+		case reflect.String:
+			out.SetString(in.String())
+			return nil
+		case reflect.Slice:
+			out.SetString(string(in.Bytes()))
+			return nil
+		}
+	case reflect.Slice:
+		switch in.Kind() {
+		case reflect.Slice:
+			out.SetBytes(append([]byte{}, in.Bytes()...))
+			return nil
+		case reflect.String:
+			out.SetBytes([]byte(in.String()))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cannot convert %T to %s", v, outType)
+}
@@ -0,0 +1,105 @@
+package canopen
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type testMode uint8
+
+func TestValue(t *testing.T) {
+	variable := &DicVariable{DataType: Integer16, Data: []byte{0xF4, 0xFF}}
+
+	if v, err := Value[int32](variable); err != nil || v != -12 {
+		t.Fatalf("invalid int32 value %d: %v", v, err)
+	}
+
+	if v, err := Value[float64](variable); err != nil || v != -12 {
+		t.Fatalf("invalid float64 value %g: %v", v, err)
+	}
+
+	if _, err := Value[uint16](variable); err == nil {
+		t.Fatal("negative value should overflow uint16")
+	}
+
+	if _, err := Value[string](variable); err == nil {
+		t.Fatal("INTEGER16 should not be converted to string")
+	}
+
+	variable = &DicVariable{DataType: Unsigned16, Data: []byte{0x2C, 0x01}}
+	if _, err := Value[int8](variable); err == nil {
+		t.Fatal("300 should overflow int8")
+	}
+
+	if v, err := Value[testMode](&DicVariable{DataType: Unsigned8, Data: []byte{0x03}}); err != nil || v != 3 {
+		t.Fatalf("invalid named type value %d: %v", v, err)
+	}
+
+	if v, err := Value[string](&DicVariable{DataType: VisibleString, Data: []byte("abc")}); err != nil || v != "abc" {
+		t.Fatalf("invalid string value %q: %v", v, err)
+	}
+
+	if v, err := Value[time.Duration](&DicVariable{DataType: TimeDifference, Data: []byte{0xE8, 0x03, 0, 0, 0, 0}}); err != nil || v != time.Second {
+		t.Fatalf("invalid duration value %s: %v", v, err)
+	}
+
+	if _, err := Value[int](&DicArray{}); err == nil {
+		t.Fatal("array should not have a value")
+	}
+
+	if err := (&DicRecord{}).SetUintVal(1); err != errDicNotVariable {
+		t.Fatalf("record should not have a value, got %v", err)
+	}
+
+	if err := (&DicArray{}).SetStringVal("a"); err != errDicNotVariable {
+		t.Fatalf("array should not have a value, got %v", err)
+	}
+}
+
+func TestSetValue(t *testing.T) {
+	variable := &DicVariable{DataType: Unsigned16}
+
+	if err := SetValue(variable, 300); err != nil || !bytes.Equal(variable.Data, []byte{0x2C, 0x01}) {
+		t.Fatalf("invalid data %v: %v", variable.Data, err)
+	}
+
+	if err := SetValue(variable, 70000); err == nil {
+		t.Fatal("70000 should be out of range for UNSIGNED16")
+	}
+
+	if err := SetValue(variable, -1); err == nil {
+		t.Fatal("-1 should be out of range for UNSIGNED16")
+	}
+
+	if err := SetValue(variable, 2.5); err == nil {
+		t.Fatal("2.5 should not be set on UNSIGNED16")
+	}
+
+	if err := SetValue(variable, 2.0); err != nil || !bytes.Equal(variable.Data, []byte{0x02, 0x00}) {
+		t.Fatalf("invalid data %v: %v", variable.Data, err)
+	}
+
+	variable = &DicVariable{DataType: Real32}
+	if err := SetValue(variable, int8(-3)); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, err := variable.Value(); err != nil || v.(float64) != -3 {
+		t.Fatalf("invalid value %v: %v", v, err)
+	}
+
+	variable = &DicVariable{DataType: TimeDifference}
+	if err := SetValue(variable, time.Second); err != nil || !bytes.Equal(variable.Data, []byte{0xE8, 0x03, 0, 0, 0, 0}) {
+		t.Fatalf("invalid data %v: %v", variable.Data, err)
+	}
+
+	variable = &DicVariable{DataType: OctetString}
+	if err := variable.SetValue([]byte{0x01, 0x02}); err != nil || !bytes.Equal(variable.Data, []byte{0x01, 0x02}) {
+		t.Fatalf("invalid data %v: %v", variable.Data, err)
+	}
+
+	if err := variable.SetValue(true); err == nil {
+		t.Fatal("bool should not be set on OCTET_STRING")
+	}
+}
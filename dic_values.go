@@ -0,0 +1,246 @@
+package canopen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// dicNodeIDVar is the node id variable usable in EDS / XDD values, eg: $NODEID+0x180
+const dicNodeIDVar = "$NODEID"
+
+// hasNodeIDVar return true if s contain a $NODEID expression
+func hasNodeIDVar(s string) bool {
+	return strings.Contains(strings.ToUpper(s), dicNodeIDVar)
+}
+
+// parseDicUint parse an unsigned integer literal, decimal, 0x hexadecimal or 0 octal
+func parseDicUint(s string, bitSize int) (uint64, error) {
+	v, err := strconv.ParseUint(s, 0, bitSize)
+	if err != nil {
+		// Decimal numbers with leading zeros, eg: 08
+		if v, err2 := strconv.ParseUint(s, 10, bitSize); err2 == nil {
+			return v, nil
+		}
+	}
+
+	return v, err
+}
+
+// evalDicUint evaluate an unsigned integer expression, which can be a literal or
+// a sum of literals and $NODEID, eg: $NODEID+0x180
+func evalDicUint(s string, nodeID int, bitSize int) (uint64, error) {
+	if !hasNodeIDVar(s) {
+		return parseDicUint(strings.TrimSpace(s), bitSize)
+	}
+
+	var sum uint64
+
+	for _, term := range strings.Split(s, "+") {
+		term = strings.TrimSpace(term)
+
+		if strings.ToUpper(term) == dicNodeIDVar {
+			sum += uint64(nodeID)
+			continue
+		}
+
+		v, err := parseDicUint(term, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid expression %q: %v", s, err)
+		}
+
+		sum += v
+	}
+
+	if bitSize < 64 && sum >= 1<<uint(bitSize) {
+		return 0, fmt.Errorf("expression %q value %d out of range", s, sum)
+	}
+
+	return sum, nil
+}
+
+// evalDicInt evaluate a signed integer expression. See evalDicUint
+func evalDicInt(s string, nodeID int, bitSize int) (int64, error) {
+	s = strings.TrimSpace(s)
+
+	if strings.HasPrefix(s, "-") {
+		v, err := evalDicUint(s[1:], nodeID, 64)
+		if err != nil {
+			return 0, err
+		}
+
+		if bitSize < 64 && v > 1<<uint(bitSize-1) || v > 1<<63 {
+			return 0, fmt.Errorf("value %s out of range", s)
+		}
+
+		return -int64(v), nil
+	}
+
+	v, err := evalDicUint(s, nodeID, bitSize)
+	return int64(v), err
+}
+
+// isDicHex return true for 0x hexadecimal literals
+func isDicHex(s string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(s)), "0x")
+}
+
+// parseDicFloat parse a REAL32 or REAL64 value, in decimal notation or as its
+// 0x hexadecimal IEEE 754 bit pattern, eg: 0x3F800000 for 1.0
+func parseDicFloat(dataType byte, s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	size := DataTypeSize(dataType)
+
+	if isDicHex(s) {
+		bits, err := parseDicUint(s, size*8)
+		if err != nil {
+			return 0, err
+		}
+
+		data := make([]byte, 8)
+		binary.LittleEndian.PutUint64(data, bits)
+
+		return decodeDicFloat(dataType, data[:size])
+	}
+
+	return strconv.ParseFloat(s, size*8)
+}
+
+// encodeDicValue encode a value in EDS / XDD notation (decimal, 0x hexadecimal
+// or 0 octal numbers, $NODEID expressions, raw strings) to its little endian
+// representation for dataType
+func encodeDicValue(dataType byte, s string, nodeID int) ([]byte, error) {
+	s = strings.TrimSpace(s)
+
+	switch {
+	case dataType == VisibleString:
+		return []byte(s), nil
+	case dataType == UnicodeString:
+		return encodeDicUnicode(s), nil
+	case dataType == OctetString || dataType == Domain:
+		data, err := hex.DecodeString(strings.TrimPrefix(strings.ReplaceAll(s, " ", ""), "0x"))
+		if err != nil {
+			// Domains can contain anything
+			if dataType == Domain {
+				return []byte(s), nil
+			}
+
+			return nil, fmt.Errorf("invalid hex value %q: %v", s, err)
+		}
+
+		return data, nil
+	}
+
+	size := DataTypeSize(dataType)
+	if size == 0 {
+		return nil, fmt.Errorf("unsupported data type 0x%X", dataType)
+	}
+
+	buf := make([]byte, 8)
+
+	switch {
+	case dataType == Boolean:
+		switch strings.ToLower(s) {
+		case "true":
+			buf[0] = 1
+		case "false":
+			buf[0] = 0
+		default:
+			v, err := evalDicUint(s, nodeID, 8)
+			if err != nil {
+				return nil, err
+			}
+
+			if v > 1 {
+				return nil, fmt.Errorf("invalid boolean value %q", s)
+			}
+
+			buf[0] = byte(v)
+		}
+	case IsFloatType(dataType) && isDicHex(s):
+		// IEEE 754 bit pattern, eg: 0x3F800000 for 1.0
+		bits, err := parseDicUint(s, size*8)
+		if err != nil {
+			return nil, err
+		}
+
+		binary.LittleEndian.PutUint64(buf, bits)
+	case IsFloatType(dataType):
+		f, err := strconv.ParseFloat(s, size*8)
+		if err != nil {
+			return nil, err
+		}
+
+		return encodeDicFloat(dataType, f)
+	case IsSignedType(dataType) && strings.HasPrefix(s, "-"):
+		i, err := evalDicInt(s, nodeID, size*8)
+		if err != nil {
+			return nil, err
+		}
+
+		binary.LittleEndian.PutUint64(buf, uint64(i))
+	default:
+		// Positive signed values can be given in two's complement, eg: 0xFFFF
+		i, err := evalDicUint(s, nodeID, size*8)
+		if err != nil {
+			return nil, err
+		}
+
+		binary.LittleEndian.PutUint64(buf, i)
+	}
+
+	return buf[:size], nil
+}
+
+// DicEncodeValue encode s in EDS notation, eg: 0x10, -5, 1.5, text or $NODEID+0x180,
+// to the little endian representation of dataType
+func DicEncodeValue(dataType byte, s string, nodeID int) ([]byte, error) {
+	return encodeDicValue(dataType, s, nodeID)
+}
+
+// DicFormatValue format data of dataType in EDS notation
+func DicFormatValue(dataType byte, data []byte) string {
+	return formatEDSValue(dataType, data)
+}
+
+// dicJSONValue return data of dataType as a JSON value, a number, a boolean or
+// a string, or nil for other data types
+func dicJSONValue(dataType byte, data []byte) interface{} {
+	switch {
+	case dataType == Boolean && len(data) > 0:
+		return data[0] != 0
+	case dataType == VisibleString:
+		return strings.TrimRight(string(data), "\x00")
+	case dataType == UnicodeString:
+		return decodeDicUnicode(data)
+	case IsFloatType(dataType):
+		if f, err := decodeDicFloat(dataType, data); err == nil {
+			return f
+		}
+	case IsSignedType(dataType):
+		if v, err := decodeDicInt(dataType, data); err == nil {
+			return v
+		}
+	case IsUnsignedType(dataType):
+		if v, err := decodeDicUint(dataType, data); err == nil {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// dicJSONText return the content of a JSON string, or the JSON text of other
+// values, eg: numbers and booleans, to encode with DicEncodeValue
+func dicJSONText(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return string(bytes.TrimSpace(raw))
+	}
+
+	return s
+}
@@ -0,0 +1,65 @@
+package canopen
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodeDicValue(t *testing.T) {
+	tests := []struct {
+		dataType byte
+		value    string
+		expected []byte
+	}{
+		{Unsigned32, "$NODEID+0x180", []byte{0x85, 0x01, 0x00, 0x00}},
+		{Unsigned32, "0x180 + $NodeID", []byte{0x85, 0x01, 0x00, 0x00}},
+		{Unsigned16, "0x1234", []byte{0x34, 0x12}},
+		{Unsigned8, "010", []byte{0x08}},
+		{Unsigned8, "08", []byte{0x08}},
+		{Integer16, "-12", []byte{0xF4, 0xFF}},
+		{Integer16, "0xFFF4", []byte{0xF4, 0xFF}},
+		{Boolean, "true", []byte{0x01}},
+		{VisibleString, "go-canopen", []byte("go-canopen")},
+		{OctetString, "0A0B", []byte{0x0A, 0x0B}},
+		{Real32, "1.5", []byte{0x00, 0x00, 0xC0, 0x3F}},
+		{Real32, "0x3F800000", []byte{0x00, 0x00, 0x80, 0x3F}},
+	}
+
+	for _, test := range tests {
+		data, err := encodeDicValue(test.dataType, test.value, 5)
+		if err != nil {
+			t.Fatalf("%q: %v", test.value, err)
+		}
+
+		if !bytes.Equal(data, test.expected) {
+			t.Fatalf("%q: expected %v, got %v", test.value, test.expected, data)
+		}
+	}
+
+	for _, value := range []string{"0x100", "-1", "foo", "$NODEID+bar"} {
+		if _, err := encodeDicValue(Unsigned8, value, 5); err == nil {
+			t.Fatalf("%q should be invalid for Unsigned8", value)
+		}
+	}
+}
+
+func TestDicObjectDicSetNodeID(t *testing.T) {
+	dic, err := DicEDSParse([]byte("[1014]\nParameterName=COB-ID EMCY\nObjectType=0x7\nDataType=0x0007\nAccessType=rw\nDefaultValue=$NODEID+0x80\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	variable, ok := dic.FindIndex(0x1014).(*DicVariable)
+	if !ok {
+		t.Fatal("0x1014 not found")
+	}
+
+	if variable.DefaultValue != "$NODEID+0x80" {
+		t.Fatalf("invalid DefaultValue %q", variable.DefaultValue)
+	}
+
+	dic.SetNodeID(0x12)
+	if !bytes.Equal(variable.Default, []byte{0x92, 0x00, 0x00, 0x00}) {
+		t.Fatalf("invalid Default %v", variable.Default)
+	}
+}
@@ -1,20 +1,38 @@
 package canopen
 
 import (
-	"encoding/ascii85"
-	"encoding/binary"
 	"errors"
-	"unicode/utf16"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type DicVariable struct {
+	// Engineering unit value is Factor * raw value + ScaleOffset. Factor 0 means 1
 	Unit        string
-	Factor      int
-	Min         int
-	Max         int
-	Default     []byte
+	Factor      float64
+	ScaleOffset float64
+
+	// Min and Max are valid only if HasMin and HasMax are true. Limits of REAL
+	// types are MinFloat and MaxFloat. They are evaluated from LowLimit and
+	// HighLimit, the EDS / XDD notation, which can contain $NODEID expressions
+	Min       int
+	Max       int
+	MinFloat  float64
+	MaxFloat  float64
+	HasMin    bool
+	HasMax    bool
+	LowLimit  string
+	HighLimit string
+
+	// Default is encoded like Data, from DefaultValue which is the EDS / XDD value notation
+	Default      []byte
+	DefaultValue string
+
 	DataType    byte
 	AccessType  string
+	PDOMapping  bool
 	Description string
 
 	SDOClient *SDOClient
@@ -27,8 +45,11 @@ type DicVariable struct {
 	SubIndex uint8
 	Name     string
 
-	ValueDescriptions map[string]string
-	BitDefinitions    map[string][]byte
+	// ValueDescriptions map raw values to their names
+	ValueDescriptions map[int64]string
+
+	// BitDefinitions map bit fields names to their bit numbers, least significant first
+	BitDefinitions map[string][]byte
 }
 
 func (variable *DicVariable) GetIndex() uint16 {
@@ -69,73 +90,171 @@ func (variable *DicVariable) GetDataType() byte {
 	return variable.DataType
 }
 
+// GetDataLen return variable size in bits. For variable length types, size is
+// the current Data length
 func (variable *DicVariable) GetDataLen() int {
-	l := 1
-
-	if variable.DataType == Boolean {
-		l = 1
+	l := DataTypeSize(variable.DataType)
+	if l == 0 {
+		l = len(variable.Data)
 	}
 
-	if variable.DataType == Integer8 {
-		l = 1
+	return l * 8
+}
+
+func (variable *DicVariable) SetSize(s int) {
+	variable.Size = s
+}
+
+func (variable *DicVariable) SetOffset(s int) {
+	variable.Offset = s
+}
+
+func (variable *DicVariable) GetOffset() int {
+	return variable.Offset
+}
+
+// copy return a deep copy of variable, not bound to an SDOClient
+func (variable *DicVariable) copy() *DicVariable {
+	c := *variable
+	c.SDOClient = nil
+	c.Data = dicCopyBytes(variable.Data)
+	c.Default = dicCopyBytes(variable.Default)
+
+	if variable.ValueDescriptions != nil {
+		c.ValueDescriptions = map[int64]string{}
+		for value, des := range variable.ValueDescriptions {
+			c.ValueDescriptions[value] = des
+		}
 	}
 
-	if variable.DataType == Integer16 {
-		l = 2
+	if variable.BitDefinitions != nil {
+		c.BitDefinitions = map[string][]byte{}
+		for name, bits := range variable.BitDefinitions {
+			c.BitDefinitions[name] = dicCopyBytes(bits)
+		}
 	}
 
-	if variable.DataType == Integer32 {
-		l = 4
+	return &c
+}
+
+// setLimit set the high or low limit from a value in EDS / XDD notation, parsed
+// per DataType like default values. The value is kept in LowLimit or HighLimit
+// even if it is invalid, the limit being then unset. Empty values unset the limit
+func (variable *DicVariable) setLimit(value string, nodeID int, high bool) error {
+	value = strings.TrimSpace(value)
+
+	if high {
+		variable.HighLimit, variable.HasMax = value, false
+	} else {
+		variable.LowLimit, variable.HasMin = value, false
 	}
 
-	if variable.DataType == Integer64 {
-		l = 8
+	if value == "" {
+		return nil
 	}
 
-	if variable.DataType == Unsigned8 {
-		l = 1
+	var i int64
+	var f float64
+	var err error
+
+	if IsFloatType(variable.DataType) {
+		f, err = parseDicFloat(variable.DataType, value)
+	} else {
+		i, err = evalDicInt(value, nodeID, 64)
 	}
 
-	if variable.DataType == Unsigned16 {
-		l = 2
+	if err != nil {
+		return err
 	}
 
-	if variable.DataType == Unsigned32 {
-		l = 4
+	if high {
+		variable.Max, variable.MaxFloat, variable.HasMax = int(i), f, true
+	} else {
+		variable.Min, variable.MinFloat, variable.HasMin = int(i), f, true
 	}
 
-	if variable.DataType == Unsigned64 {
-		l = 8
+	return nil
+}
+
+// limitValue return the evaluated high or low limit in EDS notation, empty if not set
+func (variable *DicVariable) limitValue(high bool) string {
+	if high && !variable.HasMax || !high && !variable.HasMin {
+		return ""
 	}
 
-	if variable.DataType == Real32 {
-		l = 4
+	i, f := variable.Min, variable.MinFloat
+	if high {
+		i, f = variable.Max, variable.MaxFloat
 	}
 
-	if variable.DataType == Real64 {
-		l = 8
+	switch {
+	case IsFloatType(variable.DataType):
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	case IsUnsignedType(variable.DataType):
+		return strconv.FormatUint(uint64(i), 10)
 	}
 
-	return l * 8
+	return strconv.Itoa(i)
 }
 
-func (variable *DicVariable) SetSize(s int) {
-	variable.Size = s
+// limitNotation return the high or low limit in its original notation if any,
+// else the evaluated limit, to export it
+func (variable *DicVariable) limitNotation(high bool) string {
+	notation := variable.LowLimit
+	if high {
+		notation = variable.HighLimit
+	}
+
+	if notation != "" {
+		return notation
+	}
+
+	return variable.limitValue(high)
 }
 
-func (variable *DicVariable) SetOffset(s int) {
-	variable.Offset = s
+// dicCopyBytes return a copy of b, nil if b is nil
+func dicCopyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+
+	return append([]byte{}, b...)
 }
 
-func (variable *DicVariable) GetOffset() int {
-	return variable.Offset
+// setDefaultValue set DefaultValue and Default from a value in EDS / XDD notation
+func (variable *DicVariable) setDefaultValue(value string, nodeID int) error {
+	variable.DefaultValue = value
+	variable.Default = nil
+
+	if value == "" {
+		return nil
+	}
+
+	data, err := encodeDicValue(variable.DataType, value, nodeID)
+	if err != nil {
+		return err
+	}
+
+	variable.Default = data
+
+	return nil
 }
 
-func (variable *DicVariable) AddValueDescription(name string, des string) {
-	variable.ValueDescriptions[name] = des
+// AddValueDescription set des as the name of raw value
+func (variable *DicVariable) AddValueDescription(value int64, des string) {
+	if variable.ValueDescriptions == nil {
+		variable.ValueDescriptions = map[int64]string{}
+	}
+
+	variable.ValueDescriptions[value] = des
 }
 
+// AddBitDefinition define the bit field name, made of bits, least significant first
 func (variable *DicVariable) AddBitDefinition(name string, bits []byte) {
+	if variable.BitDefinitions == nil {
+		variable.BitDefinitions = map[string][]byte{}
+	}
+
 	variable.BitDefinitions[name] = bits
 }
 
@@ -155,18 +274,29 @@ func (variable *DicVariable) Read() error {
 	return nil
 }
 
-// Write variable value using SDO
+// Write data using SDO, after data validation against variable limits.
+// variable.Data is updated on success
 func (variable *DicVariable) Write(data []byte) error {
 	if variable.SDOClient == nil {
 		return errors.New("SDOClient required")
 	}
 
-	return variable.SDOClient.Write(
+	if err := variable.Validate(data); err != nil {
+		return err
+	}
+
+	if err := variable.SDOClient.Write(
 		variable.Index,
 		variable.SubIndex,
 		variable.IsDomainDataType(),
-		variable.Data,
-	)
+		data,
+	); err != nil {
+		return err
+	}
+
+	variable.Data = data
+
+	return nil
 }
 
 // Save variable.Data using SDO
@@ -186,188 +316,222 @@ func (variable *DicVariable) SetData(data []byte) {
 	variable.Data = data
 }
 
+// GetStringVal return value of a VISIBLE_STRING, OCTET_STRING or UNICODE_STRING variable
 func (variable *DicVariable) GetStringVal() *string {
 	if !IsStringType(variable.DataType) {
 		return nil
 	}
 
-	// @TODO: check if all working
 	var v string
 
-	if variable.DataType == VisibleString {
-		dst := []byte{}
-		ascii85.Decode(dst, variable.Data, false)
-		v = string(dst)
-	}
-
 	if variable.DataType == UnicodeString {
-		src := make([]uint16, len(variable.Data))
-		for i, r := range variable.Data {
-			src[i] = uint16(r)
-		}
-		dst := utf16.Decode(src)
-		v = string(dst)
-	}
-
-	if len(v) == 0 {
+		v = decodeDicUnicode(variable.Data)
+	} else {
 		v = string(variable.Data)
 	}
 
 	return &v
 }
 
+// GetFloatVal return value of a REAL32 or REAL64 variable
 func (variable *DicVariable) GetFloatVal() *float64 {
-	if !IsFloatType(variable.DataType) {
+	v, err := decodeDicFloat(variable.DataType, variable.Data)
+	if err != nil {
 		return nil
 	}
 
-	var v float64
-
-	// @TODO
-
 	return &v
 }
 
+// GetUintVal return value of an UNSIGNED variable
 func (variable *DicVariable) GetUintVal() *uint64 {
 	if !IsUnsignedType(variable.DataType) {
 		return nil
 	}
 
-	var v uint64
-
-	if variable.DataType == Unsigned8 {
-		v = uint64(variable.Data[0])
+	v, err := decodeDicUint(variable.DataType, variable.Data)
+	if err != nil {
+		return nil
 	}
 
-	if variable.DataType == Unsigned16 {
-		v = uint64(binary.LittleEndian.Uint16(variable.Data))
-	}
+	return &v
+}
 
-	if variable.DataType == Unsigned32 {
-		v = uint64(binary.LittleEndian.Uint32(variable.Data))
+// GetIntVal return value of an INTEGER variable
+func (variable *DicVariable) GetIntVal() *int64 {
+	if !IsSignedType(variable.DataType) {
+		return nil
 	}
 
-	if variable.DataType == Unsigned64 {
-		v = binary.LittleEndian.Uint64(variable.Data)
+	v, err := decodeDicInt(variable.DataType, variable.Data)
+	if err != nil {
+		return nil
 	}
 
 	return &v
 }
 
-func (variable *DicVariable) GetIntVal() *int64 {
-	if !IsSignedType(variable.DataType) {
+// GetBoolVal return value of a BOOLEAN variable
+func (variable *DicVariable) GetBoolVal() *bool {
+	if variable.DataType != Boolean || len(variable.Data) < 1 {
 		return nil
 	}
 
-	var v int64
+	v := variable.Data[0] != 0
 
-	if variable.DataType == Integer8 {
-		v = int64(int8(variable.Data[0]))
-	}
-
-	// @TODO: https://groups.google.com/forum/#!topic/golang-nuts/f1QQkP19G9Q
-	if variable.DataType == Integer16 {
-		v = int64(binary.LittleEndian.Uint16(variable.Data))
-	}
+	return &v
+}
 
-	if variable.DataType == Integer32 {
-		v = int64(binary.LittleEndian.Uint32(variable.Data))
+// GetByteVal return value of an UNSIGNED8 variable
+func (variable *DicVariable) GetByteVal() *byte {
+	if variable.DataType != Unsigned8 || len(variable.Data) < 1 {
+		return nil
 	}
 
-	if variable.DataType == Integer64 {
-		v = int64(binary.LittleEndian.Uint64(variable.Data))
-	}
+	v := variable.Data[0]
 
 	return &v
 }
 
-func (variable *DicVariable) GetBoolVal() *bool {
-	if variable.DataType != Boolean {
+// GetTimeVal return value of a TIME_OF_DAY variable
+func (variable *DicVariable) GetTimeVal() *time.Time {
+	if variable.DataType != TimeOfDay {
 		return nil
 	}
 
-	v := false
-
-	// @TODO
+	v, err := decodeDicTime(variable.Data)
+	if err != nil {
+		return nil
+	}
 
 	return &v
 }
 
-func (variable *DicVariable) GetByteVal() *byte {
-	var v byte
+// GetDurationVal return value of a TIME_DIFFERENCE variable
+func (variable *DicVariable) GetDurationVal() *time.Duration {
+	if variable.DataType != TimeDifference {
+		return nil
+	}
 
-	if variable.DataType == Unsigned8 {
-		v = variable.Data[0]
+	v, err := decodeDicDuration(variable.Data)
+	if err != nil {
+		return nil
 	}
 
 	return &v
 }
 
-func (variable *DicVariable) SetStringVal(a string) {
-	// @TODO
+// setEncoded set variable Data from an encoder result
+func (variable *DicVariable) setEncoded(data []byte, err error) error {
+	if err != nil {
+		return fmt.Errorf("0x%X sub %d: %v", variable.Index, variable.SubIndex, err)
+	}
+
+	variable.Data = data
+
+	return nil
 }
 
-func (variable *DicVariable) SetFloatVal(a float64) {
-	// @TODO
+// errInvalidType return an error for a setter not supported by variable data type
+func (variable *DicVariable) errInvalidType(value interface{}) error {
+	return fmt.Errorf(
+		"0x%X sub %d: cannot set %T value to data type 0x%X",
+		variable.Index,
+		variable.SubIndex,
+		value,
+		variable.DataType,
+	)
 }
 
-func (variable *DicVariable) SetUintVal(a uint64) {
-	if !IsUnsignedType(variable.DataType) {
-		return
-	}
+// SetStringVal set value of a VISIBLE_STRING, OCTET_STRING or UNICODE_STRING variable
+func (variable *DicVariable) SetStringVal(a string) error {
+	switch variable.DataType {
+	case VisibleString:
+		for _, c := range []byte(a) {
+			if c > 0x7F {
+				return fmt.Errorf("0x%X sub %d: invalid VISIBLE_STRING character 0x%X", variable.Index, variable.SubIndex, c)
+			}
+		}
 
-	if variable.DataType == Unsigned8 {
-		variable.Data[0] = byte(a)
+		variable.Data = []byte(a)
+	case OctetString:
+		variable.Data = []byte(a)
+	case UnicodeString:
+		variable.Data = encodeDicUnicode(a)
+	default:
+		return variable.errInvalidType(a)
 	}
 
-	if variable.DataType == Unsigned16 {
-		binary.LittleEndian.PutUint16(variable.Data, uint16(a))
-	}
+	return nil
+}
 
-	if variable.DataType == Unsigned32 {
-		binary.LittleEndian.PutUint32(variable.Data, uint32(a))
+// SetFloatVal set value of a REAL32 or REAL64 variable
+func (variable *DicVariable) SetFloatVal(a float64) error {
+	if !IsFloatType(variable.DataType) {
+		return variable.errInvalidType(a)
 	}
 
-	if variable.DataType == Unsigned64 {
-		binary.LittleEndian.PutUint64(variable.Data, uint64(a))
-	}
+	return variable.setEncoded(encodeDicFloat(variable.DataType, a))
 }
 
-func (variable *DicVariable) SetIntVal(a int64) {
-	if !IsSignedType(variable.DataType) {
-		return
+// SetUintVal set value of an UNSIGNED or INTEGER variable
+func (variable *DicVariable) SetUintVal(a uint64) error {
+	if !IsIntegerType(variable.DataType) {
+		return variable.errInvalidType(a)
 	}
 
-	if variable.DataType == Integer8 {
-		variable.Data[0] = byte(a)
+	return variable.setEncoded(encodeDicUint(variable.DataType, a))
+}
+
+// SetIntVal set value of an INTEGER or UNSIGNED variable
+func (variable *DicVariable) SetIntVal(a int64) error {
+	if !IsIntegerType(variable.DataType) {
+		return variable.errInvalidType(a)
 	}
 
-	// @TODO: https://groups.google.com/forum/#!topic/golang-nuts/f1QQkP19G9Q
-	if variable.DataType == Integer16 {
-		binary.LittleEndian.PutUint16(variable.Data, uint16(a))
+	return variable.setEncoded(encodeDicInt(variable.DataType, a))
+}
+
+// SetBoolVal set value of a BOOLEAN variable
+func (variable *DicVariable) SetBoolVal(a bool) error {
+	if variable.DataType != Boolean {
+		return variable.errInvalidType(a)
 	}
 
-	if variable.DataType == Integer32 {
-		binary.LittleEndian.PutUint32(variable.Data, uint32(a))
+	if a {
+		variable.Data = []byte{0x01}
+	} else {
+		variable.Data = []byte{0x00}
 	}
 
-	if variable.DataType == Integer64 {
-		binary.LittleEndian.PutUint64(variable.Data, uint64(a))
+	return nil
+}
+
+// SetByteVal set value of an UNSIGNED8 variable
+func (variable *DicVariable) SetByteVal(a byte) error {
+	if variable.DataType != Unsigned8 {
+		return variable.errInvalidType(a)
 	}
+
+	variable.Data = []byte{a}
+
+	return nil
 }
 
-func (variable *DicVariable) SetBoolVal(a bool) {
-	if variable.DataType == Boolean {
-		if a {
-			variable.Data[0] = 0x01
-		} else {
-			variable.Data[0] = 0x02
-		}
+// SetTimeVal set value of a TIME_OF_DAY variable
+func (variable *DicVariable) SetTimeVal(a time.Time) error {
+	if variable.DataType != TimeOfDay {
+		return variable.errInvalidType(a)
 	}
+
+	return variable.setEncoded(encodeDicTime(a))
 }
 
-func (variable *DicVariable) SetByteVal(a byte) {
-	if variable.DataType == Unsigned8 {
-		variable.Data[0] = a
+// SetDurationVal set value of a TIME_DIFFERENCE variable
+func (variable *DicVariable) SetDurationVal(a time.Duration) error {
+	if variable.DataType != TimeDifference {
+		return variable.errInvalidType(a)
 	}
+
+	return variable.setEncoded(encodeDicDuration(a))
 }
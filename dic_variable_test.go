@@ -0,0 +1,152 @@
+package canopen
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestDicVariableIntVal(t *testing.T) {
+	tests := []struct {
+		dataType byte
+		value    int64
+		data     []byte
+	}{
+		{Integer8, -2, []byte{0xFE}},
+		{Integer16, -12, []byte{0xF4, 0xFF}},
+		{Integer24, -1, []byte{0xFF, 0xFF, 0xFF}},
+		{Integer32, -100000, []byte{0x60, 0x79, 0xFE, 0xFF}},
+		{Integer40, 0x7F00000000, []byte{0x00, 0x00, 0x00, 0x00, 0x7F}},
+		{Integer48, -2, []byte{0xFE, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+		{Integer56, 1, []byte{0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+		{Integer64, -3, []byte{0xFD, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+	}
+
+	for _, test := range tests {
+		variable := &DicVariable{DataType: test.dataType}
+		if err := variable.SetIntVal(test.value); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(variable.Data, test.data) {
+			t.Fatalf("type 0x%X: expected %v, got %v", test.dataType, test.data, variable.Data)
+		}
+
+		if v := variable.GetIntVal(); v == nil || *v != test.value {
+			t.Fatalf("type 0x%X: invalid decoded value %v", test.dataType, v)
+		}
+	}
+
+	variable := &DicVariable{DataType: Integer24}
+	for _, value := range []int64{1 << 23, -1<<23 - 1} {
+		if err := variable.SetIntVal(value); err == nil {
+			t.Fatalf("%d should be out of range for INTEGER24", value)
+		}
+	}
+}
+
+func TestDicVariableUintVal(t *testing.T) {
+	variable := &DicVariable{DataType: Unsigned24}
+	if err := variable.SetUintVal(0xABCDEF); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(variable.Data, []byte{0xEF, 0xCD, 0xAB}) || *variable.GetUintVal() != 0xABCDEF {
+		t.Fatalf("invalid UNSIGNED24 %v", variable.Data)
+	}
+
+	if err := variable.SetUintVal(0x1000000); err == nil {
+		t.Fatal("0x1000000 should be out of range for UNSIGNED24")
+	}
+
+	variable = &DicVariable{DataType: Unsigned8}
+	if err := variable.SetIntVal(-1); err == nil {
+		t.Fatal("-1 should be out of range for UNSIGNED8")
+	}
+
+	if err := variable.SetFloatVal(1); err == nil {
+		t.Fatal("float should not be settable on UNSIGNED8")
+	}
+
+	variable.Data = nil
+	if variable.GetUintVal() != nil {
+		t.Fatal("empty data should not be decoded")
+	}
+}
+
+func TestDicVariableFloatBoolVal(t *testing.T) {
+	variable := &DicVariable{DataType: Real32}
+	if err := variable.SetFloatVal(1.5); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(variable.Data, []byte{0x00, 0x00, 0xC0, 0x3F}) || *variable.GetFloatVal() != 1.5 {
+		t.Fatalf("invalid REAL32 %v", variable.Data)
+	}
+
+	if err := variable.SetFloatVal(1e300); err == nil {
+		t.Fatal("1e300 should be out of range for REAL32")
+	}
+
+	variable = &DicVariable{DataType: Real64}
+	if err := variable.SetFloatVal(-0.25); err != nil || *variable.GetFloatVal() != -0.25 {
+		t.Fatalf("invalid REAL64 %v: %v", variable.Data, err)
+	}
+
+	variable = &DicVariable{DataType: Boolean}
+	if err := variable.SetBoolVal(true); err != nil || !*variable.GetBoolVal() {
+		t.Fatalf("invalid BOOLEAN %v: %v", variable.Data, err)
+	}
+
+	if err := variable.SetBoolVal(false); err != nil || variable.Data[0] != 0 {
+		t.Fatalf("invalid BOOLEAN %v: %v", variable.Data, err)
+	}
+}
+
+func TestDicVariableStringVal(t *testing.T) {
+	variable := &DicVariable{DataType: VisibleString, Data: []byte("go-canopen")}
+	if *variable.GetStringVal() != "go-canopen" {
+		t.Fatalf("invalid VISIBLE_STRING %q", *variable.GetStringVal())
+	}
+
+	if err := variable.SetStringVal("é"); err == nil {
+		t.Fatal("non ASCII should be invalid for VISIBLE_STRING")
+	}
+
+	variable = &DicVariable{DataType: UnicodeString}
+	if err := variable.SetStringVal("é€"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(variable.Data, []byte{0xE9, 0x00, 0xAC, 0x20}) || *variable.GetStringVal() != "é€" {
+		t.Fatalf("invalid UNICODE_STRING %v", variable.Data)
+	}
+}
+
+func TestDicVariableTimeVal(t *testing.T) {
+	variable := &DicVariable{DataType: TimeOfDay}
+	date := time.Date(1984, time.January, 3, 0, 0, 1, 0, time.UTC)
+
+	if err := variable.SetTimeVal(date); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(variable.Data, []byte{0xE8, 0x03, 0x00, 0x00, 0x02, 0x00}) || !variable.GetTimeVal().Equal(date) {
+		t.Fatalf("invalid TIME_OF_DAY %v", variable.Data)
+	}
+
+	if err := variable.SetTimeVal(time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Fatal("time before 1984 should be invalid")
+	}
+
+	variable = &DicVariable{DataType: TimeDifference}
+	d := 25*time.Hour + 500*time.Millisecond
+
+	if err := variable.SetDurationVal(d); err != nil {
+		t.Fatal(err)
+	}
+
+	if *variable.GetDurationVal() != d {
+		t.Fatalf("invalid TIME_DIFFERENCE %v", variable.Data)
+	}
+}
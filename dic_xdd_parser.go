@@ -0,0 +1,517 @@
+package canopen
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// xddContainer is the root of a CiA 311 XDD / XDC file (ISO 15745 profile container)
+type xddContainer struct {
+	XMLName  xml.Name     `xml:"ISO15745ProfileContainer"`
+	Profiles []xddProfile `xml:"ISO15745Profile"`
+}
+
+type xddProfile struct {
+	Body xddProfileBody `xml:"ProfileBody"`
+}
+
+// xddProfileBody merge ProfileBody_Device_CANopen and ProfileBody_CommunicationNetwork_CANopen
+type xddProfileBody struct {
+	// ProfileBody_Device_CANopen
+	DeviceIdentity     *xddDeviceIdentity     `xml:"DeviceIdentity"`
+	ApplicationProcess *xddApplicationProcess `xml:"ApplicationProcess"`
+
+	// ProfileBody_CommunicationNetwork_CANopen
+	ApplicationLayers *xddApplicationLayers `xml:"ApplicationLayers"`
+	TransportLayers   *xddTransportLayers   `xml:"TransportLayers"`
+	NetworkManagement *xddNetworkManagement `xml:"NetworkManagement"`
+}
+
+type xddDeviceIdentity struct {
+	VendorName  string `xml:"vendorName"`
+	VendorID    string `xml:"vendorID"`
+	ProductName string `xml:"productName"`
+	ProductID   string `xml:"productID"`
+	OrderNumber string `xml:"orderNumber"`
+}
+
+// xddApplicationProcess describe parameters referenced by objects uniqueIDRef
+type xddApplicationProcess struct {
+	DataTypes struct {
+		Enums   []xddEnumType `xml:"enumType"`
+		Structs []xddStruct   `xml:"struct"`
+	} `xml:"dataTypeList"`
+	Parameters []xddParameter `xml:"parameterList>parameter"`
+}
+
+type xddLabel struct {
+	Lang  string `xml:"lang,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xddValue struct {
+	Value  string     `xml:"value,attr"`
+	Labels []xddLabel `xml:"label"`
+}
+
+type xddEnumType struct {
+	UniqueID string     `xml:"uniqueID,attr"`
+	Values   []xddValue `xml:"enumValue"`
+}
+
+type xddStruct struct {
+	UniqueID string `xml:"uniqueID,attr"`
+	Vars     []struct {
+		Name  string `xml:"name,attr"`
+		Start string `xml:"start,attr"`
+		Size  string `xml:"size,attr"`
+	} `xml:"varDeclaration"`
+}
+
+type xddParameter struct {
+	UniqueID      string `xml:"uniqueID,attr"`
+	DataTypeIDRef struct {
+		UniqueIDRef string `xml:"uniqueIDRef,attr"`
+	} `xml:"dataTypeIDRef"`
+	Unit *struct {
+		Multiplier string     `xml:"multiplier,attr"`
+		Labels     []xddLabel `xml:"label"`
+	} `xml:"unit"`
+	AllowedValues []xddValue `xml:"allowedValues>value"`
+}
+
+type xddApplicationLayers struct {
+	Identity   *xddIdentity `xml:"identity"`
+	ObjectList struct {
+		Objects []xddObject `xml:"CANopenObject"`
+	} `xml:"CANopenObjectList"`
+	DummyUsage struct {
+		Dummies []struct {
+			Entry string `xml:"entry,attr"`
+		} `xml:"dummy"`
+	} `xml:"dummyUsage"`
+}
+
+type xddIdentity struct {
+	VendorID       string `xml:"vendorID"`
+	ProductCode    string `xml:"productCode"`
+	RevisionNumber string `xml:"revisionNumber"`
+}
+
+// xddObject is a CANopenObject or a CANopenSubObject
+type xddObject struct {
+	Index        string `xml:"index,attr"`
+	SubIndex     string `xml:"subIndex,attr"`
+	Name         string `xml:"name,attr"`
+	ObjectType   string `xml:"objectType,attr"`
+	DataType     string `xml:"dataType,attr"`
+	AccessType   string `xml:"accessType,attr"`
+	DefaultValue string `xml:"defaultValue,attr"`
+	ActualValue  string `xml:"actualValue,attr"`
+	LowLimit     string `xml:"lowLimit,attr"`
+	HighLimit    string `xml:"highLimit,attr"`
+	PDOMapping   string `xml:"PDOmapping,attr"`
+	UniqueIDRef  string `xml:"uniqueIDRef,attr"`
+
+	SubObjects []xddObject `xml:"CANopenSubObject"`
+}
+
+type xddTransportLayers struct {
+	BaudRate struct {
+		Supported []struct {
+			Value string `xml:"value,attr"`
+		} `xml:"supportedBaudRate"`
+	} `xml:"PhysicalLayer>baudRate"`
+}
+
+type xddNetworkManagement struct {
+	GeneralFeatures struct {
+		Granularity    string `xml:"granularity,attr"`
+		NrOfRxPDO      string `xml:"nrOfRxPDO,attr"`
+		NrOfTxPDO      string `xml:"nrOfTxPDO,attr"`
+		GroupMessaging string `xml:"groupMessaging,attr"`
+		BootUpSlave    string `xml:"bootUpSlave,attr"`
+		LSSSlave       string `xml:"layerSettingServiceSlave,attr"`
+	} `xml:"CANopenGeneralFeatures"`
+	MasterFeatures *struct {
+		BootUpMaster string `xml:"bootUpMaster,attr"`
+	} `xml:"CANopenMasterFeatures"`
+	DeviceCommissioning *struct {
+		NodeID         string `xml:"nodeID,attr"`
+		ActualBaudRate string `xml:"actualBaudRate,attr"`
+	} `xml:"deviceCommissioning"`
+}
+
+// DicXDDParse parse a CiA 311 XDD or XDC file. If in is string, it must be a path to a file,
+// else in must be xml data as []byte or an io.Reader
+func DicXDDParse(in interface{}) (*DicObjectDic, error) {
+	data, err := dicReadSource(in)
+	if err != nil {
+		return nil, err
+	}
+
+	container := &xddContainer{}
+	if err := xml.Unmarshal(data, container); err != nil {
+		return nil, err
+	}
+
+	ddic := NewDicObjectDic()
+
+	// Application process and objects can be in different profiles
+	process := &xddApplicationProcess{}
+	for _, profile := range container.Profiles {
+		if p := profile.Body.ApplicationProcess; p != nil {
+			process.DataTypes.Enums = append(process.DataTypes.Enums, p.DataTypes.Enums...)
+			process.DataTypes.Structs = append(process.DataTypes.Structs, p.DataTypes.Structs...)
+			process.Parameters = append(process.Parameters, p.Parameters...)
+		}
+	}
+
+	for _, profile := range container.Profiles {
+		body := profile.Body
+
+		if body.DeviceIdentity != nil {
+			buildXDDDeviceIdentity(ddic, body.DeviceIdentity)
+		}
+
+		if body.TransportLayers != nil {
+			for _, baudRate := range body.TransportLayers.BaudRate.Supported {
+				if b, ok := parseXDDBaudRate(baudRate.Value); ok {
+					ddic.DeviceInfo.BaudRates = append(ddic.DeviceInfo.BaudRates, b)
+				}
+			}
+		}
+
+		if body.NetworkManagement != nil {
+			buildXDDNetworkManagement(ddic, body.NetworkManagement)
+		}
+
+		if body.ApplicationLayers == nil {
+			continue
+		}
+
+		if identity := body.ApplicationLayers.Identity; identity != nil {
+			if v, ok := parseXDDUint(identity.VendorID); ok {
+				ddic.DeviceInfo.VendorNumber = uint32(v)
+			}
+
+			if v, ok := parseXDDUint(identity.ProductCode); ok {
+				ddic.DeviceInfo.ProductNumber = uint32(v)
+			}
+
+			if v, ok := parseXDDUint(identity.RevisionNumber); ok {
+				ddic.DeviceInfo.RevisionNumber = uint32(v)
+			}
+		}
+
+		// Dummy entries are like "Dummy0002=1"
+		for _, dummy := range body.ApplicationLayers.DummyUsage.Dummies {
+			var index uint16
+			var used int
+
+			if _, err := fmt.Sscanf(dummy.Entry, "Dummy%04X=%d", &index, &used); err == nil && used != 0 {
+				addDummyObject(ddic, index)
+			}
+		}
+
+		for _, xObject := range body.ApplicationLayers.ObjectList.Objects {
+			object, err := buildXDDObject(xObject, process, ddic.NodeID)
+			if err != nil {
+				return nil, err
+			}
+
+			ddic.AddObject(object)
+		}
+	}
+
+	return ddic, nil
+}
+
+func buildXDDDeviceIdentity(ddic *DicObjectDic, identity *xddDeviceIdentity) {
+	ddic.DeviceInfo.VendorName = strings.TrimSpace(identity.VendorName)
+	ddic.DeviceInfo.ProductName = strings.TrimSpace(identity.ProductName)
+	ddic.DeviceInfo.OrderCode = strings.TrimSpace(identity.OrderNumber)
+
+	if v, ok := parseXDDUint(identity.VendorID); ok {
+		ddic.DeviceInfo.VendorNumber = uint32(v)
+	}
+
+	if v, ok := parseXDDUint(identity.ProductID); ok {
+		ddic.DeviceInfo.ProductNumber = uint32(v)
+	}
+}
+
+func buildXDDNetworkManagement(ddic *DicObjectDic, nmt *xddNetworkManagement) {
+	features := nmt.GeneralFeatures
+
+	if v, ok := parseXDDUint(features.Granularity); ok {
+		ddic.DeviceInfo.Granularity = int(v)
+	}
+
+	if v, ok := parseXDDUint(features.NrOfRxPDO); ok {
+		ddic.DeviceInfo.NrOfRXPDO = int(v)
+	}
+
+	if v, ok := parseXDDUint(features.NrOfTxPDO); ok {
+		ddic.DeviceInfo.NrOfTXPDO = int(v)
+	}
+
+	ddic.DeviceInfo.GroupMessaging = features.GroupMessaging == "true"
+	ddic.DeviceInfo.SimpleBootUpSlave = features.BootUpSlave == "true"
+	ddic.DeviceInfo.LSSSupported = features.LSSSlave == "true"
+
+	if nmt.MasterFeatures != nil {
+		ddic.DeviceInfo.SimpleBootUpMaster = nmt.MasterFeatures.BootUpMaster == "true"
+	}
+
+	if commissioning := nmt.DeviceCommissioning; commissioning != nil {
+		if v, ok := parseXDDUint(commissioning.NodeID); ok {
+			ddic.NodeID = int(v)
+		}
+
+		if b, ok := parseXDDBaudRate(commissioning.ActualBaudRate); ok {
+			ddic.Baudrate = b
+		}
+	}
+}
+
+func buildXDDObject(xObject xddObject, process *xddApplicationProcess, nodeID int) (DicObject, error) {
+	idx, err := strconv.ParseUint(xObject.Index, 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object index %q: %v", xObject.Index, err)
+	}
+
+	index := uint16(idx)
+
+	objectType, ok := parseXDDUint(xObject.ObjectType)
+	if !ok {
+		objectType = uint64(DicVar)
+	}
+
+	switch byte(objectType) {
+	case DicArr, DicRec:
+		var object DicObject = &DicRecord{Index: index, Name: xObject.Name}
+		if byte(objectType) == DicArr {
+			object = &DicArray{Index: index, Name: xObject.Name}
+		}
+
+		for _, xSubObject := range xObject.SubObjects {
+			sidx, err := strconv.ParseUint(xSubObject.SubIndex, 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid sub index %q for object 0x%04X: %v", xSubObject.SubIndex, index, err)
+			}
+
+			variable, err := buildXDDVariable(index, uint8(sidx), xSubObject, process, nodeID)
+			if err != nil {
+				return nil, err
+			}
+
+			object.AddMember(variable)
+		}
+
+		return object, nil
+	default:
+		return buildXDDVariable(index, 0, xObject, process, nodeID)
+	}
+}
+
+func buildXDDVariable(
+	index uint16,
+	subIndex uint8,
+	xObject xddObject,
+	process *xddApplicationProcess,
+	nodeID int,
+) (*DicVariable, error) {
+	variable := &DicVariable{
+		Index:      index,
+		SubIndex:   subIndex,
+		Name:       xObject.Name,
+		AccessType: strings.ToLower(xObject.AccessType),
+		PDOMapping: xObject.PDOMapping != "" && xObject.PDOMapping != "no",
+		DataType:   Domain,
+	}
+
+	if xObject.DataType != "" {
+		dataType, err := strconv.ParseUint(xObject.DataType, 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data type %q for object 0x%04Xsub%d: %v", xObject.DataType, index, subIndex, err)
+		}
+
+		variable.DataType = byte(dataType)
+	}
+
+	// Invalid limits and default values are kept in their original notation
+	// only, instead of rejecting the whole file
+	variable.setLimit(xObject.LowLimit, nodeID, false)
+	variable.setLimit(xObject.HighLimit, nodeID, true)
+	variable.setDefaultValue(xObject.DefaultValue, nodeID)
+
+	// XDC actual value
+	if xObject.ActualValue != "" {
+		data, err := encodeDicValue(variable.DataType, xObject.ActualValue, nodeID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid actual value for object 0x%04Xsub%d: %v", index, subIndex, err)
+		}
+
+		variable.Data = data
+	}
+
+	if xObject.UniqueIDRef != "" {
+		if err := applyXDDParameter(variable, process, xObject.UniqueIDRef); err != nil {
+			return nil, fmt.Errorf("invalid parameter for object 0x%04Xsub%d: %v", index, subIndex, err)
+		}
+	}
+
+	return variable, nil
+}
+
+// applyXDDParameter set variable unit, value descriptions and bit definitions
+// from the application process parameter uniqueID
+func applyXDDParameter(variable *DicVariable, process *xddApplicationProcess, uniqueID string) error {
+	var parameter *xddParameter
+	for i := range process.Parameters {
+		if process.Parameters[i].UniqueID == uniqueID {
+			parameter = &process.Parameters[i]
+			break
+		}
+	}
+
+	if parameter == nil {
+		return nil
+	}
+
+	if unit := parameter.Unit; unit != nil {
+		variable.Unit = xddLabelText(unit.Labels)
+
+		if unit.Multiplier != "" {
+			f, err := strconv.ParseFloat(strings.TrimSpace(unit.Multiplier), 64)
+			if err != nil {
+				return fmt.Errorf("invalid unit multiplier %q: %v", unit.Multiplier, err)
+			}
+			variable.Factor = f
+		}
+	}
+
+	values := parameter.AllowedValues
+	typeRef := parameter.DataTypeIDRef.UniqueIDRef
+
+	for _, enum := range process.DataTypes.Enums {
+		if typeRef != "" && enum.UniqueID == typeRef {
+			values = append(values, enum.Values...)
+		}
+	}
+
+	for _, value := range values {
+		label := xddLabelText(value.Labels)
+		if label == "" {
+			continue
+		}
+
+		v, err := evalDicInt(value.Value, 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value %q: %v", value.Value, err)
+		}
+
+		variable.AddValueDescription(v, label)
+	}
+
+	for _, xStruct := range process.DataTypes.Structs {
+		if typeRef == "" || xStruct.UniqueID != typeRef {
+			continue
+		}
+
+		// Without start attribute, fields follow each other
+		start := 0
+		for _, field := range xStruct.Vars {
+			if v, ok := parseXDDUint(field.Start); ok {
+				start = int(v)
+			}
+
+			size := 1
+			if v, ok := parseXDDUint(field.Size); ok {
+				size = int(v)
+			}
+
+			bits := make([]byte, size)
+			for i := range bits {
+				bits[i] = byte(start + i)
+			}
+
+			variable.AddBitDefinition(field.Name, bits)
+			start += size
+		}
+	}
+
+	return nil
+}
+
+// xddLabelText return the english label, or the first one
+func xddLabelText(labels []xddLabel) string {
+	for _, label := range labels {
+		if strings.HasPrefix(strings.ToLower(label.Lang), "en") {
+			return strings.TrimSpace(label.Value)
+		}
+	}
+
+	if len(labels) > 0 {
+		return strings.TrimSpace(labels[0].Value)
+	}
+
+	return ""
+}
+
+// parseXDDUint parse a decimal or 0x prefixed hexadecimal number
+func parseXDDUint(s string) (uint64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+
+	v, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return v, true
+}
+
+// parseXDDBaudRate parse a baud rate like "250 Kbps" or "1 Mbps" in kbit/s
+func parseXDDBaudRate(s string) (int, bool) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0, false
+	}
+
+	v, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, false
+	}
+
+	switch strings.ToLower(fields[1]) {
+	case "kbps":
+		return v, true
+	case "mbps":
+		return v * 1000, true
+	}
+
+	return 0, false
+}
+
+// dicReadSource read all data from a file path, []byte or io.Reader
+func dicReadSource(in interface{}) ([]byte, error) {
+	switch src := in.(type) {
+	case string:
+		return os.ReadFile(src)
+	case []byte:
+		return src, nil
+	case io.Reader:
+		return io.ReadAll(src)
+	}
+
+	return nil, errors.New("invalid source, must be a file path, []byte or io.Reader")
+}
@@ -0,0 +1,180 @@
+package canopen
+
+import (
+	"testing"
+)
+
+const TestXDDFile string = `<?xml version="1.0" encoding="utf-8"?>
+<ISO15745ProfileContainer xmlns="http://www.canopen.org/xml/1.1" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <ISO15745Profile>
+    <ProfileHeader>
+      <ProfileIdentification>CANopen device profile</ProfileIdentification>
+      <ProfileRevision>1.1</ProfileRevision>
+      <ProfileName>Test device</ProfileName>
+      <ProfileSource />
+      <ProfileClassID>Device</ProfileClassID>
+      <ISO15745Reference>
+        <ISO15745Part>1</ISO15745Part>
+        <ISO15745Edition>1</ISO15745Edition>
+        <ProfileTechnology>CANopen</ProfileTechnology>
+      </ISO15745Reference>
+    </ProfileHeader>
+    <ProfileBody xsi:type="ProfileBody_Device_CANopen" fileName="test.xdc" fileCreator="go-canopen" fileCreationDate="2020-01-01" fileVersion="1">
+      <DeviceIdentity>
+        <vendorName>go-canopen</vendorName>
+        <vendorID>0x00001234</vendorID>
+        <productName>Test device</productName>
+        <productID>0x00000042</productID>
+      </DeviceIdentity>
+      <ApplicationProcess>
+        <dataTypeList>
+          <enumType uniqueID="T_State" name="State">
+            <enumValue value="0"><label lang="en">Ok</label></enumValue>
+            <enumValue value="1"><label lang="de">Fehler</label><label lang="en">Fault</label></enumValue>
+          </enumType>
+          <struct uniqueID="T_Status" name="Status">
+            <varDeclaration uniqueID="T_Status_Ready" name="Ready" start="0" size="1" />
+            <varDeclaration uniqueID="T_Status_Mode" name="Mode" start="4" size="2" />
+          </struct>
+        </dataTypeList>
+        <parameterList>
+          <parameter uniqueID="UID_6401_1" access="read">
+            <label lang="en">Temperature</label>
+            <INT />
+            <unit multiplier="0.1"><label lang="en">°C</label></unit>
+          </parameter>
+          <parameter uniqueID="UID_2000" access="read">
+            <label lang="en">State</label>
+            <dataTypeIDRef uniqueIDRef="T_State" />
+            <allowedValues><value value="2"><label lang="en">Warning</label></value></allowedValues>
+          </parameter>
+          <parameter uniqueID="UID_2001" access="read">
+            <label lang="en">Status</label>
+            <dataTypeIDRef uniqueIDRef="T_Status" />
+          </parameter>
+        </parameterList>
+      </ApplicationProcess>
+    </ProfileBody>
+  </ISO15745Profile>
+  <ISO15745Profile>
+    <ProfileHeader>
+      <ProfileIdentification>CANopen communication network profile</ProfileIdentification>
+      <ProfileRevision>1.1</ProfileRevision>
+      <ProfileName />
+      <ProfileSource />
+      <ProfileClassID>CommunicationNetwork</ProfileClassID>
+      <ISO15745Reference>
+        <ISO15745Part>1</ISO15745Part>
+        <ISO15745Edition>1</ISO15745Edition>
+        <ProfileTechnology>CANopen</ProfileTechnology>
+      </ISO15745Reference>
+    </ProfileHeader>
+    <ProfileBody xsi:type="ProfileBody_CommunicationNetwork_CANopen" fileName="test.xdc" fileCreator="go-canopen" fileCreationDate="2020-01-01" fileVersion="1">
+      <ApplicationLayers>
+        <identity>
+          <vendorID>0x00001234</vendorID>
+          <productCode>0x00000042</productCode>
+          <revisionNumber>0x00010002</revisionNumber>
+        </identity>
+        <CANopenObjectList>
+          <CANopenObject index="1000" name="Device type" objectType="7" dataType="0007" accessType="ro" defaultValue="0x00000191" PDOmapping="no" />
+          <CANopenObject index="1018" name="Identity object" objectType="9" subNumber="2">
+            <CANopenSubObject subIndex="00" name="Highest sub-index supported" objectType="7" dataType="0005" accessType="const" defaultValue="1" PDOmapping="no" />
+            <CANopenSubObject subIndex="01" name="Vendor-ID" objectType="7" dataType="0007" accessType="ro" defaultValue="0x00001234" PDOmapping="no" />
+          </CANopenObject>
+          <CANopenObject index="2000" name="State" objectType="7" dataType="0005" accessType="ro" actualValue="1" uniqueIDRef="UID_2000" />
+          <CANopenObject index="2001" name="Status" objectType="7" dataType="0006" accessType="ro" actualValue="0x0021" uniqueIDRef="UID_2001" />
+          <CANopenObject index="6401" name="Read analog input 16-bit" objectType="8" subNumber="2">
+            <CANopenSubObject subIndex="00" name="Number of channels" objectType="7" dataType="0005" accessType="const" defaultValue="1" PDOmapping="no" />
+            <CANopenSubObject subIndex="01" name="Channel 1" objectType="7" dataType="0003" accessType="ro" lowLimit="-1000" highLimit="1000" PDOmapping="TPDO" actualValue="-12" uniqueIDRef="UID_6401_1" />
+          </CANopenObject>
+        </CANopenObjectList>
+      </ApplicationLayers>
+      <TransportLayers>
+        <PhysicalLayer>
+          <baudRate defaultValue="250 Kbps">
+            <supportedBaudRate value="125 Kbps" />
+            <supportedBaudRate value="250 Kbps" />
+            <supportedBaudRate value="1 Mbps" />
+          </baudRate>
+        </PhysicalLayer>
+      </TransportLayers>
+      <NetworkManagement>
+        <CANopenGeneralFeatures granularity="8" nrOfRxPDO="4" nrOfTxPDO="2" bootUpSlave="true" layerSettingServiceSlave="false" />
+        <deviceCommissioning nodeID="12" nodeName="io" actualBaudRate="250 Kbps" />
+      </NetworkManagement>
+    </ProfileBody>
+  </ISO15745Profile>
+</ISO15745ProfileContainer>
+`
+
+func TestDicXDDParse(t *testing.T) {
+	dic, err := DicXDDParse([]byte(TestXDDFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deviceInfo := dic.DeviceInfo
+	if deviceInfo.VendorName != "go-canopen" || deviceInfo.VendorNumber != 0x1234 || deviceInfo.RevisionNumber != 0x00010002 {
+		t.Fatalf("invalid device info %+v", deviceInfo)
+	}
+
+	if !deviceInfo.SupportBaudRate(1000) || deviceInfo.NrOfRXPDO != 4 || deviceInfo.Granularity != 8 {
+		t.Fatalf("invalid device features %+v", deviceInfo)
+	}
+
+	if dic.NodeID != 12 || dic.Baudrate != 250 {
+		t.Fatalf("invalid commissioning %d / %d", dic.NodeID, dic.Baudrate)
+	}
+
+	if _, ok := dic.FindName("Identity object").(*DicRecord); !ok {
+		t.Fatal("1018 should be a record")
+	}
+
+	channel, ok := dic.FindIndex(0x6401).FindName("Channel 1").(*DicVariable)
+	if !ok {
+		t.Fatal("6401sub1 not found")
+	}
+
+	if channel.DataType != Integer16 || !channel.PDOMapping || channel.Min != -1000 || channel.Max != 1000 {
+		t.Fatalf("invalid variable %+v", channel)
+	}
+
+	if v := channel.GetIntVal(); v == nil || *v != -12 {
+		t.Fatalf("invalid actual value %v", channel.Data)
+	}
+
+	if scaled, err := channel.ScaledValue(); err != nil || scaled.String() != "-1.2 °C" {
+		t.Fatalf("invalid scaled value %v: %v", scaled, err)
+	}
+
+	state := dic.FindIndex(0x2000).(*DicVariable)
+	if des, err := state.DescribedValue(); err != nil || des != "Fault" || state.ValueDescriptions[2] != "Warning" {
+		t.Fatalf("invalid described value %q: %v", des, err)
+	}
+
+	status := dic.FindIndex(0x2001).(*DicVariable)
+	if mode, err := status.BitField("Mode"); err != nil || mode != 2 {
+		t.Fatalf("invalid bit field value %d: %v", mode, err)
+	}
+}
+
+func TestDicParse(t *testing.T) {
+	dic, err := DicParse([]byte(TestXDDFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dic.FindIndex(0x1000) == nil {
+		t.Fatal("XDD data not detected")
+	}
+
+	dic, err = DicParse([]byte("[1000]\nParameterName=Device type\nObjectType=0x7\nDataType=0x0007\nAccessType=ro\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dic.FindIndex(0x1000) == nil {
+		t.Fatal("EDS data not detected")
+	}
+}
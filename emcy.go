@@ -0,0 +1,43 @@
+package canopen
+
+// emcyClasses describe emergency error codes by their high byte, see CiA 301
+var emcyClasses = map[uint8]string{
+	0x00: "error reset or no error",
+	0x10: "generic error",
+	0x20: "current",
+	0x21: "current, device input side",
+	0x22: "current inside the device",
+	0x23: "current, device output side",
+	0x30: "voltage",
+	0x31: "mains voltage",
+	0x32: "voltage inside the device",
+	0x33: "output voltage",
+	0x40: "temperature",
+	0x41: "ambient temperature",
+	0x42: "device temperature",
+	0x50: "device hardware",
+	0x60: "device software",
+	0x61: "internal software",
+	0x62: "user software",
+	0x63: "data set",
+	0x70: "additional modules",
+	0x80: "monitoring",
+	0x81: "communication",
+	0x82: "protocol error",
+	0x90: "external error",
+	0xF0: "additional functions",
+	0xFF: "device specific",
+}
+
+// EMCYDescription return the class description of an emergency error code
+func EMCYDescription(code uint16) string {
+	if des, ok := emcyClasses[uint8(code>>8)]; ok {
+		return des
+	}
+
+	if des, ok := emcyClasses[uint8(code>>8)&0xF0]; ok {
+		return des
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,206 @@
+package canopen
+
+import (
+	"encoding/binary"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/angelodlfrtr/go-can"
+)
+
+// fakeTransport is an in memory can.Transport. Each written frame is passed to
+// the registered handlers, which can answer by calling Inject.
+type fakeTransport struct {
+	sync.Mutex
+
+	readChan chan *can.Frame
+	handlers []func(*can.Frame)
+	written  []*can.Frame
+}
+
+func newFakeTransport() *fakeTransport {
+	return &fakeTransport{readChan: make(chan *can.Frame, 64)}
+}
+
+func (t *fakeTransport) Open() error  { return nil }
+func (t *fakeTransport) Close() error { return nil }
+
+func (t *fakeTransport) Write(frm *can.Frame) error {
+	t.Lock()
+	t.written = append(t.written, frm)
+	handlers := t.handlers
+	t.Unlock()
+
+	for _, h := range handlers {
+		h(frm)
+	}
+
+	return nil
+}
+
+func (t *fakeTransport) ReadChan() chan *can.Frame {
+	return t.readChan
+}
+
+// Inject a frame as if it was received from the bus
+func (t *fakeTransport) Inject(arbID uint32, data []byte) {
+	frm := &can.Frame{ArbitrationID: arbID, DLC: uint8(len(data))}
+	copy(frm.Data[:], data)
+
+	// Give the requester some time to wait on its frames chan, as network
+	// publish is not blocking
+	go func() {
+		time.Sleep(2 * time.Millisecond)
+		t.readChan <- frm
+	}()
+}
+
+// Written return a copy of frames written to the transport
+func (t *fakeTransport) Written() []*can.Frame {
+	t.Lock()
+	defer t.Unlock()
+
+	return append([]*can.Frame{}, t.written...)
+}
+
+// fakeSDOServer answer SDO requests for a node from an in memory dictionary
+type fakeSDOServer struct {
+	sync.Mutex
+
+	transport *fakeTransport
+	nodeID    int
+	objects   map[uint32][]byte
+
+	// segmented upload state
+	upload []byte
+	toggle uint8
+}
+
+func sdoKey(index uint16, subIndex uint8) uint32 {
+	return uint32(index)<<8 | uint32(subIndex)
+}
+
+func newFakeSDOServer(transport *fakeTransport, nodeID int) *fakeSDOServer {
+	server := &fakeSDOServer{
+		transport: transport,
+		nodeID:    nodeID,
+		objects:   map[uint32][]byte{},
+	}
+
+	transport.Lock()
+	transport.handlers = append(transport.handlers, server.handle)
+	transport.Unlock()
+
+	return server
+}
+
+// Set object value
+func (server *fakeSDOServer) Set(index uint16, subIndex uint8, data []byte) {
+	server.Lock()
+	defer server.Unlock()
+
+	server.objects[sdoKey(index, subIndex)] = data
+}
+
+// Get object value
+func (server *fakeSDOServer) Get(index uint16, subIndex uint8) []byte {
+	server.Lock()
+	defer server.Unlock()
+
+	return server.objects[sdoKey(index, subIndex)]
+}
+
+func (server *fakeSDOServer) abort(index uint16, subIndex uint8, code uint32) {
+	res := make([]byte, 8)
+	res[0] = 0x80
+	binary.LittleEndian.PutUint16(res[1:], index)
+	res[3] = subIndex
+	binary.LittleEndian.PutUint32(res[4:], code)
+	server.transport.Inject(uint32(0x580+server.nodeID), res)
+}
+
+func (server *fakeSDOServer) handle(frm *can.Frame) {
+	if frm.ArbitrationID != uint32(0x600+server.nodeID) {
+		return
+	}
+
+	server.Lock()
+	defer server.Unlock()
+
+	command := frm.Data[0]
+	index := binary.LittleEndian.Uint16(frm.Data[1:])
+	subIndex := frm.Data[3]
+	res := make([]byte, 8)
+
+	switch command & 0xE0 {
+	case SDORequestUpload:
+		data, ok := server.objects[sdoKey(index, subIndex)]
+		if !ok {
+			server.abort(index, subIndex, 0x06020000)
+			return
+		}
+
+		copy(res[1:4], frm.Data[1:4])
+
+		if len(data) <= 4 {
+			res[0] = SDOResponseUpload | SDOExpedited | SDOSizeSpecified | uint8(4-len(data))<<2
+			copy(res[4:], data)
+		} else {
+			res[0] = SDOResponseUpload | SDOSizeSpecified
+			binary.LittleEndian.PutUint32(res[4:], uint32(len(data)))
+			server.upload = data
+			server.toggle = 0
+		}
+	case SDORequestSegmentUpload:
+		n := len(server.upload)
+		if n > 7 {
+			n = 7
+		}
+
+		res[0] = SDOResponseSegmentUpload | server.toggle | uint8(7-n)<<1
+		copy(res[1:], server.upload[:n])
+		server.upload = server.upload[n:]
+		server.toggle ^= SDOToggleBit
+
+		if len(server.upload) == 0 {
+			res[0] |= SDONoMoreData
+		}
+	case SDORequestDownload:
+		if (command & SDOExpedited) == 0 {
+			server.abort(index, subIndex, 0x05040001)
+			return
+		}
+
+		size := 4 - int((command>>2)&0x3)
+		server.objects[sdoKey(index, subIndex)] = append([]byte{}, frm.Data[4:4+size]...)
+
+		res[0] = SDOResponseDownload
+		copy(res[1:4], frm.Data[1:4])
+	default:
+		return
+	}
+
+	server.transport.Inject(uint32(0x580+server.nodeID), res)
+}
+
+// newTestNetwork create a running network over a fake transport
+func newTestNetwork(t *testing.T) (*Network, *fakeTransport) {
+	t.Helper()
+
+	transport := newFakeTransport()
+	network, err := NewNetwork(can.Bus{Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := network.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		network.Stop()
+	})
+
+	return network, transport
+}
@@ -0,0 +1,617 @@
+package canopen
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Gateway error codes of CiA 309-3, SDO aborts are reported with their abort code
+const (
+	GatewayErrorNotSupported = 100
+	GatewayErrorSyntax       = 101
+	GatewayErrorState        = 102
+	GatewayErrorTimeout      = 103
+	GatewayErrorNoNet        = 104
+	GatewayErrorNoNode       = 105
+	GatewayErrorNet          = 106
+	GatewayErrorNode         = 107
+)
+
+// gatewayNMTCommands map NMT commands to their command specifier
+var gatewayNMTCommands = map[string]int{
+	"start":          0x01,
+	"stop":           0x02,
+	"preop":          0x80,
+	"preoperational": 0x80,
+}
+
+// gatewayResetCommands map reset commands arguments to their command specifier
+var gatewayResetCommands = map[string]int{
+	"node":          0x81,
+	"comm":          0x82,
+	"communication": 0x82,
+}
+
+// GatewayInfo is returned by the info version command
+type GatewayInfo struct {
+	VendorID            uint32
+	ProductCode         uint32
+	RevisionNumber      uint32
+	SerialNumber        uint32
+	GatewayClass        int
+	ProtocolVersion     string
+	ImplementationClass string
+}
+
+// GatewaySession contain the settings of a client connection
+type GatewaySession struct {
+	// Net and Node are used when omitted from requests, 0 when not set
+	Net  int
+	Node int
+
+	SDOTimeout time.Duration
+}
+
+// Gateway is a CiA 309-3 ASCII gateway to networks. Requests are lines of the form
+//
+//	[<sequence>] [[<net>] <node>] <command> [arguments]
+//
+// eg: "[1] 1 5 r 0x1017 0 u16" read the heartbeat time of node 5 of net 1, and
+// the answer is "[1] 1000". Supported commands are r[ead], w[rite], start, stop,
+// preop[erational], reset node, reset comm[unication], init, set network,
+// set node, set sdo_timeout and info version
+type Gateway struct {
+	sync.Mutex
+
+	// Networks map net numbers to networks
+	Networks map[int]*Network
+
+	// DefaultNet of new sessions
+	DefaultNet int
+
+	// SDOTimeout of new sessions, default to 500ms
+	SDOTimeout time.Duration
+
+	Info GatewayInfo
+
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	wg       sync.WaitGroup
+}
+
+// NewGateway return a gateway to network as net 1
+func NewGateway(network *Network) *Gateway {
+	return &Gateway{
+		Networks:   map[int]*Network{1: network},
+		DefaultNet: 1,
+		SDOTimeout: 500 * time.Millisecond,
+		Info: GatewayInfo{
+			GatewayClass:        1,
+			ProtocolVersion:     "2.0",
+			ImplementationClass: "1.0",
+		},
+		conns: map[net.Conn]struct{}{},
+	}
+}
+
+// NewSession return a session with gateway defaults
+func (gateway *Gateway) NewSession() *GatewaySession {
+	return &GatewaySession{Net: gateway.DefaultNet, SDOTimeout: gateway.SDOTimeout}
+}
+
+// ListenAndServe listen on TCP address addr, and serve requests until Close
+func (gateway *Gateway) ListenAndServe(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return gateway.Serve(listener)
+}
+
+// Serve requests of connections accepted by listener until Close
+func (gateway *Gateway) Serve(listener net.Listener) error {
+	gateway.Lock()
+	if gateway.listener != nil {
+		gateway.Unlock()
+		return errors.New("gateway already serving")
+	}
+	gateway.listener = listener
+	gateway.Unlock()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			gateway.Lock()
+			closed := gateway.listener == nil
+			gateway.Unlock()
+
+			if closed {
+				return nil
+			}
+
+			return err
+		}
+
+		gateway.Lock()
+		gateway.conns[conn] = struct{}{}
+		gateway.wg.Add(1)
+		gateway.Unlock()
+
+		go gateway.serveConn(conn)
+	}
+}
+
+// serveConn answer requests of conn, one per line
+func (gateway *Gateway) serveConn(conn net.Conn) {
+	defer gateway.wg.Done()
+
+	defer func() {
+		conn.Close()
+
+		gateway.Lock()
+		delete(gateway.conns, conn)
+		gateway.Unlock()
+	}()
+
+	session := gateway.NewSession()
+	scanner := bufio.NewScanner(conn)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(conn, "%s\r\n", gateway.Handle(session, line)); err != nil {
+			return
+		}
+	}
+}
+
+// Close stop serving, and close client connections
+func (gateway *Gateway) Close() error {
+	gateway.Lock()
+	listener := gateway.listener
+	gateway.listener = nil
+
+	for conn := range gateway.conns {
+		conn.Close()
+	}
+	gateway.Unlock()
+
+	var err error
+	if listener != nil {
+		err = listener.Close()
+	}
+
+	gateway.wg.Wait()
+
+	return err
+}
+
+// gatewayError is a request error reported with its code
+type gatewayError struct {
+	code uint32
+}
+
+func (e *gatewayError) Error() string {
+	if e.code >= 0x01000000 {
+		return fmt.Sprintf("ERROR: 0x%08X", e.code)
+	}
+
+	return fmt.Sprintf("ERROR: %d", e.code)
+}
+
+// gatewayErrorf return the gateway error of code
+func gatewayErrorf(code int) error {
+	return &gatewayError{code: uint32(code)}
+}
+
+// gatewayRequest is a parsed request
+type gatewayRequest struct {
+	session *GatewaySession
+	net     int
+	node    int
+	hasNet  bool
+	hasNode bool
+	command string
+	args    []string
+}
+
+// Handle a request line of session, and return the response without line end
+func (gateway *Gateway) Handle(session *GatewaySession, line string) string {
+	tokens, err := gatewayTokens(line)
+	if err != nil || len(tokens) == 0 {
+		return gatewayErrorf(GatewayErrorSyntax).Error()
+	}
+
+	// Sequence number is mandatory
+	seq := tokens[0]
+	if len(seq) < 3 || seq[0] != '[' || seq[len(seq)-1] != ']' {
+		return gatewayErrorf(GatewayErrorSyntax).Error()
+	}
+
+	if _, err := strconv.ParseUint(seq[1:len(seq)-1], 10, 32); err != nil {
+		return gatewayErrorf(GatewayErrorSyntax).Error()
+	}
+
+	response, err := gateway.handleTokens(session, tokens[1:])
+	if err != nil {
+		var gwErr *gatewayError
+		if !errors.As(err, &gwErr) {
+			gwErr = gatewayErrorOf(err)
+		}
+
+		response = gwErr.Error()
+	}
+
+	return seq + " " + response
+}
+
+// gatewayErrorOf return the gateway error of an SDO or network error
+func gatewayErrorOf(err error) *gatewayError {
+	var abortErr *SDOAbortError
+
+	switch {
+	case errors.As(err, &abortErr):
+		return &gatewayError{code: abortErr.Code}
+	case errors.Is(err, ErrSDOTimeout):
+		return &gatewayError{code: GatewayErrorTimeout}
+	}
+
+	return &gatewayError{code: GatewayErrorState}
+}
+
+// handleTokens execute a request without its sequence number
+func (gateway *Gateway) handleTokens(session *GatewaySession, tokens []string) (string, error) {
+	req := &gatewayRequest{session: session}
+
+	// Leading numbers are the net and node
+	numbers := []int{}
+	for len(tokens) > 0 && len(numbers) < 2 {
+		n, err := strconv.ParseUint(tokens[0], 0, 8)
+		if err != nil {
+			break
+		}
+
+		numbers = append(numbers, int(n))
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) == 0 {
+		return "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	req.command = strings.ToLower(tokens[0])
+	req.args = tokens[1:]
+
+	switch {
+	case len(numbers) == 2:
+		req.net, req.node = numbers[0], numbers[1]
+		req.hasNet, req.hasNode = true, true
+	case len(numbers) == 1 && (req.command == "init" || req.command == "set"):
+		// Net level commands
+		req.net, req.hasNet = numbers[0], true
+	case len(numbers) == 1:
+		req.node, req.hasNode = numbers[0], true
+	}
+
+	switch req.command {
+	case "r", "read":
+		return gateway.read(req)
+	case "w", "write":
+		return gateway.write(req)
+	case "start", "stop", "preop", "preoperational":
+		return gateway.nmt(req, gatewayNMTCommands[req.command], 0)
+	case "reset":
+		if len(req.args) != 1 {
+			return "", gatewayErrorf(GatewayErrorSyntax)
+		}
+
+		code, ok := gatewayResetCommands[strings.ToLower(req.args[0])]
+		if !ok {
+			return "", gatewayErrorf(GatewayErrorSyntax)
+		}
+
+		return gateway.nmt(req, code, 1)
+	case "init":
+		return gateway.init(req)
+	case "set":
+		return gateway.set(req)
+	case "info":
+		return gateway.info(req)
+	}
+
+	return "", gatewayErrorf(GatewayErrorNotSupported)
+}
+
+// network return the network of req, using the session default net
+func (gateway *Gateway) network(req *gatewayRequest) (*Network, error) {
+	netID := req.net
+	if !req.hasNet {
+		netID = req.session.Net
+	}
+
+	if netID == 0 {
+		return nil, gatewayErrorf(GatewayErrorNoNet)
+	}
+
+	gateway.Lock()
+	network, ok := gateway.Networks[netID]
+	gateway.Unlock()
+
+	if !ok {
+		return nil, gatewayErrorf(GatewayErrorNet)
+	}
+
+	return network, nil
+}
+
+// nodeID return the node of req, using the session default node. With
+// broadcast, 0 is valid to address all nodes
+func (gateway *Gateway) nodeID(req *gatewayRequest, broadcast bool) (int, error) {
+	nodeID := req.node
+	if !req.hasNode {
+		if req.session.Node == 0 {
+			return 0, gatewayErrorf(GatewayErrorNoNode)
+		}
+
+		nodeID = req.session.Node
+	}
+
+	if nodeID > 127 || (nodeID == 0 && !broadcast) {
+		return 0, gatewayErrorf(GatewayErrorNode)
+	}
+
+	return nodeID, nil
+}
+
+// sdoClient return a client for nodeID, with the session timeout
+func (gateway *Gateway) sdoClient(req *gatewayRequest) (*SDOClient, error) {
+	network, err := gateway.network(req)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeID, err := gateway.nodeID(req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	sdoClient := NewSDOClient(NewNode(nodeID, network, nil))
+	sdoClient.Timeout = req.session.SDOTimeout
+	sdoClient.Retries = 1
+
+	return sdoClient, nil
+}
+
+// gatewayObject parse index, sub-index and data type arguments
+func gatewayObject(args []string) (uint16, uint8, string, error) {
+	if len(args) < 3 {
+		return 0, 0, "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	index, err := strconv.ParseUint(args[0], 0, 16)
+	if err != nil {
+		return 0, 0, "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	subIndex, err := strconv.ParseUint(args[1], 0, 8)
+	if err != nil {
+		return 0, 0, "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	dataType := strings.ToLower(args[2])
+	if _, ok := gatewayDataTypes[dataType]; !ok {
+		return 0, 0, "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	return uint16(index), uint8(subIndex), dataType, nil
+}
+
+// read: r[ead] <index> <sub-index> <datatype>
+func (gateway *Gateway) read(req *gatewayRequest) (string, error) {
+	index, subIndex, dataType, err := gatewayObject(req.args)
+	if err != nil {
+		return "", err
+	}
+
+	if len(req.args) != 3 {
+		return "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	sdoClient, err := gateway.sdoClient(req)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := sdoClient.Read(index, subIndex)
+	if err != nil {
+		return "", err
+	}
+
+	return gatewayFormatValue(dataType, data)
+}
+
+// write: w[rite] <index> <sub-index> <datatype> <value>
+func (gateway *Gateway) write(req *gatewayRequest) (string, error) {
+	index, subIndex, dataType, err := gatewayObject(req.args)
+	if err != nil {
+		return "", err
+	}
+
+	if len(req.args) != 4 {
+		return "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	data, err := gatewayEncodeValue(dataType, req.args[3])
+	if err != nil {
+		return "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	sdoClient, err := gateway.sdoClient(req)
+	if err != nil {
+		return "", err
+	}
+
+	if err := sdoClient.Write(index, subIndex, false, data); err != nil {
+		return "", err
+	}
+
+	return "OK", nil
+}
+
+// nmt send an NMT command, with nArgs arguments in request
+func (gateway *Gateway) nmt(req *gatewayRequest, code int, nArgs int) (string, error) {
+	if len(req.args) != nArgs {
+		return "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	network, err := gateway.network(req)
+	if err != nil {
+		return "", err
+	}
+
+	nodeID, err := gateway.nodeID(req, true)
+	if err != nil {
+		return "", err
+	}
+
+	if err := NewNMTMaster(nodeID, network).SendCommand(code); err != nil {
+		return "", err
+	}
+
+	return "OK", nil
+}
+
+// init: [net] init <bit rate index>. The bit rate is set by the network
+// transport, only the net is checked
+func (gateway *Gateway) init(req *gatewayRequest) (string, error) {
+	if len(req.args) != 1 {
+		return "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	if _, err := strconv.ParseUint(req.args[0], 0, 8); err != nil {
+		return "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	if _, err := gateway.network(req); err != nil {
+		return "", err
+	}
+
+	return "OK", nil
+}
+
+// set: set network <net>, set node <node>, set sdo_timeout <ms>
+func (gateway *Gateway) set(req *gatewayRequest) (string, error) {
+	if len(req.args) != 2 {
+		return "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	value, err := strconv.ParseUint(req.args[1], 0, 32)
+	if err != nil {
+		return "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	switch strings.ToLower(req.args[0]) {
+	case "network":
+		gateway.Lock()
+		_, ok := gateway.Networks[int(value)]
+		gateway.Unlock()
+
+		if !ok {
+			return "", gatewayErrorf(GatewayErrorNet)
+		}
+
+		req.session.Net = int(value)
+	case "node":
+		if value < 1 || value > 127 {
+			return "", gatewayErrorf(GatewayErrorNode)
+		}
+
+		req.session.Node = int(value)
+	case "sdo_timeout":
+		if value == 0 {
+			return "", gatewayErrorf(GatewayErrorSyntax)
+		}
+
+		req.session.SDOTimeout = time.Duration(value) * time.Millisecond
+	default:
+		return "", gatewayErrorf(GatewayErrorNotSupported)
+	}
+
+	return "OK", nil
+}
+
+// info: info version
+func (gateway *Gateway) info(req *gatewayRequest) (string, error) {
+	if len(req.args) != 1 {
+		return "", gatewayErrorf(GatewayErrorSyntax)
+	}
+
+	if strings.ToLower(req.args[0]) != "version" {
+		return "", gatewayErrorf(GatewayErrorNotSupported)
+	}
+
+	info := gateway.Info
+
+	return fmt.Sprintf(
+		"0x%08X 0x%08X 0x%08X 0x%08X %d %s %s",
+		info.VendorID, info.ProductCode, info.RevisionNumber, info.SerialNumber,
+		info.GatewayClass, info.ProtocolVersion, info.ImplementationClass,
+	), nil
+}
+
+// gatewayTokens split line on spaces. Double quoted tokens can contain spaces,
+// and "" for a double quote
+func gatewayTokens(line string) ([]string, error) {
+	tokens := []string{}
+
+	for i := 0; i < len(line); {
+		switch {
+		case line[i] == ' ' || line[i] == '\t':
+			i++
+		case line[i] == '"':
+			token := strings.Builder{}
+			i++
+
+			for {
+				if i >= len(line) {
+					return nil, errors.New("unterminated string")
+				}
+
+				if line[i] == '"' {
+					if i+1 < len(line) && line[i+1] == '"' {
+						token.WriteByte('"')
+						i += 2
+						continue
+					}
+
+					i++
+					break
+				}
+
+				token.WriteByte(line[i])
+				i++
+			}
+
+			tokens = append(tokens, token.String())
+		default:
+			start := i
+			for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+				i++
+			}
+
+			tokens = append(tokens, line[start:i])
+		}
+	}
+
+	return tokens, nil
+}
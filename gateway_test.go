@@ -0,0 +1,117 @@
+package canopen
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestGatewayHandle(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 5)
+	server.Set(0x1017, 0, []byte{0xE8, 0x03})
+	server.Set(0x1008, 0, []byte("Drive \"X\""))
+	server.Set(0x2000, 0, []byte{0xFE, 0xFF})
+
+	gateway := NewGateway(network)
+	session := gateway.NewSession()
+	session.SDOTimeout = 20 * time.Millisecond
+
+	for _, test := range []struct {
+		request  string
+		response string
+	}{
+		{"[1] 1 5 r 0x1017 0 u16", "[1] 1000"},
+		{"[2] 5 read 0x1008 0 vs", `[2] "Drive ""X"""`},
+		{"[3] 1 5 r 0x2000 0 i16", "[3] -2"},
+		{"[4] 1 5 r 0x2001 0 u8", "[4] ERROR: 0x06020000"},
+		{"[5] 1 5 w 0x1017 0 u16 2000", "[5] OK"},
+		{"[6] 1 5 r 0x1017 0 u16", "[6] 2000"},
+		{"[7] 1 5 w 0x2000 0 i16 -10", "[7] OK"},
+		{"[8] 1 5 w 0x2000 0 i16 x", "[8] ERROR: 101"},
+		{"[9] 1 6 r 0x1017 0 u16", "[9] ERROR: 103"},
+		{"[10] r 0x1017 0 u16", "[10] ERROR: 105"},
+		{"[11] set node 5", "[11] OK"},
+		{"[12] r 0x1017 0 u16", "[12] 2000"},
+		{"[13] 2 5 r 0x1017 0 u16", "[13] ERROR: 106"},
+		{"[14] 1 5 r 0x1017 0 x16", "[14] ERROR: 101"},
+		{"[15] 1 5 start", "[15] OK"},
+		{"[16] 1 0 reset comm", "[16] OK"},
+		{"[17] 1 5 enable guarding 100 3", "[17] ERROR: 100"},
+		{"[18] 1 init 4", "[18] OK"},
+		{"[19] info version", "[19] 0x00000000 0x00000000 0x00000000 0x00000000 1 2.0 1.0"},
+		{"1 5 r 0x1017 0 u16", "ERROR: 101"},
+		{`[20] 1 5 w 0x1008 0 vs "unterminated`, "ERROR: 101"},
+	} {
+		if response := gateway.Handle(session, test.request); response != test.response {
+			t.Errorf("%q: expected %q, got %q", test.request, test.response, response)
+		}
+	}
+
+	if data := server.Get(0x2000, 0); fmt.Sprintf("%X", data) != "F6FF" {
+		t.Errorf("unexpected written value %X", data)
+	}
+
+	written := transport.Written()
+	nmt := []string{}
+	for _, frm := range written {
+		if frm.ArbitrationID == 0 {
+			nmt = append(nmt, fmt.Sprintf("%X", frm.GetData()))
+		}
+	}
+
+	if fmt.Sprint(nmt) != "[0105 8200]" {
+		t.Errorf("unexpected NMT commands %v", nmt)
+	}
+}
+
+func TestGatewayServe(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	newFakeSDOServer(transport, 5).Set(0x1017, 0, []byte{0xE8, 0x03})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gateway := NewGateway(network)
+	served := make(chan error, 1)
+	go func() { served <- gateway.Serve(listener) }()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	for request, response := range map[string]string{
+		"[1] 1 5 r 0x1017 0 u16\r\n": "[1] 1000\r\n",
+		"[2] set sdo_timeout 0\n":    "[2] ERROR: 101\r\n",
+	} {
+		if _, err := conn.Write([]byte(request)); err != nil {
+			t.Fatal(err)
+		}
+
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if line != response {
+			t.Errorf("%q: expected %q, got %q", request, response, line)
+		}
+	}
+
+	if err := gateway.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-served; err != nil {
+		t.Errorf("unexpected serve error %v", err)
+	}
+}
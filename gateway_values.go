@@ -0,0 +1,111 @@
+package canopen
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// gatewayDataTypes map CiA 309-3 data types to CiA 301 data types
+var gatewayDataTypes = map[string]byte{
+	"b":   Boolean,
+	"i8":  Integer8,
+	"i16": Integer16,
+	"i24": Integer24,
+	"i32": Integer32,
+	"i40": Integer40,
+	"i48": Integer48,
+	"i56": Integer56,
+	"i64": Integer64,
+	"u8":  Unsigned8,
+	"u16": Unsigned16,
+	"u24": Unsigned24,
+	"u32": Unsigned32,
+	"u40": Unsigned40,
+	"u48": Unsigned48,
+	"u56": Unsigned56,
+	"u64": Unsigned64,
+	"r32": Real32,
+	"r64": Real64,
+	"t":   TimeOfDay,
+	"td":  TimeDifference,
+	"vs":  VisibleString,
+	"os":  OctetString,
+	"us":  UnicodeString,
+	"d":   Domain,
+}
+
+// gatewayQuote quote s, with double quotes doubled
+func gatewayQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// gatewayFormatValue format data of a 309-3 data type. Integers are decimal,
+// strings are quoted, octet strings are hexadecimal, domains are base64, and
+// times are raw hexadecimal values
+func gatewayFormatValue(name string, data []byte) (string, error) {
+	dataType := gatewayDataTypes[name]
+
+	switch {
+	case dataType == VisibleString:
+		return gatewayQuote(strings.TrimRight(string(data), "\x00")), nil
+	case dataType == UnicodeString:
+		return gatewayQuote(decodeDicUnicode(data)), nil
+	case dataType == OctetString:
+		return strings.ToUpper(hex.EncodeToString(data)), nil
+	case dataType == Domain:
+		return base64.StdEncoding.EncodeToString(data), nil
+	case dataType == TimeOfDay || dataType == TimeDifference:
+		if len(data) < DataTypeSize(dataType) {
+			return "", fmt.Errorf("invalid %s value length %d", name, len(data))
+		}
+
+		return DicFormatValue(dataType, data), nil
+	case IsFloatType(dataType):
+		f, err := decodeDicFloat(dataType, data)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatFloat(f, 'g', -1, DataTypeSize(dataType)*8), nil
+	case IsSignedType(dataType):
+		v, err := decodeDicInt(dataType, data)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatInt(v, 10), nil
+	}
+
+	v, err := decodeDicUint(dataType, data)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatUint(v, 10), nil
+}
+
+// gatewayEncodeValue encode a value of a 309-3 data type, see gatewayFormatValue
+func gatewayEncodeValue(name string, s string) ([]byte, error) {
+	dataType := gatewayDataTypes[name]
+
+	switch dataType {
+	case VisibleString:
+		return []byte(s), nil
+	case UnicodeString:
+		return encodeDicUnicode(s), nil
+	case OctetString:
+		return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	case Domain:
+		return base64.StdEncoding.DecodeString(s)
+	}
+
+	// No $NODEID expressions
+	if strings.Contains(s, "$") {
+		return nil, fmt.Errorf("invalid %s value %q", name, s)
+	}
+
+	return encodeDicValue(dataType, s, 0)
+}
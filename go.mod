@@ -1,16 +1,26 @@
 module github.com/angelodlfrtr/go-canopen
 
-go 1.17
+go 1.18
 
 require (
 	github.com/angelodlfrtr/go-can v0.0.4
 	github.com/google/uuid v1.3.0
+	github.com/prometheus/client_golang v1.14.0
+	golang.org/x/sys v0.1.0
 	gopkg.in/ini.v1 v1.66.4
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/angelodlfrtr/serial v0.0.0-20190912094943-d028474db63c // indirect
+	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/brutella/can v0.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.1.2 // indirect
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/matttproud/golang_protobuf_extensions v1.0.1 // indirect
+	github.com/prometheus/client_model v0.3.0 // indirect
+	github.com/prometheus/common v0.37.0 // indirect
+	github.com/prometheus/procfs v0.8.0 // indirect
 	github.com/stretchr/testify v1.7.0 // indirect
-	golang.org/x/sys v0.1.0 // indirect
+	google.golang.org/protobuf v1.28.1 // indirect
 )
@@ -0,0 +1,389 @@
+package canopen
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// httpNMTCommands map NMT commands of HTTPHandler to their command specifier
+var httpNMTCommands = map[string]int{
+	"start":      0x01,
+	"stop":       0x02,
+	"preop":      0x80,
+	"reset":      0x81,
+	"reset-comm": 0x82,
+}
+
+// HTTPHandler is an http.Handler exposing a network as JSON:
+//
+//	GET  /nodes                           network nodes, with their heartbeat state
+//	GET  /nodes/{id}/objects/{index}/{sub} read an object, eg: /nodes/5/objects/1017/0
+//	PUT  /nodes/{id}/objects/{index}/{sub} write an object, from {"value": 1000}
+//	POST /nodes/{id}/nmt                   send {"command": "start"}, node 0 for all
+//	GET  /events                           server-sent events of PDO changes and EMCY
+//
+// Objects are typed from the node object dictionary, or with the type query
+// parameter, eg: ?type=UNSIGNED16. Mount it with http.StripPrefix under a path
+type HTTPHandler struct {
+	Network *Network
+
+	// SDOTimeout of object requests, default to the SDO client timeout
+	SDOTimeout time.Duration
+}
+
+// NewHTTPHandler return an HTTPHandler of network
+func NewHTTPHandler(network *Network) *HTTPHandler {
+	return &HTTPHandler{Network: network}
+}
+
+// HTTPNode is a node of GET /nodes
+type HTTPNode struct {
+	ID            int        `json:"id"`
+	State         string     `json:"state,omitempty"`
+	LastHeartbeat *time.Time `json:"lastHeartbeat,omitempty"`
+}
+
+// HTTPObject is an object value of GET and PUT /nodes/{id}/objects/{index}/{sub}
+type HTTPObject struct {
+	Index    string `json:"index"`
+	SubIndex uint8  `json:"subIndex"`
+	Name     string `json:"name,omitempty"`
+	DataType string `json:"dataType,omitempty"`
+
+	// Value is a number, a boolean or a string, depending on DataType
+	Value interface{} `json:"value,omitempty"`
+
+	// Raw is the hexadecimal little endian value
+	Raw string `json:"raw"`
+}
+
+// httpError is an error response
+type httpError struct {
+	Error     string `json:"error"`
+	AbortCode string `json:"abortCode,omitempty"`
+}
+
+// ServeHTTP route requests
+func (handler *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "nodes":
+		handler.method(w, r, http.MethodGet, handler.nodes)
+	case len(parts) == 1 && parts[0] == "events":
+		handler.method(w, r, http.MethodGet, handler.events)
+	case len(parts) == 3 && parts[0] == "nodes" && parts[2] == "nmt":
+		handler.method(w, r, http.MethodPost, func(w http.ResponseWriter, r *http.Request) {
+			handler.nmt(w, r, parts[1])
+		})
+	case len(parts) == 5 && parts[0] == "nodes" && parts[2] == "objects":
+		handler.object(w, r, parts[1], parts[3], parts[4])
+	default:
+		httpWriteError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+// method call fn if r method is method
+func (handler *HTTPHandler) method(w http.ResponseWriter, r *http.Request, method string, fn http.HandlerFunc) {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		httpWriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	fn(w, r)
+}
+
+// httpWriteJSON write v as JSON with status
+func httpWriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// httpWriteError write err with status, or a status from err for SDO errors
+func httpWriteError(w http.ResponseWriter, status int, err error) {
+	res := httpError{Error: err.Error()}
+
+	var abortErr *SDOAbortError
+
+	switch {
+	case errors.As(err, &abortErr):
+		res.AbortCode = fmt.Sprintf("0x%08X", abortErr.Code)
+		status = httpAbortStatus(abortErr.Code)
+	case errors.Is(err, ErrSDOTimeout):
+		status = http.StatusGatewayTimeout
+	}
+
+	httpWriteJSON(w, status, res)
+}
+
+// httpAbortStatus return the HTTP status of an SDO abort code
+func httpAbortStatus(code uint32) int {
+	switch code {
+	case SDOAbortObjectNotExist, SDOAbortSubIndexNotExist:
+		return http.StatusNotFound
+	case SDOAbortUnsupportedAccess, SDOAbortWriteOnly, SDOAbortReadOnly:
+		return http.StatusForbidden
+	case SDOAbortTypeMismatch, SDOAbortTypeTooLong, SDOAbortTypeTooShort,
+		SDOAbortInvalidValue, SDOAbortValueTooHigh, SDOAbortValueTooLow:
+		return http.StatusBadRequest
+	}
+
+	return http.StatusBadGateway
+}
+
+// nodes: GET /nodes
+func (handler *HTTPHandler) nodes(w http.ResponseWriter, r *http.Request) {
+	handler.Network.Lock()
+	networkNodes := make([]*Node, 0, len(handler.Network.Nodes))
+	for _, node := range handler.Network.Nodes {
+		networkNodes = append(networkNodes, node)
+	}
+	handler.Network.Unlock()
+
+	sort.Slice(networkNodes, func(i, j int) bool { return networkNodes[i].ID < networkNodes[j].ID })
+
+	nodes := make([]HTTPNode, 0, len(networkNodes))
+	for _, node := range networkNodes {
+		httpNode := HTTPNode{ID: node.ID}
+
+		if node.NMTMaster != nil {
+			if state, at, ok := node.NMTMaster.LastHeartbeat(); ok {
+				httpNode.State = state
+				httpNode.LastHeartbeat = &at
+			}
+		}
+
+		nodes = append(nodes, httpNode)
+	}
+
+	httpWriteJSON(w, http.StatusOK, nodes)
+}
+
+// node return the network node nodeID, or a new node without object dictionary
+func (handler *HTTPHandler) node(nodeID int) *Node {
+	if node, err := handler.Network.GetNode(nodeID); err == nil {
+		return node
+	}
+
+	return NewNode(nodeID, handler.Network, nil)
+}
+
+// httpNodeID parse a node id, 0 is accepted with broadcast
+func httpNodeID(s string, broadcast bool) (int, error) {
+	nodeID, err := strconv.ParseUint(s, 0, 8)
+	if err != nil || nodeID > 127 || (nodeID == 0 && !broadcast) {
+		return 0, fmt.Errorf("invalid node id %q", s)
+	}
+
+	return int(nodeID), nil
+}
+
+// nmt: POST /nodes/{id}/nmt
+func (handler *HTTPHandler) nmt(w http.ResponseWriter, r *http.Request, id string) {
+	nodeID, err := httpNodeID(id, true)
+	if err != nil {
+		httpWriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	req := struct {
+		Command string `json:"command"`
+	}{}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httpWriteError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %v", err))
+		return
+	}
+
+	code, ok := httpNMTCommands[strings.ToLower(req.Command)]
+	if !ok {
+		httpWriteError(w, http.StatusBadRequest, fmt.Errorf("unknown NMT command %q", req.Command))
+		return
+	}
+
+	if err := NewNMTMaster(nodeID, handler.Network).SendCommand(code); err != nil {
+		httpWriteError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// object: GET and PUT /nodes/{id}/objects/{index}/{sub}
+func (handler *HTTPHandler) object(w http.ResponseWriter, r *http.Request, id, indexStr, subStr string) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPut {
+		w.Header().Set("Allow", "GET, PUT")
+		httpWriteError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	nodeID, err := httpNodeID(id, false)
+	if err != nil {
+		httpWriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	index, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(indexStr), "0x"), 16, 16)
+	if err != nil {
+		httpWriteError(w, http.StatusBadRequest, fmt.Errorf("invalid index %q", indexStr))
+		return
+	}
+
+	subIndex, err := strconv.ParseUint(subStr, 0, 8)
+	if err != nil {
+		httpWriteError(w, http.StatusBadRequest, fmt.Errorf("invalid sub-index %q", subStr))
+		return
+	}
+
+	node := handler.node(nodeID)
+	object := &HTTPObject{Index: fmt.Sprintf("0x%04X", index), SubIndex: uint8(subIndex)}
+
+	// Data type from the dictionary, or the query
+	var dataType byte
+	if node.ObjectDic != nil {
+		if variable := node.ObjectDic.FindVariable(uint16(index), uint8(subIndex)); variable != nil {
+			object.Name = variable.Name
+			dataType = variable.DataType
+		}
+	}
+
+	if s := r.URL.Query().Get("type"); s != "" {
+		if dataType, err = ParseDataType(s); err != nil {
+			httpWriteError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if dataType != 0 {
+		object.DataType = DataTypeName(dataType)
+	}
+
+	sdoClient := node.sdo()
+	if handler.SDOTimeout > 0 {
+		client := *sdoClient
+		client.Timeout = handler.SDOTimeout
+		sdoClient = &client
+	}
+
+	if r.Method == http.MethodPut {
+		data, err := httpDecodeObject(r, dataType)
+		if err != nil {
+			httpWriteError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := sdoClient.Write(uint16(index), uint8(subIndex), false, data); err != nil {
+			httpWriteError(w, http.StatusBadGateway, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	data, err := sdoClient.Read(uint16(index), uint8(subIndex))
+	if err != nil {
+		httpWriteError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	object.Raw = strings.ToUpper(hex.EncodeToString(data))
+	if dataType != 0 {
+		object.Value = dicJSONValue(dataType, data)
+	}
+
+	httpWriteJSON(w, http.StatusOK, object)
+}
+
+// httpDecodeObject decode a PUT body, {"value": ...} encoded with dataType, or {"raw": "E803"}
+func httpDecodeObject(r *http.Request, dataType byte) ([]byte, error) {
+	req := struct {
+		Value json.RawMessage `json:"value"`
+		Raw   *string         `json:"raw"`
+	}{}
+
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&req); err != nil {
+		return nil, fmt.Errorf("invalid body: %v", err)
+	}
+
+	if req.Raw != nil {
+		return hex.DecodeString(*req.Raw)
+	}
+
+	if req.Value == nil {
+		return nil, errors.New("missing value or raw")
+	}
+
+	if dataType == 0 {
+		return nil, errors.New("unknown data type, use raw or the type parameter")
+	}
+
+	return DicEncodeValue(dataType, dicJSONText(req.Value), 0)
+}
+
+// events: GET /events, server-sent events of PDO changes and EMCY, or of the
+// monitor event types of the types parameter, eg: ?types=PDO,EMCY,HEARTBEAT
+func (handler *HTTPHandler) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		httpWriteError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+
+	types := map[MonitorEventType]bool{MonitorPDO: true, MonitorEMCY: true}
+	if s := r.URL.Query().Get("types"); s != "" {
+		types = map[MonitorEventType]bool{}
+		for _, t := range strings.Split(s, ",") {
+			types[MonitorEventType(strings.ToUpper(strings.TrimSpace(t)))] = true
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Last data of PDOs, to send changes only
+	pdos := map[uint32]string{}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	NewMonitor(handler.Network).Run(ctx, func(event *MonitorEvent) {
+		if !types[event.Type] {
+			return
+		}
+
+		if event.Type == MonitorPDO {
+			data := fmt.Sprintf("%X", event.Frames[0].GetData())
+			if last, ok := pdos[event.CobID]; ok && last == data {
+				return
+			}
+
+			pdos[event.CobID] = data
+		}
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			return
+		}
+
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+			cancel()
+			return
+		}
+
+		flusher.Flush()
+	})
+}
@@ -0,0 +1,193 @@
+package canopen
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// httpTestRequest serve a request, and return the response code and body
+func httpTestRequest(handler http.Handler, method, path, body string) (int, string) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	return w.Code, strings.TrimSpace(w.Body.String())
+}
+
+func TestHTTPHandlerObjects(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 10)
+	server.Set(0x1017, 0, []byte{0xE8, 0x03})
+	server.Set(0x2000, 0, []byte{0x2A, 0x00})
+	server.Set(0x1008, 0, []byte("Drive"))
+
+	objectDic, err := DicEDSParse([]byte(testConfigDCF))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := network.AddNode(NewNode(10, nil, nil), objectDic, false); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewHTTPHandler(network)
+	handler.SDOTimeout = 20 * time.Millisecond
+
+	for _, test := range []struct {
+		method string
+		path   string
+		body   string
+		code   int
+		res    string
+	}{
+		{"GET", "/nodes/10/objects/1017/0", "", 200, `{"index":"0x1017","subIndex":0,"name":"Producer heartbeat time","dataType":"UNSIGNED16","value":1000,"raw":"E803"}`},
+		{"GET", "/nodes/10/objects/0x2000/0", "", 200, `{"index":"0x2000","subIndex":0,"name":"Setpoint","dataType":"UNSIGNED16","value":42,"raw":"2A00"}`},
+		{"GET", "/nodes/10/objects/1008/0?type=VISIBLE_STRING", "", 200, `{"index":"0x1008","subIndex":0,"dataType":"VISIBLE_STRING","value":"Drive","raw":"4472697665"}`},
+		{"PUT", "/nodes/10/objects/1017/0", `{"value":2000}`, 204, ""},
+		{"GET", "/nodes/10/objects/1017/0", "", 200, `{"index":"0x1017","subIndex":0,"name":"Producer heartbeat time","dataType":"UNSIGNED16","value":2000,"raw":"D007"}`},
+		{"PUT", "/nodes/10/objects/2000/0", `{"raw":"0700"}`, 204, ""},
+		{"PUT", "/nodes/10/objects/2000/0", `{"value":"x"}`, 400, ""},
+		{"PUT", "/nodes/10/objects/2001/0", `{"value":1}`, 400, ""},
+		{"GET", "/nodes/10/objects/2001/0", "", 404, `{"error":"SDO abort 0x06020000 on 0x2001 sub 0: object does not exist in the object dictionary","abortCode":"0x06020000"}`},
+		{"GET", "/nodes/11/objects/1017/0", "", 504, `{"error":"timeout execeded"}`},
+		{"GET", "/nodes/200/objects/1017/0", "", 400, ""},
+		{"GET", "/nodes/10/objects/zz/0", "", 400, ""},
+		{"DELETE", "/nodes/10/objects/1017/0", "", 405, ""},
+		{"GET", "/unknown", "", 404, ""},
+	} {
+		code, res := httpTestRequest(handler, test.method, test.path, test.body)
+		if code != test.code || (test.res != "" && res != test.res) {
+			t.Errorf("%s %s: expected %d %s, got %d %s", test.method, test.path, test.code, test.res, code, res)
+		}
+	}
+
+	if data := server.Get(0x2000, 0); fmt.Sprintf("%X", data) != "0700" {
+		t.Errorf("unexpected written value %X", data)
+	}
+}
+
+func TestHTTPHandlerNodes(t *testing.T) {
+	network, transport := newTestNetwork(t)
+
+	for _, id := range []int{12, 5} {
+		objectDic, err := DicEDSParse([]byte(testConfigDCF))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := network.AddNode(NewNode(id, nil, nil), objectDic, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	transport.Inject(0x705, []byte{0x05})
+	time.Sleep(10 * time.Millisecond)
+
+	handler := NewHTTPHandler(network)
+
+	code, res := httpTestRequest(handler, "GET", "/nodes", "")
+	if code != 200 {
+		t.Fatalf("unexpected status %d %s", code, res)
+	}
+
+	nodes := []HTTPNode{}
+	if err := json.Unmarshal([]byte(res), &nodes); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(nodes) != 2 || nodes[0].ID != 5 || nodes[0].State != "OPERATIONAL" || nodes[0].LastHeartbeat == nil ||
+		nodes[1].ID != 12 || nodes[1].State != "" {
+		t.Errorf("unexpected nodes %s", res)
+	}
+
+	for _, test := range []struct {
+		path string
+		body string
+		code int
+	}{
+		{"/nodes/5/nmt", `{"command":"start"}`, 204},
+		{"/nodes/0/nmt", `{"command":"reset-comm"}`, 204},
+		{"/nodes/5/nmt", `{"command":"jump"}`, 400},
+		{"/nodes/5/nmt", `{`, 400},
+	} {
+		if code, res := httpTestRequest(handler, "POST", test.path, test.body); code != test.code {
+			t.Errorf("%s %s: expected %d, got %d %s", test.path, test.body, test.code, code, res)
+		}
+	}
+
+	nmt := []string{}
+	for _, frm := range transport.Written() {
+		if frm.ArbitrationID == 0 {
+			nmt = append(nmt, fmt.Sprintf("%X", frm.GetData()))
+		}
+	}
+
+	if fmt.Sprint(nmt) != "[0105 8200]" {
+		t.Errorf("unexpected NMT commands %v", nmt)
+	}
+}
+
+func TestHTTPHandlerEvents(t *testing.T) {
+	network, transport := newTestNetwork(t)
+
+	objectDic, err := DicEDSParse([]byte(testConfigDCF))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := network.AddNode(NewNode(10, nil, nil), objectDic, false); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(NewHTTPHandler(network))
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	// Give the handler monitor some time to start
+	time.Sleep(10 * time.Millisecond)
+
+	for _, frm := range []struct {
+		arbID uint32
+		data  []byte
+	}{
+		{0x18A, []byte{0x2A, 0x00}},
+		{0x18A, []byte{0x2A, 0x00}},
+		{0x70A, []byte{0x05}},
+		{0x08A, []byte{0x10, 0x81, 0x11, 0, 0, 0, 0, 0}},
+		{0x18A, []byte{0x2B, 0x00}},
+	} {
+		transport.Inject(frm.arbID, frm.data)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	events := []string{}
+	scanner := bufio.NewScanner(res.Body)
+
+	for len(events) < 3 && scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "event: ") {
+			events = append(events, strings.TrimPrefix(line, "event: "))
+		} else if strings.HasPrefix(line, "data: ") && !json.Valid([]byte(strings.TrimPrefix(line, "data: "))) {
+			t.Errorf("invalid event data %q", line)
+		}
+	}
+
+	if strings.Join(events, ",") != "PDO,EMCY,PDO" {
+		t.Errorf("unexpected events %v", events)
+	}
+}
@@ -0,0 +1,66 @@
+package canopen
+
+import "fmt"
+
+// Logger is a structured logger, args being key value pairs. It is implemented
+// by *slog.Logger, and adapted easily to other loggers
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// nopLogger is the default Logger, discarding logs
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, args ...interface{}) {}
+func (nopLogger) Info(msg string, args ...interface{})  {}
+func (nopLogger) Warn(msg string, args ...interface{})  {}
+func (nopLogger) Error(msg string, args ...interface{}) {}
+
+// logger return the network Logger, or a Logger discarding logs
+func (network *Network) logger() Logger {
+	if network == nil || network.Logger == nil {
+		return nopLogger{}
+	}
+
+	return network.Logger
+}
+
+// SetTrace enable or disable debug logs of the protocol traffic of nodeID: SDO
+// requests and responses, PDO decoding and NMT transitions. Node 0 trace all nodes
+func (network *Network) SetTrace(nodeID int, enabled bool) {
+	network.Lock()
+	defer network.Unlock()
+
+	if network.traceNodes == nil {
+		network.traceNodes = map[int]bool{}
+	}
+
+	if enabled {
+		network.traceNodes[nodeID] = true
+	} else {
+		delete(network.traceNodes, nodeID)
+	}
+}
+
+// trace log a debug message about nodeID, if its tracing is enabled
+func (network *Network) trace(nodeID int, msg string, args ...interface{}) {
+	if network == nil || network.Logger == nil {
+		return
+	}
+
+	network.Lock()
+	enabled := network.traceNodes[nodeID] || network.traceNodes[0]
+	network.Unlock()
+
+	if enabled {
+		network.Logger.Debug(msg, append([]interface{}{"node", nodeID}, args...)...)
+	}
+}
+
+// logData format frame data for logs
+func logData(data []byte) string {
+	return fmt.Sprintf("% X", data)
+}
@@ -0,0 +1,89 @@
+package canopen
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testLogger record logs, as "LEVEL msg key=value ..."
+type testLogger struct {
+	sync.Mutex
+	logs []string
+}
+
+func (logger *testLogger) log(level, msg string, args ...interface{}) {
+	logger.Lock()
+	defer logger.Unlock()
+
+	parts := []string{level, msg}
+	for i := 0; i+1 < len(args); i += 2 {
+		parts = append(parts, fmt.Sprintf("%v=%v", args[i], args[i+1]))
+	}
+
+	logger.logs = append(logger.logs, strings.Join(parts, " "))
+}
+
+func (logger *testLogger) Debug(msg string, args ...interface{}) { logger.log("DEBUG", msg, args...) }
+func (logger *testLogger) Info(msg string, args ...interface{})  { logger.log("INFO", msg, args...) }
+func (logger *testLogger) Warn(msg string, args ...interface{})  { logger.log("WARN", msg, args...) }
+func (logger *testLogger) Error(msg string, args ...interface{}) { logger.log("ERROR", msg, args...) }
+
+// Logs return recorded logs
+func (logger *testLogger) Logs() []string {
+	logger.Lock()
+	defer logger.Unlock()
+
+	return append([]string{}, logger.logs...)
+}
+
+func TestNetworkTrace(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	newFakeSDOServer(transport, 10).Set(0x1017, 0, []byte{0xE8, 0x03})
+	newFakeSDOServer(transport, 11).Set(0x1017, 0, []byte{0xE8, 0x03})
+
+	logger := &testLogger{}
+	network.Logger = logger
+	network.SetTrace(10, true)
+
+	// Without object dictionary
+	if _, err := network.AddNode(NewNode(10, nil, nil), nil, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := network.AddNode(nil, nil, false); err == nil {
+		t.Error("expected nil node error")
+	}
+
+	for _, id := range []int{10, 11} {
+		if _, err := NewNode(id, network, nil).sdo().Read(0x1017, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	transport.Inject(0x70A, []byte{0x00})
+	time.Sleep(10 * time.Millisecond)
+
+	if err := NewNMTMaster(10, network).SendCommand(0x01); err != nil {
+		t.Fatal(err)
+	}
+
+	network.SetTrace(10, false)
+	if err := NewNMTMaster(10, network).SendCommand(0x02); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"DEBUG node added node=10 uploadEDS=false",
+		"DEBUG SDO request node=10 cobId=1546 data=40 17 10 00 00 00 00 00",
+		"DEBUG SDO response node=10 cobId=1418 data=4B 17 10 00 E8 03 00 00",
+		"DEBUG NMT state changed node=10 from=INITIALISING to=PRE-OPERATIONAL bootUp=true",
+		"DEBUG NMT command node=10 command=START",
+	}
+
+	if logs := logger.Logs(); strings.Join(logs, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected logs\n%s", strings.Join(logs, "\n"))
+	}
+}
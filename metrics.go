@@ -0,0 +1,285 @@
+package canopen
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// MetricsSDOBuckets are the upper bounds of SDO latency histograms
+var MetricsSDOBuckets = []time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	20 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+}
+
+// MetricsHistogram is a cumulative histogram of durations
+type MetricsHistogram struct {
+	Count uint64
+	Sum   time.Duration
+
+	// Counts are the cumulative counts of observations lower or equal to
+	// Bounds of the same index
+	Bounds []time.Duration
+	Counts []uint64
+}
+
+// observe add d to histogram
+func (histogram *MetricsHistogram) observe(d time.Duration) {
+	histogram.Count++
+	histogram.Sum += d
+
+	for i, bound := range histogram.Bounds {
+		if d <= bound {
+			histogram.Counts[i]++
+		}
+	}
+}
+
+// MetricsSDO are the SDO transfers metrics of a node
+type MetricsSDO struct {
+	// Latency of complete transfers, aborted ones included
+	Latency MetricsHistogram
+
+	Aborts   uint64
+	Timeouts uint64
+}
+
+// MetricsPDO are the reception metrics of a PDO COB-ID
+type MetricsPDO struct {
+	Frames uint64
+	Last   time.Time
+
+	// Period is the smoothed reception period, and Jitter the smoothed
+	// deviation of periods from it, as the RFC 3550 interarrival jitter
+	Period time.Duration
+	Jitter time.Duration
+}
+
+// MetricsSnapshot are the metrics of a network at a given time
+type MetricsSnapshot struct {
+	Time time.Time
+
+	// Frames counts received frames by service, see MonitorEventType, and
+	// FramesPerSecond are the counts of the last complete second
+	Frames          map[MonitorEventType]uint64
+	FramesPerSecond map[MonitorEventType]float64
+
+	// DroppedFrames counts frames not delivered to a busy NetworkFramesChan
+	DroppedFrames uint64
+
+	// SDO by node id
+	SDO map[int]MetricsSDO
+
+	// HeartbeatAge is the time since the last heartbeat by node id
+	HeartbeatAge map[int]time.Duration
+
+	// PDO by COB-ID
+	PDO map[uint32]MetricsPDO
+}
+
+// MetricsSource provide metrics snapshots, to export them to any monitoring
+// system, see the promcanopen package for Prometheus
+type MetricsSource interface {
+	Snapshot() MetricsSnapshot
+}
+
+// Metrics collect bus and protocol metrics of a network: frames by service,
+// dropped frames, SDO latencies and errors, heartbeats and PDO periods
+type Metrics struct {
+	sync.Mutex
+
+	frames  map[MonitorEventType]uint64
+	dropped uint64
+
+	// second is the current second, counted in secondFrames, and
+	// lastSecondFrames the counts of the previous one
+	second           time.Time
+	secondFrames     map[MonitorEventType]uint64
+	lastSecondFrames map[MonitorEventType]uint64
+
+	sdos       map[int]*MetricsSDO
+	heartbeats map[int]time.Time
+	pdos       map[uint32]*MetricsPDO
+}
+
+// NewMetrics return Metrics collecting metrics of network
+func NewMetrics(network *Network) *Metrics {
+	metrics := &Metrics{
+		frames:       map[MonitorEventType]uint64{},
+		secondFrames: map[MonitorEventType]uint64{},
+		sdos:         map[int]*MetricsSDO{},
+		heartbeats:   map[int]time.Time{},
+		pdos:         map[uint32]*MetricsPDO{},
+	}
+
+	network.Lock()
+	network.Metrics = metrics
+	network.Unlock()
+
+	return metrics
+}
+
+// metricsService return the service of a COB-ID of the predefined connection set
+func metricsService(cobID uint32) MonitorEventType {
+	switch {
+	case cobID == 0x000:
+		return MonitorNMT
+	case cobID == 0x080:
+		return MonitorSync
+	case cobID > 0x080 && cobID <= 0x0FF:
+		return MonitorEMCY
+	case cobID == 0x100:
+		return MonitorTime
+	case cobID > 0x180 && cobID <= 0x57F:
+		return MonitorPDO
+	case cobID > 0x580 && cobID <= 0x5FF, cobID > 0x600 && cobID <= 0x67F:
+		return MonitorSDO
+	case cobID > 0x700 && cobID <= 0x77F:
+		return MonitorHeartbeat
+	case cobID == 0x7E4 || cobID == 0x7E5:
+		return MonitorLSS
+	}
+
+	return MonitorUnknown
+}
+
+// observeFrame count a frame received at t, and dropped, the number of
+// frames chans it was not delivered to
+func (metrics *Metrics) observeFrame(cobID uint32, t time.Time, dropped int) {
+	metrics.Lock()
+	defer metrics.Unlock()
+
+	service := metricsService(cobID)
+	metrics.frames[service]++
+	metrics.dropped += uint64(dropped)
+
+	// Frames per second
+	if second := t.Truncate(time.Second); !second.Equal(metrics.second) {
+		metrics.lastSecondFrames = nil
+		if second.Equal(metrics.second.Add(time.Second)) {
+			metrics.lastSecondFrames = metrics.secondFrames
+		}
+
+		metrics.second = second
+		metrics.secondFrames = map[MonitorEventType]uint64{}
+	}
+
+	metrics.secondFrames[service]++
+
+	switch service {
+	case MonitorHeartbeat:
+		metrics.heartbeats[int(cobID&0x7F)] = t
+	case MonitorPDO:
+		pdo, ok := metrics.pdos[cobID]
+		if !ok {
+			pdo = &MetricsPDO{}
+			metrics.pdos[cobID] = pdo
+		}
+
+		if pdo.Frames > 0 {
+			period := t.Sub(pdo.Last)
+
+			if pdo.Frames == 1 {
+				pdo.Period = period
+			}
+
+			deviation := period - pdo.Period
+			if deviation < 0 {
+				deviation = -deviation
+			}
+
+			pdo.Jitter += (deviation - pdo.Jitter) / 16
+			pdo.Period += (period - pdo.Period) / 16
+		}
+
+		pdo.Frames++
+		pdo.Last = t
+	}
+}
+
+// observeSDO add an SDO transfer of nodeID, of duration d and ended with err
+func (metrics *Metrics) observeSDO(nodeID int, d time.Duration, err error) {
+	metrics.Lock()
+	defer metrics.Unlock()
+
+	sdo, ok := metrics.sdos[nodeID]
+	if !ok {
+		sdo = &MetricsSDO{Latency: MetricsHistogram{
+			Bounds: MetricsSDOBuckets,
+			Counts: make([]uint64, len(MetricsSDOBuckets)),
+		}}
+		metrics.sdos[nodeID] = sdo
+	}
+
+	var abortErr *SDOAbortError
+
+	switch {
+	case errors.Is(err, ErrSDOTimeout):
+		sdo.Timeouts++
+		return
+	case errors.As(err, &abortErr):
+		sdo.Aborts++
+	}
+
+	sdo.Latency.observe(d)
+}
+
+// Snapshot return the current metrics
+func (metrics *Metrics) Snapshot() MetricsSnapshot {
+	metrics.Lock()
+	defer metrics.Unlock()
+
+	now := time.Now()
+	snapshot := MetricsSnapshot{
+		Time:            now,
+		Frames:          map[MonitorEventType]uint64{},
+		FramesPerSecond: map[MonitorEventType]float64{},
+		DroppedFrames:   metrics.dropped,
+		SDO:             map[int]MetricsSDO{},
+		HeartbeatAge:    map[int]time.Duration{},
+		PDO:             map[uint32]MetricsPDO{},
+	}
+
+	for service, count := range metrics.frames {
+		snapshot.Frames[service] = count
+	}
+
+	// Counts of the last complete second
+	lastSecondFrames := map[MonitorEventType]uint64{}
+	switch second := now.Truncate(time.Second); {
+	case second.Equal(metrics.second):
+		lastSecondFrames = metrics.lastSecondFrames
+	case second.Equal(metrics.second.Add(time.Second)):
+		lastSecondFrames = metrics.secondFrames
+	}
+
+	for service := range metrics.frames {
+		snapshot.FramesPerSecond[service] = float64(lastSecondFrames[service])
+	}
+
+	for nodeID, sdo := range metrics.sdos {
+		copied := *sdo
+		copied.Latency.Counts = append([]uint64{}, sdo.Latency.Counts...)
+		snapshot.SDO[nodeID] = copied
+	}
+
+	for nodeID, t := range metrics.heartbeats {
+		snapshot.HeartbeatAge[nodeID] = now.Sub(t)
+	}
+
+	for cobID, pdo := range metrics.pdos {
+		snapshot.PDO[cobID] = *pdo
+	}
+
+	return snapshot
+}
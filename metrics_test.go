@@ -0,0 +1,90 @@
+package canopen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	newFakeSDOServer(transport, 10).Set(0x1017, 0, []byte{0xE8, 0x03})
+
+	metrics := NewMetrics(network)
+
+	// Never read, its frames are dropped
+	framesChan := network.AcquireFramesChan(nil)
+	defer network.ReleaseFramesChan(framesChan.ID)
+
+	transport.Inject(0x70A, []byte{0x05})
+	transport.Inject(0x080, nil)
+	time.Sleep(10 * time.Millisecond)
+
+	sdoClient := NewNode(10, network, nil).sdo()
+	sdoClient.Timeout = 10 * time.Millisecond
+	sdoClient.Retries = 1
+
+	if _, err := sdoClient.Read(0x1017, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sdoClient.Read(0x2001, 0); err == nil {
+		t.Fatal("expected abort")
+	}
+
+	sdoClient = NewNode(11, network, nil).sdo()
+	sdoClient.Timeout = 10 * time.Millisecond
+	sdoClient.Retries = 1
+
+	if _, err := sdoClient.Read(0x1017, 0); err == nil {
+		t.Fatal("expected timeout")
+	}
+
+	// PDO periods of 10ms, with a 2ms jitter
+	start := time.Now().Add(-time.Second)
+	for _, offset := range []int{0, 10, 22, 30, 42, 50} {
+		metrics.observeFrame(0x18A, start.Add(time.Duration(offset)*time.Millisecond), 0)
+	}
+
+	snapshot := metrics.Snapshot()
+
+	if snapshot.Frames[MonitorHeartbeat] != 1 || snapshot.Frames[MonitorSync] != 1 || snapshot.Frames[MonitorPDO] != 6 {
+		t.Errorf("unexpected frames %v", snapshot.Frames)
+	}
+
+	if snapshot.Frames[MonitorSDO] == 0 || snapshot.DroppedFrames == 0 {
+		t.Errorf("unexpected SDO frames %v and dropped frames %d", snapshot.Frames, snapshot.DroppedFrames)
+	}
+
+	if sdo := snapshot.SDO[10]; sdo.Latency.Count != 2 || sdo.Aborts != 1 || sdo.Timeouts != 0 ||
+		sdo.Latency.Counts[len(sdo.Latency.Counts)-1] != 2 {
+		t.Errorf("unexpected node 10 SDO metrics %+v", sdo)
+	}
+
+	if sdo := snapshot.SDO[11]; sdo.Latency.Count != 0 || sdo.Timeouts != 1 {
+		t.Errorf("unexpected node 11 SDO metrics %+v", sdo)
+	}
+
+	if age, ok := snapshot.HeartbeatAge[10]; !ok || age <= 0 || age > time.Second {
+		t.Errorf("unexpected heartbeat age %v", snapshot.HeartbeatAge)
+	}
+
+	pdo := snapshot.PDO[0x18A]
+	if pdo.Frames != 6 || pdo.Period < 9*time.Millisecond || pdo.Period > 11*time.Millisecond ||
+		pdo.Jitter <= 0 || pdo.Jitter > 2*time.Millisecond {
+		t.Errorf("unexpected PDO metrics %+v", pdo)
+	}
+}
+
+func TestMetricsFramesPerSecond(t *testing.T) {
+	network, _ := newTestNetwork(t)
+	metrics := NewMetrics(network)
+
+	second := time.Now().Truncate(time.Second).Add(-time.Second)
+	for i := 0; i < 5; i++ {
+		metrics.observeFrame(0x080, second.Add(time.Duration(i)*time.Millisecond), 0)
+	}
+
+	if fps := metrics.Snapshot().FramesPerSecond[MonitorSync]; fps != 5 {
+		t.Errorf("expected 5 frames per second, got %v", fps)
+	}
+}
@@ -0,0 +1,353 @@
+package canopen
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angelodlfrtr/go-can"
+)
+
+// MonitorEventType is the protocol of a MonitorEvent
+type MonitorEventType string
+
+// Monitor event types
+const (
+	MonitorNMT       MonitorEventType = "NMT"
+	MonitorSync      MonitorEventType = "SYNC"
+	MonitorEMCY      MonitorEventType = "EMCY"
+	MonitorTime      MonitorEventType = "TIME"
+	MonitorPDO       MonitorEventType = "PDO"
+	MonitorSDO       MonitorEventType = "SDO"
+	MonitorHeartbeat MonitorEventType = "HEARTBEAT"
+	MonitorLSS       MonitorEventType = "LSS"
+	MonitorUnknown   MonitorEventType = "UNKNOWN"
+)
+
+// monitorNMTCommands name NMT command specifiers
+var monitorNMTCommands = map[byte]string{
+	0x01: "START",
+	0x02: "STOP",
+	0x80: "ENTER PRE-OPERATIONAL",
+	0x81: "RESET NODE",
+	0x82: "RESET COMMUNICATION",
+}
+
+// MonitorSignal is a decoded PDO mapped object
+type MonitorSignal struct {
+	Index    uint16 `json:"index"`
+	SubIndex uint8  `json:"subIndex"`
+	Name     string `json:"name,omitempty"`
+	Value    string `json:"value"`
+
+	// Data is the little endian value
+	Data []byte `json:"-"`
+}
+
+// MonitorEvent is a decoded frame, or a complete SDO transfer. Values are in EDS
+// notation, or hexadecimal when their type is unknown
+type MonitorEvent struct {
+	Time   time.Time        `json:"time"`
+	Type   MonitorEventType `json:"type"`
+	CobID  uint32           `json:"cobId"`
+	NodeID int              `json:"node,omitempty"`
+
+	// Summary is a human readable description of the event
+	Summary string `json:"summary"`
+
+	// NMT command, or heartbeat state
+	Command string `json:"command,omitempty"`
+	State   string `json:"state,omitempty"`
+
+	// SYNC counter
+	Counter *int `json:"counter,omitempty"`
+
+	// EMCY error
+	ErrorCode     *uint16 `json:"errorCode,omitempty"`
+	ErrorRegister *uint8  `json:"errorRegister,omitempty"`
+
+	// SDO transfer, Direction is read or write
+	Index     *uint16 `json:"index,omitempty"`
+	SubIndex  *uint8  `json:"subIndex,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Direction string  `json:"direction,omitempty"`
+	Value     string  `json:"value,omitempty"`
+	AbortCode *uint32 `json:"abortCode,omitempty"`
+
+	// PDO mapped objects
+	Signals []MonitorSignal `json:"signals,omitempty"`
+
+	// Frames of the event, several for SDO transfers
+	Frames []*can.Frame `json:"-"`
+}
+
+// Monitor decode frames of a network into CANopen events. Object dictionaries
+// are used to name SDO objects and decode PDOs, from ObjectDics, from network
+// nodes, or DefaultObjectDic
+type Monitor struct {
+	sync.Mutex
+
+	Network *Network
+
+	// ObjectDics map node ids to their object dictionary
+	ObjectDics map[int]*DicObjectDic
+
+	// DefaultObjectDic is used for nodes without object dictionary
+	DefaultObjectDic *DicObjectDic
+
+	// pdos map COB-IDs to PDO mappings, built on first use per node
+	pdos      map[uint32]*monitorPDO
+	pdosNodes map[int]bool
+
+	// sdos contain SDO transfers in progress by node id
+	sdos map[int]*monitorSDO
+}
+
+// NewMonitor return a Monitor of network
+func NewMonitor(network *Network) *Monitor {
+	return &Monitor{
+		Network:    network,
+		ObjectDics: map[int]*DicObjectDic{},
+		pdos:       map[uint32]*monitorPDO{},
+		pdosNodes:  map[int]bool{},
+		sdos:       map[int]*monitorSDO{},
+	}
+}
+
+// AddObjectDic set the object dictionary of node nodeID
+func (monitor *Monitor) AddObjectDic(nodeID int, objectDic *DicObjectDic) {
+	monitor.Lock()
+	defer monitor.Unlock()
+
+	monitor.ObjectDics[nodeID] = objectDic
+
+	// PDO mappings are built again
+	monitor.pdos = map[uint32]*monitorPDO{}
+	monitor.pdosNodes = map[int]bool{}
+}
+
+// monitorFrame is a frame with its reception time
+type monitorFrame struct {
+	frm *can.Frame
+	t   time.Time
+}
+
+// Run call fn with events decoded from network frames, until ctx is done
+func (monitor *Monitor) Run(ctx context.Context, fn func(*MonitorEvent)) error {
+	framesChan := monitor.Network.AcquireFramesChan(nil)
+
+	// Network drop frames of busy chans, frames are buffered while fn run
+	frames := make(chan monitorFrame, 1024)
+
+	go func() {
+		defer close(frames)
+
+		for frm := range framesChan.C {
+			select {
+			case frames <- monitorFrame{frm: frm, t: time.Now()}:
+			default:
+			}
+		}
+	}()
+
+	defer func() {
+		monitor.Network.ReleaseFramesChan(framesChan.ID)
+
+		// Wait for the relay goroutine end
+		for range frames {
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case received, ok := <-frames:
+			if !ok {
+				return nil
+			}
+
+			if event := monitor.Decode(received.frm, received.t); event != nil {
+				fn(event)
+			}
+		}
+	}
+}
+
+// objectDic return the object dictionary of nodeID, or nil. monitor must be locked
+func (monitor *Monitor) objectDic(nodeID int) *DicObjectDic {
+	if objectDic, ok := monitor.ObjectDics[nodeID]; ok {
+		return objectDic
+	}
+
+	if monitor.Network != nil {
+		monitor.Network.Lock()
+		node, ok := monitor.Network.Nodes[nodeID]
+		monitor.Network.Unlock()
+
+		if ok && node.ObjectDic != nil {
+			return node.ObjectDic
+		}
+	}
+
+	return monitor.DefaultObjectDic
+}
+
+// variable return the variable at index and subIndex of nodeID dictionary, or nil
+func (monitor *Monitor) variable(nodeID int, index uint16, subIndex uint8) *DicVariable {
+	if objectDic := monitor.objectDic(nodeID); objectDic != nil {
+		return objectDic.FindVariable(index, subIndex)
+	}
+
+	return nil
+}
+
+// Decode frm received at t. It return nil for frames of an SDO transfer in progress
+func (monitor *Monitor) Decode(frm *can.Frame, t time.Time) *MonitorEvent {
+	monitor.Lock()
+	defer monitor.Unlock()
+
+	cobID := frm.ArbitrationID
+	data := frm.GetData()
+	nodeID := int(cobID & 0x7F)
+
+	event := &MonitorEvent{
+		Time:   t,
+		Type:   MonitorUnknown,
+		CobID:  cobID,
+		NodeID: nodeID,
+		Frames: []*can.Frame{frm},
+	}
+
+	switch {
+	case cobID == 0x000:
+		monitor.decodeNMT(event, data)
+	case cobID == 0x080:
+		monitor.decodeSync(event, data)
+	case cobID > 0x080 && cobID <= 0x0FF:
+		monitor.decodeEMCY(event, data)
+	case cobID == 0x100:
+		monitor.decodeTime(event, data)
+	case cobID > 0x580 && cobID <= 0x5FF, cobID > 0x600 && cobID <= 0x67F:
+		return monitor.decodeSDO(event, frm)
+	case cobID > 0x700 && cobID <= 0x77F:
+		monitor.decodeHeartbeat(event, data)
+	case cobID == 0x7E4 || cobID == 0x7E5:
+		event.Type = MonitorLSS
+		event.NodeID = 0
+		event.Summary = fmt.Sprintf("LSS % X", data)
+	default:
+		if !monitor.decodePDO(event, data) {
+			event.NodeID = 0
+			event.Summary = fmt.Sprintf("% X", data)
+		}
+	}
+
+	return event
+}
+
+func (monitor *Monitor) decodeNMT(event *MonitorEvent, data []byte) {
+	event.Type = MonitorNMT
+	event.NodeID = 0
+
+	if len(data) < 2 {
+		event.Summary = fmt.Sprintf("invalid NMT % X", data)
+		return
+	}
+
+	event.NodeID = int(data[1])
+	event.Command = monitorNMTCommands[data[0]]
+	if event.Command == "" {
+		event.Command = fmt.Sprintf("0x%02X", data[0])
+	}
+
+	target := "all nodes"
+	if data[1] != 0 {
+		target = fmt.Sprintf("node %d", data[1])
+	}
+
+	event.Summary = fmt.Sprintf("%s %s", event.Command, target)
+}
+
+func (monitor *Monitor) decodeSync(event *MonitorEvent, data []byte) {
+	event.Type = MonitorSync
+	event.NodeID = 0
+	event.Summary = "SYNC"
+
+	if len(data) > 0 {
+		counter := int(data[0])
+		event.Counter = &counter
+		event.Summary = fmt.Sprintf("SYNC counter %d", counter)
+	}
+}
+
+func (monitor *Monitor) decodeEMCY(event *MonitorEvent, data []byte) {
+	event.Type = MonitorEMCY
+
+	if len(data) < 3 {
+		event.Summary = fmt.Sprintf("invalid EMCY % X", data)
+		return
+	}
+
+	code := binary.LittleEndian.Uint16(data)
+	register := data[2]
+	event.ErrorCode = &code
+	event.ErrorRegister = &register
+	event.Summary = fmt.Sprintf(
+		"EMCY 0x%04X %s register 0x%02X data % X",
+		code, EMCYDescription(code), register, data[3:],
+	)
+}
+
+func (monitor *Monitor) decodeTime(event *MonitorEvent, data []byte) {
+	event.Type = MonitorTime
+	event.NodeID = 0
+
+	t, err := decodeDicTime(data)
+	if err != nil {
+		event.Summary = fmt.Sprintf("invalid TIME % X", data)
+		return
+	}
+
+	event.Value = t.UTC().Format(time.RFC3339Nano)
+	event.Summary = "TIME " + event.Value
+}
+
+func (monitor *Monitor) decodeHeartbeat(event *MonitorEvent, data []byte) {
+	event.Type = MonitorHeartbeat
+
+	if len(data) < 1 {
+		event.Summary = "invalid heartbeat"
+		return
+	}
+
+	state := int(data[0] & 0x7F)
+	name, ok := NMTStates[state]
+
+	switch {
+	case state == 0:
+		name = "BOOT-UP"
+	case !ok:
+		name = fmt.Sprintf("0x%02X", state)
+	}
+
+	event.State = name
+	event.Summary = fmt.Sprintf("node %d %s", event.NodeID, name)
+}
+
+// monitorValue format data of a variable, hexadecimal without variable
+func monitorValue(variable *DicVariable, data []byte) string {
+	if variable == nil || variable.DataType == Domain {
+		return strings.ToUpper(fmt.Sprintf("%x", data))
+	}
+
+	value := DicFormatValue(variable.DataType, data)
+	if variable.Unit != "" {
+		value += " " + variable.Unit
+	}
+
+	return value
+}
@@ -0,0 +1,100 @@
+package canopen
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MonitorFormat is an output format of MonitorWriter
+type MonitorFormat string
+
+// Monitor output formats
+const (
+	// MonitorFormatTable is an aligned human readable table
+	MonitorFormatTable MonitorFormat = "table"
+	// MonitorFormatJSON is one JSON object per line
+	MonitorFormatJSON MonitorFormat = "json"
+	// MonitorFormatCandump is like candump -L, one line per frame, with the
+	// event summary as comment
+	MonitorFormatCandump MonitorFormat = "candump"
+)
+
+// ParseMonitorFormat return the format named s
+func ParseMonitorFormat(s string) (MonitorFormat, error) {
+	switch format := MonitorFormat(strings.ToLower(s)); format {
+	case MonitorFormatTable, MonitorFormatJSON, MonitorFormatCandump:
+		return format, nil
+	}
+
+	return "", fmt.Errorf("unknown monitor format %q", s)
+}
+
+// MonitorWriter write monitor events to W in Format
+type MonitorWriter struct {
+	W      io.Writer
+	Format MonitorFormat
+
+	headerDone bool
+}
+
+// NewMonitorWriter return a MonitorWriter
+func NewMonitorWriter(w io.Writer, format MonitorFormat) *MonitorWriter {
+	return &MonitorWriter{W: w, Format: format}
+}
+
+// Write event
+func (writer *MonitorWriter) Write(event *MonitorEvent) error {
+	switch writer.Format {
+	case MonitorFormatJSON:
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(writer.W, "%s\n", data)
+		return err
+	case MonitorFormatCandump:
+		for i, frm := range event.Frames {
+			line := fmt.Sprintf(
+				"(%d.%06d) %03X#%X",
+				event.Time.Unix(), event.Time.Nanosecond()/1000, frm.ArbitrationID, frm.GetData(),
+			)
+
+			// Summary on the last frame of the event
+			if i == len(event.Frames)-1 {
+				line += " ; " + event.Summary
+			}
+
+			if _, err := fmt.Fprintln(writer.W, line); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case MonitorFormatTable, "":
+		if !writer.headerDone {
+			writer.headerDone = true
+
+			if _, err := fmt.Fprintf(writer.W, "%-15s %-9s %-5s %4s  %s\n", "TIME", "TYPE", "COBID", "NODE", "EVENT"); err != nil {
+				return err
+			}
+		}
+
+		node := "-"
+		if event.NodeID != 0 {
+			node = fmt.Sprintf("%d", event.NodeID)
+		}
+
+		_, err := fmt.Fprintf(
+			writer.W, "%-15s %-9s %03X   %4s  %s\n",
+			event.Time.Format("15:04:05.000000"), event.Type, event.CobID, node, event.Summary,
+		)
+
+		return err
+	}
+
+	return errors.New("unknown monitor format")
+}
@@ -0,0 +1,190 @@
+package canopen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// monitorPDO is a PDO mapping from a node object dictionary
+type monitorPDO struct {
+	name    string
+	nodeID  int
+	entries []*monitorPDOEntry
+}
+
+// monitorPDOEntry is a mapped object, variable is nil if not in the dictionary
+type monitorPDOEntry struct {
+	index    uint16
+	subIndex uint8
+	bits     int
+	variable *DicVariable
+}
+
+// monitorPDOKinds are the PDO communication and mapping parameters offsets
+var monitorPDOKinds = []struct {
+	name    string
+	com     uint16
+	mapping uint16
+}{
+	{"RPDO", 0x1400, 0x1600},
+	{"TPDO", 0x1800, 0x1A00},
+}
+
+// decodePDO decode data with the PDO mapping of event COB-ID. It return false
+// if no dictionary map this COB-ID
+func (monitor *Monitor) decodePDO(event *MonitorEvent, data []byte) bool {
+	pdo := monitor.findPDO(event.CobID)
+	if pdo == nil {
+		return false
+	}
+
+	event.Type = MonitorPDO
+	event.NodeID = pdo.nodeID
+
+	offset := 0
+	parts := []string{}
+
+	for _, entry := range pdo.entries {
+		signal := MonitorSignal{Index: entry.index, SubIndex: entry.subIndex}
+
+		if offset+entry.bits > len(data)*8 {
+			break
+		}
+
+		value := monitorBits(data, offset, entry.bits)
+		offset += entry.bits
+		signal.Data = value
+
+		if entry.variable != nil {
+			signal.Name = entry.variable.Name
+			signal.Value = monitorValue(entry.variable, value)
+		} else {
+			signal.Value = monitorValue(nil, value)
+		}
+
+		name := signal.Name
+		if name == "" {
+			name = fmt.Sprintf("0x%04X sub %d", signal.Index, signal.SubIndex)
+		}
+
+		event.Signals = append(event.Signals, signal)
+		parts = append(parts, fmt.Sprintf("%s=%s", name, signal.Value))
+	}
+
+	event.Summary = fmt.Sprintf("node %d %s %s", pdo.nodeID, pdo.name, strings.Join(parts, " "))
+
+	return true
+}
+
+// findPDO return the PDO mapping of cobID, building mappings of known nodes
+// and of the node id of cobID when needed
+func (monitor *Monitor) findPDO(cobID uint32) *monitorPDO {
+	if pdo, ok := monitor.pdos[cobID]; ok {
+		return pdo
+	}
+
+	nodeIDs := []int{int(cobID & 0x7F)}
+	for nodeID := range monitor.ObjectDics {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+
+	if monitor.Network != nil {
+		monitor.Network.Lock()
+		for nodeID := range monitor.Network.Nodes {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+		monitor.Network.Unlock()
+	}
+
+	for _, nodeID := range nodeIDs {
+		if monitor.pdosNodes[nodeID] {
+			continue
+		}
+
+		monitor.pdosNodes[nodeID] = true
+
+		if objectDic := monitor.objectDic(nodeID); objectDic != nil {
+			monitor.buildPDOs(nodeID, objectDic)
+		}
+	}
+
+	return monitor.pdos[cobID]
+}
+
+// buildPDOs add the enabled PDO mappings of objectDic for nodeID
+func (monitor *Monitor) buildPDOs(nodeID int, objectDic *DicObjectDic) {
+	for _, kind := range monitorPDOKinds {
+		for n := uint16(0); n < 0x200; n++ {
+			cobID, ok := monitorUint(objectDic.FindVariable(kind.com+n, 1), nodeID)
+			if !ok || cobID&(1<<31) != 0 {
+				continue
+			}
+
+			count, ok := monitorUint(objectDic.FindVariable(kind.mapping+n, 0), nodeID)
+			if !ok || count == 0 {
+				continue
+			}
+
+			pdo := &monitorPDO{name: fmt.Sprintf("%s%d", kind.name, n+1), nodeID: nodeID}
+
+			for sub := 1; sub <= int(count); sub++ {
+				entry, ok := monitorUint(objectDic.FindVariable(kind.mapping+n, uint8(sub)), nodeID)
+				if !ok {
+					break
+				}
+
+				index, subIndex := uint16(entry>>16), uint8(entry>>8)
+				pdo.entries = append(pdo.entries, &monitorPDOEntry{
+					index:    index,
+					subIndex: subIndex,
+					bits:     int(entry & 0xFF),
+					variable: objectDic.FindVariable(index, subIndex),
+				})
+			}
+
+			// First mapping wins when several nodes use the same COB-ID
+			key := uint32(cobID & 0x1FFFFFFF)
+			if _, ok := monitor.pdos[key]; !ok {
+				monitor.pdos[key] = pdo
+			}
+		}
+	}
+}
+
+// monitorUint return the unsigned value of variable, from its data, or its
+// default value evaluated for nodeID
+func monitorUint(variable *DicVariable, nodeID int) (uint64, bool) {
+	if variable == nil {
+		return 0, false
+	}
+
+	if variable.Data != nil {
+		buf := make([]byte, 8)
+		copy(buf, variable.Data)
+
+		return binary.LittleEndian.Uint64(buf), true
+	}
+
+	if variable.DefaultValue == "" {
+		return 0, false
+	}
+
+	v, err := evalDicUint(variable.DefaultValue, nodeID, 64)
+
+	return v, err == nil
+}
+
+// monitorBits return size bits of data starting at bit offset, little endian
+func monitorBits(data []byte, offset, size int) []byte {
+	out := make([]byte, (size+7)/8)
+
+	for i := 0; i < size; i++ {
+		bit := offset + i
+		if data[bit/8]&(1<<uint(bit%8)) != 0 {
+			out[i/8] |= 1 << uint(i%8)
+		}
+	}
+
+	return out
+}
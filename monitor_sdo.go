@@ -0,0 +1,173 @@
+package canopen
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/angelodlfrtr/go-can"
+)
+
+// monitorSDO is an SDO transfer in progress
+type monitorSDO struct {
+	index     uint16
+	subIndex  uint8
+	direction string
+	data      []byte
+	frames    []*can.Frame
+
+	// last is true when the last download segment was sent
+	last bool
+}
+
+// decodeSDO reassemble SDO transfers of the default SDO channels. It return an
+// event when a transfer complete or abort, nil otherwise
+func (monitor *Monitor) decodeSDO(event *MonitorEvent, frm *can.Frame) *MonitorEvent {
+	event.Type = MonitorSDO
+
+	request := frm.ArbitrationID >= 0x600
+	command := frm.Data[0] & 0xE0
+	transfer := monitor.sdos[event.NodeID]
+
+	if command == SDOResponseAbort {
+		delete(monitor.sdos, event.NodeID)
+		return monitor.sdoAbortEvent(event, transfer, frm)
+	}
+
+	if request {
+		switch command {
+		case SDORequestUpload:
+			monitor.sdos[event.NodeID] = &monitorSDO{
+				index:     binary.LittleEndian.Uint16(frm.Data[1:]),
+				subIndex:  frm.Data[3],
+				direction: "read",
+				frames:    []*can.Frame{frm},
+			}
+
+			return nil
+		case SDORequestDownload:
+			transfer := &monitorSDO{
+				index:     binary.LittleEndian.Uint16(frm.Data[1:]),
+				subIndex:  frm.Data[3],
+				direction: "write",
+				frames:    []*can.Frame{frm},
+			}
+
+			if frm.Data[0]&SDOExpedited != 0 {
+				size := 4
+				if frm.Data[0]&SDOSizeSpecified != 0 {
+					size -= int((frm.Data[0] >> 2) & 0x3)
+				}
+
+				transfer.data = append([]byte{}, frm.Data[4:4+size]...)
+			}
+
+			monitor.sdos[event.NodeID] = transfer
+
+			return nil
+		case SDORequestSegmentUpload:
+			if transfer != nil {
+				transfer.frames = append(transfer.frames, frm)
+				return nil
+			}
+		case SDORequestSegmentDownload:
+			if transfer != nil && transfer.direction == "write" {
+				size := 7 - int((frm.Data[0]>>1)&0x7)
+				transfer.data = append(transfer.data, frm.Data[1:1+size]...)
+				transfer.frames = append(transfer.frames, frm)
+				transfer.last = frm.Data[0]&SDONoMoreData != 0
+
+				return nil
+			}
+		}
+
+		event.Summary = fmt.Sprintf("SDO request % X", frm.Data)
+		return event
+	}
+
+	if transfer == nil {
+		event.Summary = fmt.Sprintf("SDO response % X", frm.Data)
+		return event
+	}
+
+	transfer.frames = append(transfer.frames, frm)
+
+	switch {
+	case command == SDOResponseUpload && transfer.direction == "read":
+		if frm.Data[0]&SDOExpedited == 0 {
+			return nil
+		}
+
+		size := 4
+		if frm.Data[0]&SDOSizeSpecified != 0 {
+			size -= int((frm.Data[0] >> 2) & 0x3)
+		}
+
+		transfer.data = append([]byte{}, frm.Data[4:4+size]...)
+	case command == SDOResponseSegmentUpload && transfer.direction == "read":
+		size := 7 - int((frm.Data[0]>>1)&0x7)
+		transfer.data = append(transfer.data, frm.Data[1:1+size]...)
+
+		if frm.Data[0]&SDONoMoreData == 0 {
+			return nil
+		}
+	case command == SDOResponseDownload && transfer.direction == "write":
+		// Segmented download start
+		if transfer.data == nil {
+			return nil
+		}
+	case command == SDOResponseSegmentDownload && transfer.direction == "write":
+		if !transfer.last {
+			return nil
+		}
+	default:
+		delete(monitor.sdos, event.NodeID)
+		event.Summary = fmt.Sprintf("SDO response % X", frm.Data)
+		return event
+	}
+
+	delete(monitor.sdos, event.NodeID)
+	monitor.sdoTransferEvent(event, transfer)
+
+	return event
+}
+
+// sdoTransferEvent fill event with a complete transfer
+func (monitor *Monitor) sdoTransferEvent(event *MonitorEvent, transfer *monitorSDO) {
+	variable := monitor.variable(event.NodeID, transfer.index, transfer.subIndex)
+
+	event.Index = &transfer.index
+	event.SubIndex = &transfer.subIndex
+	event.Direction = transfer.direction
+	event.Value = monitorValue(variable, transfer.data)
+	event.Frames = transfer.frames
+
+	object := fmt.Sprintf("0x%04X sub %d", transfer.index, transfer.subIndex)
+	if variable != nil {
+		event.Name = variable.Name
+		object += " " + variable.Name
+	}
+
+	event.Summary = fmt.Sprintf("node %d %s %s = %s", event.NodeID, transfer.direction, object, event.Value)
+}
+
+// sdoAbortEvent fill event with an aborted transfer
+func (monitor *Monitor) sdoAbortEvent(event *MonitorEvent, transfer *monitorSDO, frm *can.Frame) *MonitorEvent {
+	abortErr := newSDOAbortError(frm.Data)
+
+	event.Index = &abortErr.Index
+	event.SubIndex = &abortErr.SubIndex
+	event.AbortCode = &abortErr.Code
+
+	if transfer != nil {
+		event.Direction = transfer.direction
+		event.Frames = append(transfer.frames, frm)
+	}
+
+	if variable := monitor.variable(event.NodeID, abortErr.Index, abortErr.SubIndex); variable != nil {
+		event.Name = variable.Name
+	}
+
+	event.Summary = fmt.Sprintf("node %d %s", event.NodeID, abortErr.Error())
+
+	return event
+}
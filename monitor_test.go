@@ -0,0 +1,172 @@
+package canopen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angelodlfrtr/go-can"
+)
+
+func newTestMonitor(t *testing.T) *Monitor {
+	objectDic, err := DicEDSParse([]byte(testConfigDCF))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	monitor := NewMonitor(nil)
+	monitor.AddObjectDic(10, objectDic)
+
+	return monitor
+}
+
+// decodeFrames decode frames, and return events
+func decodeFrames(monitor *Monitor, frames ...*can.Frame) []*MonitorEvent {
+	events := []*MonitorEvent{}
+
+	for _, frm := range frames {
+		if event := monitor.Decode(frm, time.Unix(0, 0)); event != nil {
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+func testFrame(arbID uint32, data ...byte) *can.Frame {
+	frm := &can.Frame{ArbitrationID: arbID, DLC: uint8(len(data))}
+	copy(frm.Data[:], data)
+
+	return frm
+}
+
+func TestMonitorDecode(t *testing.T) {
+	monitor := newTestMonitor(t)
+
+	for _, test := range []struct {
+		frm     *can.Frame
+		typ     MonitorEventType
+		summary string
+	}{
+		{testFrame(0x000, 0x01, 0x00), MonitorNMT, "START all nodes"},
+		{testFrame(0x000, 0x82, 0x0A), MonitorNMT, "RESET COMMUNICATION node 10"},
+		{testFrame(0x080, 0x03), MonitorSync, "SYNC counter 3"},
+		{testFrame(0x70A, 0x00), MonitorHeartbeat, "node 10 BOOT-UP"},
+		{testFrame(0x70A, 0x7F), MonitorHeartbeat, "node 10 PRE-OPERATIONAL"},
+		{testFrame(0x08A, 0x10, 0x81, 0x11, 0, 0, 0, 0, 0), MonitorEMCY, "EMCY 0x8110 communication register 0x11 data 00 00 00 00 00"},
+		{testFrame(0x18A, 0x34, 0x12), MonitorPDO, "node 10 TPDO1 Setpoint=0x1234"},
+		{testFrame(0x123, 0xAA), MonitorUnknown, "AA"},
+	} {
+		events := decodeFrames(monitor, test.frm)
+		if len(events) != 1 {
+			t.Fatalf("expected one event for %v, got %d", test.frm, len(events))
+		}
+
+		if events[0].Type != test.typ || events[0].Summary != test.summary {
+			t.Errorf("unexpected event %s %q, expected %s %q", events[0].Type, events[0].Summary, test.typ, test.summary)
+		}
+	}
+}
+
+func TestMonitorSDO(t *testing.T) {
+	monitor := newTestMonitor(t)
+
+	// Segmented upload of 5 bytes
+	events := decodeFrames(
+		monitor,
+		testFrame(0x60A, 0x40, 0x01, 0x20, 0x00, 0, 0, 0, 0),
+		testFrame(0x58A, 0x41, 0x01, 0x20, 0x00, 5, 0, 0, 0),
+		testFrame(0x60A, 0x60, 0, 0, 0, 0, 0, 0, 0),
+		testFrame(0x58A, 0x05, 'h', 'e', 'l', 'l', 'o', 0, 0),
+	)
+
+	if len(events) != 1 {
+		t.Fatalf("expected one segmented upload event, got %d", len(events))
+	}
+
+	event := events[0]
+	if event.Direction != "read" || *event.Index != 0x2001 || event.Value != "68656C6C6F" || len(event.Frames) != 4 {
+		t.Errorf("unexpected segmented upload event %+v", event)
+	}
+
+	// Expedited download of a known object
+	events = decodeFrames(
+		monitor,
+		testFrame(0x60A, 0x2B, 0x00, 0x20, 0x00, 0xD0, 0x07, 0, 0),
+		testFrame(0x58A, 0x60, 0x00, 0x20, 0x00, 0, 0, 0, 0),
+	)
+
+	if len(events) != 1 || events[0].Summary != "node 10 write 0x2000 sub 0 Setpoint = 0x07D0" {
+		t.Fatalf("unexpected expedited download events %+v", events)
+	}
+
+	// Aborted upload
+	events = decodeFrames(
+		monitor,
+		testFrame(0x60A, 0x40, 0x00, 0x30, 0x00, 0, 0, 0, 0),
+		testFrame(0x58A, 0x80, 0x00, 0x30, 0x00, 0x00, 0x00, 0x02, 0x06),
+	)
+
+	if len(events) != 1 || events[0].AbortCode == nil || *events[0].AbortCode != SDOAbortObjectNotExist || events[0].Direction != "read" {
+		t.Fatalf("unexpected abort events %+v", events)
+	}
+}
+
+func TestMonitorWriter(t *testing.T) {
+	monitor := newTestMonitor(t)
+	event := decodeFrames(monitor, testFrame(0x70A, 0x05))[0]
+
+	out := &bytes.Buffer{}
+	if err := NewMonitorWriter(out, MonitorFormatCandump).Write(event); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "(0.000000) 70A#05 ; node 10 OPERATIONAL\n" {
+		t.Errorf("unexpected candump line %q", out)
+	}
+
+	out.Reset()
+	if err := NewMonitorWriter(out, MonitorFormatJSON).Write(event); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := map[string]interface{}{}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded["type"] != "HEARTBEAT" || decoded["state"] != "OPERATIONAL" || decoded["node"] != 10.0 {
+		t.Errorf("unexpected JSON event %s", out)
+	}
+
+	if _, err := ParseMonitorFormat("xml"); err == nil {
+		t.Error("expected unknown format error")
+	}
+}
+
+func TestMonitorRun(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	monitor := NewMonitor(network)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		transport.Inject(0x080, nil)
+	}()
+
+	summaries := []string{}
+	if err := monitor.Run(ctx, func(event *MonitorEvent) {
+		summaries = append(summaries, event.Summary)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(summaries, ",") != "SYNC" {
+		t.Errorf("unexpected events %v", summaries)
+	}
+}
@@ -0,0 +1,276 @@
+package canopen
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MQTTClient is the MQTT client of an MQTTBridge, eg: an adapter of the paho
+// client, or an in-process broker. Subscribe handlers can be called from any
+// goroutine
+type MQTTClient interface {
+	Publish(topic string, payload []byte, retained bool) error
+	Subscribe(topic string, handler func(topic string, payload []byte)) error
+	Unsubscribe(topic string) error
+}
+
+// MQTTBridge publish network events to MQTT topics, and execute commands from
+// MQTT topics. Topics are relative to {Prefix}/{Net}/{node}, payloads are JSON:
+//
+//	{objectName}                TPDO mapped object value, retained, eg: canopen/1/10/Setpoint
+//	state                       NMT state from heartbeats, retained, eg: "OPERATIONAL"
+//	emcy                        EMCY events, see MonitorEvent
+//	error                       command errors, {"topic": ..., "error": ...}
+//	{objectName}/set            SDO write of an object value, eg: 1000
+//	sdo/{index}/{sub}/set       SDO write, eg: canopen/1/10/sdo/1017/0/set, raw
+//	                            hexadecimal without dictionary, eg: "E803"
+//	nmt/set                     NMT command: start, stop, preop, reset or reset-comm,
+//	                            node 0 for all nodes
+//
+// Object names are dictionary names, with spaces, '/', '+' and '#' replaced by '_',
+// or {index}sub{sub} without dictionary, eg: 2000sub0
+type MQTTBridge struct {
+	Network *Network
+	Client  MQTTClient
+
+	// Prefix of topics, default to canopen
+	Prefix string
+
+	// Net is the network number of topics, default to 1
+	Net int
+
+	// DefaultObjectDic is used for nodes without object dictionary
+	DefaultObjectDic *DicObjectDic
+
+	// SDOTimeout of SDO writes, default to the SDO client timeout
+	SDOTimeout time.Duration
+
+	// states contain the last published state by node id
+	states map[int]string
+}
+
+// NewMQTTBridge return an MQTTBridge of network, publishing with client
+func NewMQTTBridge(network *Network, client MQTTClient) *MQTTBridge {
+	return &MQTTBridge{
+		Network: network,
+		Client:  client,
+		Prefix:  "canopen",
+		Net:     1,
+	}
+}
+
+// base return the topics prefix of the bridge network
+func (bridge *MQTTBridge) base() string {
+	return fmt.Sprintf("%s/%d", bridge.Prefix, bridge.Net)
+}
+
+// Run publish events and execute commands until ctx is done
+func (bridge *MQTTBridge) Run(ctx context.Context) error {
+	// {node}/+/set include nmt/set
+	filters := []string{
+		bridge.base() + "/+/+/set",
+		bridge.base() + "/+/sdo/+/+/set",
+	}
+
+	for i, filter := range filters {
+		if err := bridge.Client.Subscribe(filter, bridge.handleCommand); err != nil {
+			for _, subscribed := range filters[:i] {
+				bridge.Client.Unsubscribe(subscribed)
+			}
+
+			return err
+		}
+	}
+
+	defer func() {
+		for _, filter := range filters {
+			bridge.Client.Unsubscribe(filter)
+		}
+	}()
+
+	bridge.states = map[int]string{}
+
+	monitor := NewMonitor(bridge.Network)
+	monitor.DefaultObjectDic = bridge.DefaultObjectDic
+
+	return monitor.Run(ctx, func(event *MonitorEvent) {
+		bridge.publishEvent(monitor, event)
+	})
+}
+
+// mqttTopicName return name usable as a topic level
+func mqttTopicName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '/', '+', '#':
+			return '_'
+		}
+
+		return r
+	}, name)
+}
+
+// mqttObjectName return the topic name of an object
+func mqttObjectName(variable *DicVariable, index uint16, subIndex uint8) string {
+	if variable != nil && variable.Name != "" {
+		return mqttTopicName(variable.Name)
+	}
+
+	return fmt.Sprintf("%04Xsub%d", index, subIndex)
+}
+
+// publish payload as JSON to the topic of nodeID. Publish errors are logged,
+// the client is in charge of reconnections
+func (bridge *MQTTBridge) publish(nodeID int, name string, payload interface{}, retained bool) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	topic := fmt.Sprintf("%s/%d/%s", bridge.base(), nodeID, name)
+	if err := bridge.Client.Publish(topic, data, retained); err != nil {
+		bridge.Network.logger().Warn("MQTT publish failed", "topic", topic, "error", err)
+	}
+}
+
+// publishEvent publish PDO values, state changes and EMCY of event
+func (bridge *MQTTBridge) publishEvent(monitor *Monitor, event *MonitorEvent) {
+	switch event.Type {
+	case MonitorPDO:
+		for _, signal := range event.Signals {
+			variable := monitor.variable(event.NodeID, signal.Index, signal.SubIndex)
+
+			var value interface{}
+			if variable != nil {
+				value = dicJSONValue(variable.DataType, signal.Data)
+			}
+
+			if value == nil {
+				value = strings.ToUpper(hex.EncodeToString(signal.Data))
+			}
+
+			bridge.publish(event.NodeID, mqttObjectName(variable, signal.Index, signal.SubIndex), value, true)
+		}
+	case MonitorHeartbeat:
+		if bridge.states[event.NodeID] != event.State {
+			bridge.states[event.NodeID] = event.State
+			bridge.publish(event.NodeID, "state", event.State, true)
+		}
+	case MonitorEMCY:
+		bridge.publish(event.NodeID, "emcy", event, false)
+	}
+}
+
+// objectDic return the object dictionary of nodeID, or nil
+func (bridge *MQTTBridge) objectDic(nodeID int) *DicObjectDic {
+	if node, err := bridge.Network.GetNode(nodeID); err == nil && node.ObjectDic != nil {
+		return node.ObjectDic
+	}
+
+	return bridge.DefaultObjectDic
+}
+
+// handleCommand execute a command, publishing its error if any
+func (bridge *MQTTBridge) handleCommand(topic string, payload []byte) {
+	parts := strings.Split(strings.TrimPrefix(topic, bridge.base()+"/"), "/")
+
+	if err := bridge.command(parts, payload); err != nil {
+		data, _ := json.Marshal(map[string]string{"topic": topic, "error": err.Error()})
+		bridge.Client.Publish(fmt.Sprintf("%s/%s/error", bridge.base(), parts[0]), data, false)
+	}
+}
+
+// command execute the command of topic parts, relative to the network topic
+func (bridge *MQTTBridge) command(parts []string, payload []byte) error {
+	if len(parts) < 3 || parts[len(parts)-1] != "set" {
+		return errors.New("unknown command")
+	}
+
+	nodeID, err := httpNodeID(parts[0], parts[1] == "nmt")
+	if err != nil {
+		return err
+	}
+
+	value := dicJSONText(payload)
+
+	switch {
+	case len(parts) == 3 && parts[1] == "nmt":
+		code, ok := httpNMTCommands[strings.ToLower(value)]
+		if !ok {
+			return fmt.Errorf("unknown NMT command %q", value)
+		}
+
+		return NewNMTMaster(nodeID, bridge.Network).SendCommand(code)
+	case len(parts) == 5 && parts[1] == "sdo":
+		index, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(parts[2]), "0x"), 16, 16)
+		if err != nil {
+			return fmt.Errorf("invalid index %q", parts[2])
+		}
+
+		subIndex, err := strconv.ParseUint(parts[3], 0, 8)
+		if err != nil {
+			return fmt.Errorf("invalid sub-index %q", parts[3])
+		}
+
+		var variable *DicVariable
+		if objectDic := bridge.objectDic(nodeID); objectDic != nil {
+			variable = objectDic.FindVariable(uint16(index), uint8(subIndex))
+		}
+
+		if variable == nil {
+			data, err := hex.DecodeString(value)
+			if err != nil {
+				return fmt.Errorf("invalid raw value %q: %v", value, err)
+			}
+
+			return bridge.write(nodeID, uint16(index), uint8(subIndex), data)
+		}
+
+		return bridge.writeVariable(nodeID, variable, value)
+	case len(parts) == 3:
+		if objectDic := bridge.objectDic(nodeID); objectDic != nil {
+			for _, variable := range objectDic.Variables() {
+				if mqttObjectName(variable, variable.Index, variable.SubIndex) == parts[1] {
+					return bridge.writeVariable(nodeID, variable, value)
+				}
+			}
+		}
+
+		return fmt.Errorf("unknown object %q", parts[1])
+	}
+
+	return errors.New("unknown command")
+}
+
+// writeVariable write value in EDS notation to variable of nodeID
+func (bridge *MQTTBridge) writeVariable(nodeID int, variable *DicVariable, value string) error {
+	data, err := DicEncodeValue(variable.DataType, value, nodeID)
+	if err != nil {
+		return err
+	}
+
+	return bridge.write(nodeID, variable.Index, variable.SubIndex, data)
+}
+
+// write data to an object of nodeID
+func (bridge *MQTTBridge) write(nodeID int, index uint16, subIndex uint8, data []byte) error {
+	node, err := bridge.Network.GetNode(nodeID)
+	if err != nil {
+		node = NewNode(nodeID, bridge.Network, nil)
+	}
+
+	sdoClient := node.sdo()
+	if bridge.SDOTimeout > 0 {
+		client := *sdoClient
+		client.Timeout = bridge.SDOTimeout
+		sdoClient = &client
+	}
+
+	return sdoClient.Write(index, subIndex, false, data)
+}
@@ -0,0 +1,187 @@
+package canopen
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testBroker is an in-process MQTT broker, with a single client
+type testBroker struct {
+	sync.Mutex
+
+	subscriptions map[string]func(topic string, payload []byte)
+	retained      map[string]string
+	published     []string
+}
+
+func newTestBroker() *testBroker {
+	return &testBroker{
+		subscriptions: map[string]func(topic string, payload []byte){},
+		retained:      map[string]string{},
+	}
+}
+
+// testTopicMatch return true if topic match filter, with + and # wildcards
+func testTopicMatch(filter, topic string) bool {
+	filterParts, topicParts := strings.Split(filter, "/"), strings.Split(topic, "/")
+
+	for i, part := range filterParts {
+		if part == "#" {
+			return true
+		}
+
+		if i >= len(topicParts) || (part != "+" && part != topicParts[i]) {
+			return false
+		}
+	}
+
+	return len(filterParts) == len(topicParts)
+}
+
+func (broker *testBroker) Publish(topic string, payload []byte, retained bool) error {
+	broker.Lock()
+	broker.published = append(broker.published, topic+" "+string(payload))
+	if retained {
+		broker.retained[topic] = string(payload)
+	}
+
+	handlers := []func(string, []byte){}
+	for filter, handler := range broker.subscriptions {
+		if testTopicMatch(filter, topic) {
+			handlers = append(handlers, handler)
+		}
+	}
+	broker.Unlock()
+
+	for _, handler := range handlers {
+		handler(topic, payload)
+	}
+
+	return nil
+}
+
+func (broker *testBroker) Subscribe(topic string, handler func(topic string, payload []byte)) error {
+	broker.Lock()
+	defer broker.Unlock()
+
+	broker.subscriptions[topic] = handler
+	return nil
+}
+
+func (broker *testBroker) Unsubscribe(topic string) error {
+	broker.Lock()
+	defer broker.Unlock()
+
+	delete(broker.subscriptions, topic)
+	return nil
+}
+
+// Published return published messages, as "topic payload"
+func (broker *testBroker) Published() []string {
+	broker.Lock()
+	defer broker.Unlock()
+
+	return append([]string{}, broker.published...)
+}
+
+func TestMQTTBridge(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 10)
+	server.Set(0x1017, 0, []byte{0xE8, 0x03})
+
+	objectDic, err := DicEDSParse([]byte(testConfigDCF))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := network.AddNode(NewNode(10, nil, nil), objectDic, false); err != nil {
+		t.Fatal(err)
+	}
+
+	broker := newTestBroker()
+	bridge := NewMQTTBridge(network, broker)
+	bridge.SDOTimeout = 20 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- bridge.Run(ctx) }()
+
+	// Give the bridge some time to subscribe and start its monitor
+	time.Sleep(10 * time.Millisecond)
+
+	transport.Inject(0x70A, []byte{0x7F})
+	time.Sleep(5 * time.Millisecond)
+	transport.Inject(0x70A, []byte{0x7F})
+	time.Sleep(5 * time.Millisecond)
+	transport.Inject(0x18A, []byte{0x2A, 0x00})
+	time.Sleep(5 * time.Millisecond)
+	transport.Inject(0x08A, []byte{0x10, 0x81, 0x11, 0, 0, 0, 0, 0})
+	time.Sleep(10 * time.Millisecond)
+
+	for _, command := range []struct {
+		topic   string
+		payload string
+	}{
+		{"canopen/1/10/Producer_heartbeat_time/set", "2000"},
+		{"canopen/1/10/sdo/2000/0/set", "7"},
+		{"canopen/1/10/sdo/2001/0/set", `"0100"`},
+		{"canopen/1/10/Unknown/set", "1"},
+		{"canopen/1/0/nmt/set", `"start"`},
+		{"canopen/1/10/nmt/set", "jump"},
+	} {
+		broker.Publish(command.topic, []byte(command.payload), false)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	published := []string{}
+	for _, message := range broker.Published() {
+		if !strings.HasSuffix(strings.SplitN(message, " ", 2)[0], "/set") {
+			published = append(published, message)
+		}
+	}
+
+	expected := []string{
+		`canopen/1/10/state "PRE-OPERATIONAL"`,
+		`canopen/1/10/Setpoint 42`,
+		`canopen/1/10/emcy {"time":`,
+		`canopen/1/10/error {"error":"unknown object \"Unknown\"","topic":"canopen/1/10/Unknown/set"}`,
+		`canopen/1/10/error {"error":"unknown NMT command \"jump\"","topic":"canopen/1/10/nmt/set"}`,
+	}
+
+	if len(published) != len(expected) {
+		t.Fatalf("unexpected messages %q", published)
+	}
+
+	for i, message := range published {
+		if !strings.HasPrefix(message, expected[i]) {
+			t.Errorf("expected message %q, got %q", expected[i], message)
+		}
+	}
+
+	if broker.retained["canopen/1/10/Setpoint"] != "42" {
+		t.Errorf("unexpected retained messages %v", broker.retained)
+	}
+
+	if data := server.Get(0x1017, 0); len(data) != 2 || data[0] != 0xD0 || data[1] != 0x07 {
+		t.Errorf("unexpected heartbeat time %X", data)
+	}
+
+	if data := server.Get(0x2000, 0); len(data) != 2 || data[0] != 0x07 {
+		t.Errorf("unexpected setpoint %X", data)
+	}
+
+	if data := server.Get(0x2001, 0); len(data) != 2 || data[0] != 0x01 {
+		t.Errorf("unexpected raw value %X", data)
+	}
+
+	if frm := transport.Written(); frm[len(frm)-1].ArbitrationID != 0 || frm[len(frm)-1].Data[0] != 0x01 {
+		t.Errorf("NMT start not sent, got %v", frm[len(frm)-1])
+	}
+}
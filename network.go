@@ -1,8 +1,9 @@
 package canopen
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"sort"
 	"sync"
 	"time"
 
@@ -36,6 +37,24 @@ type Network struct {
 
 	// BusReadErrChan @TODO on go-can
 	BusReadErrChan chan error
+
+	// Metrics, if not nil, collect metrics of the network, see NewMetrics
+	Metrics *Metrics
+
+	// Logger of the network and its nodes, default to no logs. Set it before
+	// Run, and enable protocol tracing with SetTrace
+	Logger Logger
+
+	// traceNodes contain node ids with tracing enabled
+	traceNodes map[int]bool
+
+	// transportFilters are the COB-IDs set as FilterTransport filters, nil for
+	// all frames, if transportFiltersSet is true
+	transportFilters    []uint32
+	transportFiltersSet bool
+
+	// sdoChannels serialize SDO transfers by request COB-ID, see SDOClient.Read
+	sdoChannels map[uint32]*sync.Mutex
 }
 
 // NewNetwork a new Network with given bus
@@ -73,11 +92,19 @@ func (network *Network) Run() error {
 				network.Lock()
 
 				// Send frame to frames chans
+				dropped := 0
 				for _, ch := range network.FramesChans {
-					ch.Publish(frm)
+					if !ch.Publish(frm) {
+						dropped++
+					}
 				}
 
+				metrics := network.Metrics
 				network.Unlock()
+
+				if metrics != nil {
+					metrics.observeFrame(frm.ArbitrationID, time.Now(), dropped)
+				}
 			}
 		}
 	}()
@@ -128,22 +155,34 @@ func (network *Network) Send(arbID uint32, data []byte) error {
 	return network.Bus.Write(frm)
 }
 
-// AddNode add a node to the network
-func (network *Network) AddNode(node *Node, objectDic *DicObjectDic, uploadEDS bool) *Node {
-	if uploadEDS {
-		// @TODO: download definition from node if true
-		log.Fatal("uploading EDS not supported for now")
+// AddNode add a node to the network, with a copy of objectDic as node.ObjectDic.
+// If uploadEDS is true, objectDic is ignored and the node object dictionary is
+// uploaded from the node itself, see Node.UploadEDS
+func (network *Network) AddNode(node *Node, objectDic *DicObjectDic, uploadEDS bool) (*Node, error) {
+	if node == nil {
+		return nil, errors.New("cannot use nil Node")
 	}
 
-	if node == nil {
-		log.Fatal("Cannot use nil Node")
-		return nil
+	// Upload EDS using a detached node, so that node is left untouched on
+	// failure
+	if uploadEDS {
+		uploaded, err := NewNode(node.ID, network, nil).UploadEDS()
+		if err != nil {
+			return nil, err
+		}
+
+		objectDic = uploaded
 	}
 
 	// Set node network
 	node.SetNetwork(network)
 
-	// Set ObjectDic
+	// Set a copy of ObjectDic, which can be shared by nodes, with $NODEID default
+	// values evaluated for node
+	if objectDic != nil {
+		objectDic = objectDic.Copy()
+		objectDic.SetNodeID(node.ID)
+	}
 	node.SetObjectDic(objectDic)
 
 	// Set nmt and listen for nmt hearbeat messages
@@ -154,7 +193,7 @@ func (network *Network) AddNode(node *Node, objectDic *DicObjectDic, uploadEDS b
 
 	// Start nmt master hearbeat listener
 	if err := node.NMTMaster.ListenForHeartbeat(); err != nil {
-		log.Fatalf("Failed to start nmt master on node %d with err %v", node.ID, err)
+		return nil, fmt.Errorf("failed to start nmt master on node %d: %v", node.ID, err)
 	}
 
 	network.Lock()
@@ -164,10 +203,12 @@ func (network *Network) AddNode(node *Node, objectDic *DicObjectDic, uploadEDS b
 		network.Nodes = map[int]*Node{}
 	}
 
+	network.logger().Debug("node added", "node", node.ID, "uploadEDS", uploadEDS)
+
 	// Append node to network
 	network.Nodes[node.ID] = node
 
-	return node
+	return node, nil
 }
 
 // GetNode by node id. Return error if node dont exist in network.Nodes
@@ -184,6 +225,27 @@ func (network *Network) GetNode(nodeID int) (*Node, error) {
 
 // AcquireFramesChan create a new FrameChan
 func (network *Network) AcquireFramesChan(filterFunc networkFramesChanFilterFunc) *NetworkFramesChan {
+	return network.acquireFramesChan(filterFunc, nil)
+}
+
+// AcquireCobIDsFramesChan create a new FrameChan receiving frames with one of
+// cobIDs, and accepted by filterFunc if not nil. Unlike AcquireFramesChan, frames
+// can be filtered by the transport, see FilterTransport
+func (network *Network) AcquireCobIDsFramesChan(filterFunc networkFramesChanFilterFunc, cobIDs ...uint32) *NetworkFramesChan {
+	ids := append([]uint32{}, cobIDs...)
+
+	filter := func(frm *can.Frame) bool {
+		if !utils.ContainsUint32(ids, frm.ArbitrationID) {
+			return false
+		}
+
+		return filterFunc == nil || (*filterFunc)(frm)
+	}
+
+	return network.acquireFramesChan(&filter, ids)
+}
+
+func (network *Network) acquireFramesChan(filterFunc networkFramesChanFilterFunc, cobIDs []uint32) *NetworkFramesChan {
 	network.Lock()
 	defer network.Unlock()
 
@@ -193,14 +255,88 @@ func (network *Network) AcquireFramesChan(filterFunc networkFramesChanFilterFunc
 		ID:     chanID,
 		Filter: filterFunc,
 		C:      make(chan *can.Frame),
+		CobIDs: cobIDs,
 	}
 
 	// Append network.FramesChans
 	network.FramesChans = append(network.FramesChans, frameChan)
 
+	if !network.transportFiltersCover(cobIDs) {
+		network.updateTransportFilters()
+	}
+
 	return frameChan
 }
 
+// transportFiltersCover return true if frames of cobIDs, all frames if nil, are
+// received through the transport filters. network must be locked
+func (network *Network) transportFiltersCover(cobIDs []uint32) bool {
+	if !network.transportFiltersSet {
+		return false
+	}
+
+	if network.transportFilters == nil {
+		return true
+	}
+
+	if cobIDs == nil {
+		return false
+	}
+
+	for _, cobID := range cobIDs {
+		if !utils.ContainsUint32(network.transportFilters, cobID) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// updateTransportFilters set the filters of a FilterTransport to the COB-IDs
+// of the frames chans. network must be locked.
+//
+// SetFilters is a system call with socketcan, made under the network lock, so
+// filters are updated only when a chan needs COB-IDs they don't cover. COB-IDs
+// of released chans are removed at the next update, or when a chan receiving
+// all frames is released. Frames are filtered again by frames chans, extra
+// COB-IDs only cost frames dropped in user space, eg: SDO transfers to a node
+// keep its SDO response COB-ID in filters
+func (network *Network) updateTransportFilters() {
+	transport, ok := network.Bus.Transport.(FilterTransport)
+	if !ok {
+		return
+	}
+
+	cobIDs := []uint32{}
+
+	for _, framesChan := range network.FramesChans {
+		// All frames are needed
+		if framesChan.CobIDs == nil {
+			cobIDs = nil
+			break
+		}
+
+		for _, cobID := range framesChan.CobIDs {
+			if !utils.ContainsUint32(cobIDs, cobID) {
+				cobIDs = append(cobIDs, cobID)
+			}
+		}
+	}
+
+	sort.Slice(cobIDs, func(i, j int) bool { return cobIDs[i] < cobIDs[j] })
+
+	// Filters are an optimization, frames are filtered again by frames chans.
+	// On error, filters are set again at the next update
+	if err := transport.SetFilters(cobIDs); err != nil {
+		network.transportFiltersSet = false
+		network.logger().Error("transport filters update failed", "cobIds", len(cobIDs), "error", err)
+		return
+	}
+
+	network.transportFilters = cobIDs
+	network.transportFiltersSet = true
+}
+
 // ReleaseFramesChan release (close) a FrameChan
 func (network *Network) ReleaseFramesChan(id string) {
 	network.Lock()
@@ -230,6 +366,11 @@ func (network *Network) ReleaseFramesChan(id string) {
 		network.FramesChans[:*framesChanIndex],
 		network.FramesChans[*framesChanIndex+1:]...,
 	)
+
+	// Other COB-IDs are kept until the next update, see updateTransportFilters
+	if framesChan.CobIDs == nil {
+		network.updateTransportFilters()
+	}
 }
 
 // Search send data to network and wait for nodes response
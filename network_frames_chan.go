@@ -14,22 +14,31 @@ type NetworkFramesChan struct {
 	ID     string
 	C      chan *can.Frame
 	Filter networkFramesChanFilterFunc
+
+	// CobIDs, if not nil, are the only COB-IDs accepted by Filter. They are
+	// used to set the filters of a FilterTransport
+	CobIDs []uint32
 }
 
-func (networkFramesCh *NetworkFramesChan) Publish(frm *can.Frame) {
-	if networkFramesCh.Filter != nil {
-		if (*networkFramesCh.Filter)(frm) {
-			select {
-			case networkFramesCh.C <- frm:
-			default:
-			}
-		}
+// FilterTransport is a can.Transport able to filter received frames, eg: with
+// kernel filters. The network set filters to the COB-IDs of its frames chans,
+// nil cobIDs meaning all frames
+type FilterTransport interface {
+	can.Transport
+	SetFilters(cobIDs []uint32) error
+}
 
-		return
+// Publish send frm to C if accepted by Filter, without blocking. It return false
+// if frm was dropped because C was not ready
+func (networkFramesCh *NetworkFramesChan) Publish(frm *can.Frame) bool {
+	if networkFramesCh.Filter != nil && !(*networkFramesCh.Filter)(frm) {
+		return true
 	}
 
 	select {
 	case networkFramesCh.C <- frm:
+		return true
 	default:
+		return false
 	}
 }
@@ -0,0 +1,151 @@
+package canopen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/angelodlfrtr/go-can"
+)
+
+// fakeFilterTransport record filters set by the network
+type fakeFilterTransport struct {
+	*fakeTransport
+
+	filters []uint32
+	updates int
+	err     error
+}
+
+func (t *fakeFilterTransport) SetFilters(cobIDs []uint32) error {
+	t.Lock()
+	defer t.Unlock()
+
+	t.updates++
+	if t.err != nil {
+		return t.err
+	}
+
+	t.filters = cobIDs
+	return nil
+}
+
+func (t *fakeFilterTransport) Updates() int {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.updates
+}
+
+func (t *fakeFilterTransport) SetError(err error) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.err = err
+}
+
+func (t *fakeFilterTransport) Filters() string {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.filters == nil {
+		return "all"
+	}
+
+	return fmt.Sprintf("%X", t.filters)
+}
+
+func TestNetworkTransportFilters(t *testing.T) {
+	transport := &fakeFilterTransport{fakeTransport: newFakeTransport()}
+	network, err := NewNetwork(can.Bus{Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := network.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer network.Stop()
+
+	// NMT master heartbeat listener
+	if filters := transport.Filters(); filters != "[700]" {
+		t.Errorf("unexpected filters %s", filters)
+	}
+
+	sdoChan := network.AcquireCobIDsFramesChan(nil, 0x585)
+	if filters := transport.Filters(); filters != "[585 700]" {
+		t.Errorf("unexpected filters %s", filters)
+	}
+
+	allChan := network.AcquireFramesChan(nil)
+	if filters := transport.Filters(); filters != "all" {
+		t.Errorf("unexpected filters %s", filters)
+	}
+
+	network.ReleaseFramesChan(allChan.ID)
+	if filters := transport.Filters(); filters != "[585 700]" {
+		t.Errorf("unexpected filters %s", filters)
+	}
+
+	// Released COB-IDs are kept, so repeated transfers don't update filters
+	network.ReleaseFramesChan(sdoChan.ID)
+	updates := transport.Updates()
+
+	for i := 0; i < 3; i++ {
+		network.ReleaseFramesChan(network.AcquireCobIDsFramesChan(nil, 0x585).ID)
+	}
+
+	if filters := transport.Filters(); filters != "[585 700]" || transport.Updates() != updates {
+		t.Errorf("unexpected filters %s after %d updates", filters, transport.Updates()-updates)
+	}
+
+	// Filters are updated with the current chans COB-IDs
+	otherChan := network.AcquireCobIDsFramesChan(nil, 0x586)
+	if filters := transport.Filters(); filters != "[586 700]" {
+		t.Errorf("unexpected filters %s", filters)
+	}
+	network.ReleaseFramesChan(otherChan.ID)
+
+	// COB-IDs chans receive their COB-IDs only
+	filter := func(frm *can.Frame) bool { return frm.Data[0] == 0x43 }
+	framesChan := network.AcquireCobIDsFramesChan(&filter, 0x585)
+	defer network.ReleaseFramesChan(framesChan.ID)
+
+	transport.Inject(0x586, []byte{0x43})
+	transport.Inject(0x585, []byte{0x60})
+	transport.Inject(0x585, []byte{0x43, 0x01})
+
+	if frm := <-framesChan.C; frm.ArbitrationID != 0x585 || frm.Data[1] != 0x01 {
+		t.Errorf("unexpected frame %v", frm)
+	}
+}
+
+func TestNetworkTransportFiltersError(t *testing.T) {
+	transport := &fakeFilterTransport{fakeTransport: newFakeTransport()}
+	network, err := NewNetwork(can.Bus{Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logger := &testLogger{}
+	network.Logger = logger
+	transport.SetError(errors.New("setsockopt failed"))
+
+	framesChan := network.AcquireCobIDsFramesChan(nil, 0x585)
+	defer network.ReleaseFramesChan(framesChan.ID)
+
+	if logs := logger.Logs(); len(logs) != 1 || !strings.HasPrefix(logs[0], "ERROR transport filters update failed") {
+		t.Fatalf("unexpected logs %v", logs)
+	}
+
+	// Filters are set again at the next acquire
+	transport.SetError(nil)
+	otherChan := network.AcquireCobIDsFramesChan(nil, 0x585)
+	defer network.ReleaseFramesChan(otherChan.ID)
+
+	if filters := transport.Filters(); filters != "[585]" {
+		t.Errorf("unexpected filters %s", filters)
+	}
+}
@@ -1,6 +1,7 @@
 package canopen
 
 import (
+	"bytes"
 	"fmt"
 	"log"
 	"os"
@@ -92,7 +93,9 @@ func TestSearch(t *testing.T) {
 func TestAddNode(t *testing.T) {
 	network := &Network{}
 	node := &Node{ID: 1}
-	network.AddNode(node, nil, false)
+	if _, err := network.AddNode(node, nil, false); err != nil {
+		t.Fatal(err)
+	}
 
 	if len(network.Nodes) != 1 {
 		t.Fatal("Invalid network.Nodes len")
@@ -102,7 +105,9 @@ func TestAddNode(t *testing.T) {
 func TestGetNode(t *testing.T) {
 	network := &Network{}
 	node := &Node{ID: 1}
-	network.AddNode(node, nil, false)
+	if _, err := network.AddNode(node, nil, false); err != nil {
+		t.Fatal(err)
+	}
 
 	if len(network.Nodes) != 1 {
 		t.Fatal("Invalid network.Nodes len")
@@ -118,6 +123,42 @@ func TestGetNode(t *testing.T) {
 	}
 }
 
+func TestAddNodeSharedObjectDic(t *testing.T) {
+	network := &Network{}
+	dic, err := DicEDSParse([]byte("[1014]\nParameterName=COB-ID EMCY\nObjectType=0x7\nDataType=0x0007\nAccessType=rw\nDefaultValue=$NODEID+0x80\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node1, err := network.AddNode(NewNode(1, nil, nil), dic, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node2, err := network.AddNode(NewNode(2, nil, nil), dic, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if node1.ObjectDic == node2.ObjectDic || node1.ObjectDic == dic {
+		t.Fatal("nodes must have their own object dictionary")
+	}
+
+	for _, tt := range []struct {
+		objectDic *DicObjectDic
+		want      []byte
+	}{
+		{node1.ObjectDic, []byte{0x81, 0x00, 0x00, 0x00}},
+		{node2.ObjectDic, []byte{0x82, 0x00, 0x00, 0x00}},
+		{dic, []byte{0x80, 0x00, 0x00, 0x00}},
+	} {
+		variable := tt.objectDic.FindVariable(0x1014, 0)
+		if !bytes.Equal(variable.Default, tt.want) {
+			t.Fatalf("invalid COB-ID EMCY default % X, expected % X", variable.Default, tt.want)
+		}
+	}
+}
+
 func TestAll(t *testing.T) {
 	testPort := getTestPort()
 	transport := &transports.USBCanAnalyzer{
@@ -168,7 +209,9 @@ func TestAll(t *testing.T) {
 			// Parse eds file
 			dic := DicMustParse(DicEDSParse(objectDicFilePath))
 
-			network.AddNode(node, dic, false)
+			if _, err := network.AddNode(node, dic, false); err != nil {
+				log.Fatal(err)
+			}
 
 			fmt.Println("Reading PDO")
 
@@ -248,7 +291,9 @@ func TestReboot(t *testing.T) {
 			// Parse eds file
 			dic := DicMustParse(DicEDSParse(objectDicFilePath))
 
-			network.AddNode(node, dic, false)
+			if _, err := network.AddNode(node, dic, false); err != nil {
+				log.Fatal(err)
+			}
 
 			fmt.Println("Reading PDO")
 
@@ -2,6 +2,7 @@ package canopen
 
 import (
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/angelodlfrtr/go-can"
@@ -38,6 +39,9 @@ var NMTCommandToState = map[int]int{
 }
 
 type NMTMaster struct {
+	// mutex for heartbeat state access
+	sync.Mutex
+
 	NodeID        int
 	Network       *Network
 	State         int
@@ -94,51 +98,74 @@ func (master *NMTMaster) ListenForHeartbeat() error {
 
 	master.Listening = true
 
-	// Hearbeat message arbID
-	eventName := 0x700 + master.NodeID
-
-	// Filter func for messages on network
-	filterFunc := func(frm *can.Frame) bool {
-		return frm.ArbitrationID == uint32(eventName)
-	}
-
-	// Get frames chan
-	framesChan := master.Network.AcquireFramesChan(&filterFunc)
+	// Get frames chan of hearbeat messages
+	framesChan := master.Network.AcquireCobIDsFramesChan(nil, uint32(0x700+master.NodeID))
 	master.networkFramesChanID = &framesChan.ID
 
 	// Listen for messages
 	go func() {
-		select {
-		case <-master.stopChan:
-			// Stop goroutine
-			return
-		case frm := <-framesChan.C:
-			master.handleHeartbeatFrame(frm)
+		for {
+			select {
+			case <-master.stopChan:
+				// Stop goroutine
+				return
+			case frm, ok := <-framesChan.C:
+				// Chan is closed by UnlistenForHeartbeat
+				if !ok {
+					return
+				}
+
+				master.handleHeartbeatFrame(frm)
+			}
 		}
 	}()
 
 	return nil
 }
 
+// LastHeartbeat return the node state name and time of the last heartbeat
+// received, or false if none was received
+func (master *NMTMaster) LastHeartbeat() (string, time.Time, bool) {
+	master.Lock()
+	defer master.Unlock()
+
+	if master.Timestamp == nil {
+		return "", time.Time{}, false
+	}
+
+	return NMTStates[master.State], *master.Timestamp, true
+}
+
 func (master *NMTMaster) handleHeartbeatFrame(frm *can.Frame) {
+	master.Lock()
+	defer master.Unlock()
+
 	now := time.Now()
 	master.Timestamp = &now
 
 	newState := int(frm.Data[0])
 	master.StateReceived = &newState
 
+	oldState := master.State
 	if newState == 0 {
 		master.State = 127
 	} else {
 		master.State = newState
 	}
 
+	if master.State != oldState || newState == 0 {
+		master.Network.trace(master.NodeID, "NMT state changed",
+			"from", NMTStates[oldState], "to", NMTStates[master.State], "bootUp", newState == 0)
+	}
+
 	// @TODO: emit state
 }
 
 // SendCommand to target node
 func (master *NMTMaster) SendCommand(code int) error {
 	data := []byte{uint8(code), uint8(master.NodeID)}
+	master.Network.trace(master.NodeID, "NMT command", "command", monitorNMTCommands[data[0]])
+
 	return master.Network.Send(0, data)
 }
 
@@ -149,13 +176,19 @@ func (master *NMTMaster) SetState(cmd string) error {
 	}
 
 	code := NMTCommands[cmd]
+
+	master.Lock()
 	master.StateReceived = nil
+	master.Unlock()
 
 	return master.SendCommand(code)
 }
 
 // GetStateString for target node
 func (master *NMTMaster) GetStateString() string {
+	master.Lock()
+	defer master.Unlock()
+
 	if s, ok := NMTStates[master.State]; ok {
 		return s
 	}
@@ -178,10 +211,12 @@ func (master *NMTMaster) WaitForBootup(timeout *time.Duration) error {
 			return errors.New("timeout execeded")
 		}
 
-		if master.StateReceived != nil {
-			if *master.StateReceived == 5 {
-				break
-			}
+		master.Lock()
+		booted := master.StateReceived != nil && *master.StateReceived == 5
+		master.Unlock()
+
+		if booted {
+			break
 		}
 
 		time.Sleep(time.Millisecond * 100)
@@ -11,6 +11,10 @@ type Node struct {
 	SDOClient *SDOClient
 	PDONode   *PDONode
 	NMTMaster *NMTMaster
+
+	// ConfigFilter select variables saved and restored by BackupConfiguration and
+	// RestoreConfiguration, IsNodeConfigVariable if nil
+	ConfigFilter func(variable *DicVariable) bool
 }
 
 func NewNode(id int, network *Network, objectDic *DicObjectDic) *Node {
@@ -33,6 +37,25 @@ func (node *Node) SetObjectDic(objectDic *DicObjectDic) {
 	node.ObjectDic = objectDic
 }
 
+// logger return the Logger of node network
+func (node *Node) logger() Logger {
+	return node.Network.logger()
+}
+
+// trace log a debug message about node, if its tracing is enabled, see Network.SetTrace
+func (node *Node) trace(msg string, args ...interface{}) {
+	node.Network.trace(node.ID, msg, args...)
+}
+
+// sdo return node SDOClient, or a new one if node is not initialized
+func (node *Node) sdo() *SDOClient {
+	if node.SDOClient != nil {
+		return node.SDOClient
+	}
+
+	return NewSDOClient(node)
+}
+
 // Init create sdo clients, pdo nodes, nmt master
 func (node *Node) Init() {
 	node.SDOClient = NewSDOClient(node)
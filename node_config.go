@@ -0,0 +1,394 @@
+package canopen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// NodeStoreIndex is the store parameters object
+	NodeStoreIndex uint16 = 0x1010
+	// NodeRestoreIndex is the restore default parameters object
+	NodeRestoreIndex uint16 = 0x1011
+)
+
+// NodeParameterGroup is a group of parameters, sub index of NodeStoreIndex and NodeRestoreIndex
+type NodeParameterGroup uint8
+
+// Parameter groups, manufacturer specific groups start at ParametersManufacturer
+const (
+	ParametersAll           NodeParameterGroup = 0x01
+	ParametersCommunication NodeParameterGroup = 0x02
+	ParametersApplication   NodeParameterGroup = 0x03
+	ParametersManufacturer  NodeParameterGroup = 0x04
+)
+
+// Signatures written to NodeStoreIndex and NodeRestoreIndex, "save" and "load" in ASCII
+var (
+	nodeStoreSignature   = []byte("save")
+	nodeRestoreSignature = []byte("load")
+)
+
+// StoreParameters save node parameters of group in non volatile memory
+func (node *Node) StoreParameters(group NodeParameterGroup) error {
+	return node.sdo().Write(NodeStoreIndex, uint8(group), false, nodeStoreSignature)
+}
+
+// RestoreDefaultParameters load default values of node parameters of group. The
+// defaults are used after the next node reset
+func (node *Node) RestoreDefaultParameters(group NodeParameterGroup) error {
+	return node.sdo().Write(NodeRestoreIndex, uint8(group), false, nodeRestoreSignature)
+}
+
+// isNodeConfigIndex return false for objects which are commands or status, and
+// must not be saved or restored as configuration
+func isNodeConfigIndex(index uint16) bool {
+	switch {
+	case index == 0x1003, index == NodeStoreIndex, index == NodeRestoreIndex:
+		return false
+	case index >= 0x1F50 && index <= 0x1F5F:
+		// Program download
+		return false
+	}
+
+	return true
+}
+
+// IsNodeConfigVariable is the default Node.ConfigFilter. It select readable and
+// writable variables, except commands, status, domains, PDO mappable objects and
+// process data of standardized profiles (0x6000 to 0x9FFF)
+func IsNodeConfigVariable(variable *DicVariable) bool {
+	switch {
+	case !isNodeConfigIndex(variable.Index), variable.DataType == Domain:
+		return false
+	case variable.PDOMapping:
+		return false
+	case variable.Index >= 0x6000 && variable.Index <= 0x9FFF:
+		return false
+	}
+
+	return variable.IsReadable() && variable.IsWritable()
+}
+
+// configFilter return node.ConfigFilter, or IsNodeConfigVariable if not set
+func (node *Node) configFilter() func(variable *DicVariable) bool {
+	if node.ConfigFilter != nil {
+		return node.ConfigFilter
+	}
+
+	return IsNodeConfigVariable
+}
+
+// nodeConfigVariables return objectDic variables to save or restore
+func nodeConfigVariables(objectDic *DicObjectDic, filter func(variable *DicVariable) bool) []*DicVariable {
+	variables := []*DicVariable{}
+
+	for _, variable := range objectDic.Variables() {
+		if filter(variable) {
+			variables = append(variables, variable)
+		}
+	}
+
+	return variables
+}
+
+// BackupConfiguration read every object of node object dictionary selected by
+// node.ConfigFilter with SDO, and return them in a copy of the dictionary, node.ObjectDic
+// being left unchanged. Use DicDCFExport to save it as a DCF.
+// Objects aborting the read are left without value, and are not restored
+func (node *Node) BackupConfiguration() (*DicObjectDic, error) {
+	if node.ObjectDic == nil {
+		return nil, fmt.Errorf("node %d has no object dictionary", node.ID)
+	}
+
+	sdoClient := node.sdo()
+	backup := node.ObjectDic.Copy()
+	backup.NodeID = node.ID
+
+	for _, variable := range nodeConfigVariables(backup, node.configFilter()) {
+		data, err := sdoClient.Read(variable.Index, variable.SubIndex)
+		if err != nil {
+			var abortErr *SDOAbortError
+			if !errors.As(err, &abortErr) {
+				return nil, err
+			}
+
+			variable.Data = nil
+			continue
+		}
+
+		variable.Data = data
+	}
+
+	return backup, nil
+}
+
+// ConfigurationChange is an object written by RestoreConfiguration
+type ConfigurationChange struct {
+	Index    uint16
+	SubIndex uint8
+	Name     string
+
+	// Old is nil if the object could not be read before writing
+	Old []byte
+	New []byte
+
+	Err error
+}
+
+func (change ConfigurationChange) String() string {
+	s := fmt.Sprintf("0x%04X sub %d %s: %X -> %X", change.Index, change.SubIndex, change.Name, change.Old, change.New)
+	if change.Err != nil {
+		s += fmt.Sprintf(" failed: %v", change.Err)
+	}
+
+	return s
+}
+
+// ConfigurationReport list objects written by RestoreConfiguration
+type ConfigurationReport struct {
+	Changed   []ConfigurationChange
+	Failed    []ConfigurationChange
+	Unchanged int
+}
+
+func (report *ConfigurationReport) String() string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%d changed, %d failed, %d unchanged\n", len(report.Changed), len(report.Failed), report.Unchanged)
+
+	for _, change := range report.Changed {
+		fmt.Fprintf(b, "  %s\n", change)
+	}
+
+	for _, change := range report.Failed {
+		fmt.Fprintf(b, "  %s\n", change)
+	}
+
+	return b.String()
+}
+
+// configRestorer write configuration objects and fill a report
+type configRestorer struct {
+	sdoClient *SDOClient
+	report    *ConfigurationReport
+}
+
+// read current value of variable, nil on SDO abort
+func (r *configRestorer) read(variable *DicVariable) ([]byte, error) {
+	data, err := r.sdoClient.Read(variable.Index, variable.SubIndex)
+	if err != nil {
+		var abortErr *SDOAbortError
+		if errors.As(err, &abortErr) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// write data to variable and report the change
+func (r *configRestorer) write(variable *DicVariable, old, data []byte) error {
+	if err := r.sdoClient.Write(variable.Index, variable.SubIndex, false, data); err != nil {
+		return r.fail(variable, old, data, err)
+	}
+
+	r.report.Changed = append(r.report.Changed, ConfigurationChange{
+		Index:    variable.Index,
+		SubIndex: variable.SubIndex,
+		Name:     variable.Name,
+		Old:      old,
+		New:      data,
+	})
+
+	return nil
+}
+
+// fail report a failed write if err is an SDO abort, or return err
+func (r *configRestorer) fail(variable *DicVariable, old, data []byte, err error) error {
+	var abortErr *SDOAbortError
+	if !errors.As(err, &abortErr) {
+		return err
+	}
+
+	r.report.Failed = append(r.report.Failed, ConfigurationChange{
+		Index:    variable.Index,
+		SubIndex: variable.SubIndex,
+		Name:     variable.Name,
+		Old:      old,
+		New:      data,
+		Err:      err,
+	})
+
+	return nil
+}
+
+// restore write variable if its value differ from the node one
+func (r *configRestorer) restore(variable *DicVariable) error {
+	old, err := r.read(variable)
+	if err != nil {
+		return err
+	}
+
+	if old != nil && bytes.Equal(old, variable.Data) {
+		r.report.Unchanged++
+		return nil
+	}
+
+	return r.write(variable, old, variable.Data)
+}
+
+// restorePDO restore a PDO communication and mapping parameters. The PDO is
+// disabled while its parameters are written, and its mapping count is written last
+func (r *configRestorer) restorePDO(com, mapping []*DicVariable) error {
+	olds := map[*DicVariable][]byte{}
+	changed := false
+
+	for _, variable := range append(append([]*DicVariable{}, com...), mapping...) {
+		old, err := r.read(variable)
+		if err != nil {
+			return err
+		}
+
+		olds[variable] = old
+		if old == nil || !bytes.Equal(old, variable.Data) {
+			changed = true
+		}
+	}
+
+	if !changed {
+		r.report.Unchanged += len(olds)
+		return nil
+	}
+
+	var cobID, mapCount *DicVariable
+
+	for _, variable := range com {
+		if variable.SubIndex == 1 && len(variable.Data) == 4 {
+			cobID = variable
+		}
+	}
+
+	for _, variable := range mapping {
+		if variable.SubIndex == 0 {
+			mapCount = variable
+		}
+	}
+
+	// Disable the PDO. Its other parameters are not written if it fails
+	if old := olds[cobID]; cobID != nil && len(old) == 4 {
+		disabled := make([]byte, 4)
+		binary.LittleEndian.PutUint32(disabled, binary.LittleEndian.Uint32(old)|uint32(MapPDONotValid))
+
+		if err := r.sdoClient.Write(cobID.Index, cobID.SubIndex, false, disabled); err != nil {
+			return r.fail(cobID, old, disabled, err)
+		}
+	}
+
+	// Mapping entries can be written only when the mapping count is 0
+	if mapCount != nil && len(mapCount.Data) == 1 {
+		if err := r.sdoClient.Write(mapCount.Index, 0, false, []byte{0}); err != nil {
+			return r.fail(mapCount, olds[mapCount], []byte{0}, err)
+		}
+	}
+
+	for _, variable := range append(append([]*DicVariable{}, mapping...), com...) {
+		if variable == cobID || variable == mapCount {
+			continue
+		}
+
+		if old := olds[variable]; old != nil && bytes.Equal(old, variable.Data) {
+			r.report.Unchanged++
+			continue
+		}
+
+		if err := r.write(variable, olds[variable], variable.Data); err != nil {
+			return err
+		}
+	}
+
+	// Written again even if unchanged, to restore the mapping and enable the PDO
+	for _, variable := range []*DicVariable{mapCount, cobID} {
+		if variable == nil {
+			continue
+		}
+
+		if old := olds[variable]; old != nil && bytes.Equal(old, variable.Data) {
+			if err := r.sdoClient.Write(variable.Index, variable.SubIndex, false, variable.Data); err != nil {
+				return r.fail(variable, old, variable.Data, err)
+			}
+
+			r.report.Unchanged++
+			continue
+		}
+
+		if err := r.write(variable, olds[variable], variable.Data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isPDOConfigIndex return true for PDO communication and mapping parameters
+func isPDOConfigIndex(index uint16) bool {
+	return index >= 0x1400 && index <= 0x1BFF
+}
+
+// RestoreConfiguration write ParameterValue of every object of dcf selected by
+// node.ConfigFilter which differ from node values, and return a report of written objects.
+//
+// Communication objects are written first, then application objects, and PDO
+// parameters last. Each PDO is disabled while it is configured. Objects aborting
+// the write are reported in Failed, other errors stop the restore.
+// Use StoreParameters to keep the restored configuration after a reset
+func (node *Node) RestoreConfiguration(dcf *DicObjectDic) (*ConfigurationReport, error) {
+	r := &configRestorer{sdoClient: node.sdo(), report: &ConfigurationReport{}}
+
+	pdoCom := map[uint16][]*DicVariable{}
+	pdoMapping := map[uint16][]*DicVariable{}
+	pdos := []uint16{}
+
+	for _, variable := range nodeConfigVariables(dcf, node.configFilter()) {
+		if len(variable.Data) == 0 {
+			continue
+		}
+
+		if !isPDOConfigIndex(variable.Index) {
+			if err := r.restore(variable); err != nil {
+				return r.report, err
+			}
+
+			continue
+		}
+
+		// Group parameters by communication index, mapping index is 0x200 above
+		com := variable.Index
+		if (com & 0x0200) != 0 {
+			com -= 0x0200
+			pdoMapping[com] = append(pdoMapping[com], variable)
+		} else {
+			pdoCom[com] = append(pdoCom[com], variable)
+		}
+
+		if len(pdoCom[com])+len(pdoMapping[com]) == 1 {
+			pdos = append(pdos, com)
+		}
+	}
+
+	for _, com := range pdos {
+		if err := r.restorePDO(pdoCom[com], pdoMapping[com]); err != nil {
+			return r.report, err
+		}
+	}
+
+	if len(r.report.Failed) > 0 {
+		return r.report, fmt.Errorf("node %d: %d objects could not be restored", node.ID, len(r.report.Failed))
+	}
+
+	return r.report, nil
+}
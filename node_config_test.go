@@ -0,0 +1,233 @@
+package canopen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testConfigDCF = `[DeviceComissioning]
+NodeId=0x0A
+
+[1017]
+ParameterName=Producer heartbeat time
+ObjectType=0x7
+DataType=0x0006
+AccessType=rw
+ParameterValue=1000
+
+[1800]
+ParameterName=TPDO 1 communication parameter
+ObjectType=0x9
+SubNumber=0x3
+
+[1800sub0]
+ParameterName=Highest sub-index supported
+ObjectType=0x7
+DataType=0x0005
+AccessType=ro
+ParameterValue=2
+
+[1800sub1]
+ParameterName=COB-ID
+ObjectType=0x7
+DataType=0x0007
+AccessType=rw
+ParameterValue=$NODEID+0x180
+
+[1800sub2]
+ParameterName=Transmission type
+ObjectType=0x7
+DataType=0x0005
+AccessType=rw
+ParameterValue=0xFF
+
+[1A00]
+ParameterName=TPDO 1 mapping parameter
+ObjectType=0x9
+SubNumber=0x2
+
+[1A00sub0]
+ParameterName=Number of mapped objects
+ObjectType=0x7
+DataType=0x0005
+AccessType=rw
+ParameterValue=1
+
+[1A00sub1]
+ParameterName=Mapped object 1
+ObjectType=0x7
+DataType=0x0007
+AccessType=rw
+ParameterValue=0x20000010
+
+[2000]
+ParameterName=Setpoint
+ObjectType=0x7
+DataType=0x0006
+AccessType=rw
+ParameterValue=42
+
+[3000]
+ParameterName=Output
+ObjectType=0x7
+DataType=0x0006
+AccessType=rw
+PDOMapping=1
+ParameterValue=1
+
+[6040]
+ParameterName=Controlword
+ObjectType=0x7
+DataType=0x0006
+AccessType=rw
+ParameterValue=0x000F
+`
+
+func TestNodeStoreParameters(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 10)
+	node := NewNode(10, network, nil)
+
+	if err := node.StoreParameters(ParametersAll); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := node.RestoreDefaultParameters(ParametersApplication); err != nil {
+		t.Fatal(err)
+	}
+
+	if data := server.Get(NodeStoreIndex, 1); string(data) != "save" {
+		t.Errorf("unexpected store signature %q", data)
+	}
+
+	if data := server.Get(NodeRestoreIndex, 3); string(data) != "load" {
+		t.Errorf("unexpected restore signature %q", data)
+	}
+}
+
+func TestNodeRestoreConfiguration(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 10)
+	server.Set(0x1017, 0, []byte{0xE8, 0x03})
+	server.Set(0x1800, 1, []byte{0x8A, 0x01, 0x00, 0x00})
+	server.Set(0x1800, 2, []byte{0x01})
+	server.Set(0x1A00, 0, []byte{0x00})
+	server.Set(0x1A00, 1, []byte{0x00, 0x00, 0x00, 0x00})
+	server.Set(0x2000, 0, []byte{0x00, 0x00})
+
+	dcf, err := DicEDSParse([]byte(testConfigDCF))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := NewNode(10, network, nil)
+	report, err := node.RestoreConfiguration(dcf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Changed) != 4 || report.Unchanged != 2 || len(report.Failed) != 0 {
+		t.Fatalf("unexpected report %s", report)
+	}
+
+	if !strings.Contains(report.String(), "0x2000 sub 0 Setpoint: 0000 -> 2A00") {
+		t.Errorf("unexpected report %s", report)
+	}
+
+	// Application objects first, then PDO disabled, mapped, and enabled again
+	writes := []string{}
+	for _, frm := range transport.Written() {
+		if frm.Data[0]&0xE0 == SDORequestDownload {
+			writes = append(writes, fmt.Sprintf("%04Xsub%d", binary.LittleEndian.Uint16(frm.Data[1:]), frm.Data[3]))
+		}
+	}
+
+	expected := []string{"2000sub0", "1800sub1", "1A00sub0", "1A00sub1", "1800sub2", "1A00sub0", "1800sub1"}
+	if strings.Join(writes, ",") != strings.Join(expected, ",") {
+		t.Errorf("unexpected writes order %v", writes)
+	}
+
+	if data := server.Get(0x1800, 1); !bytes.Equal(data, []byte{0x8A, 0x01, 0x00, 0x00}) {
+		t.Errorf("PDO not enabled again: %X", data)
+	}
+
+	if data := server.Get(0x1A00, 0); !bytes.Equal(data, []byte{0x01}) {
+		t.Errorf("unexpected mapping count %X", data)
+	}
+
+	// Process data and commands are not restored
+	if server.Get(0x3000, 0) != nil || server.Get(0x6040, 0) != nil {
+		t.Error("process data should not be restored")
+	}
+}
+
+func TestNodeConfigFilter(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 10)
+	server.Set(0x6040, 0, []byte{0x00, 0x00})
+
+	dcf, err := DicEDSParse([]byte(testConfigDCF))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := NewNode(10, network, nil)
+	node.ConfigFilter = func(variable *DicVariable) bool {
+		return IsNodeConfigVariable(variable) || variable.Index == 0x6040
+	}
+
+	report, err := node.RestoreConfiguration(dcf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data := server.Get(0x6040, 0); !bytes.Equal(data, []byte{0x0F, 0x00}) {
+		t.Errorf("unexpected controlword %X, report %s", data, report)
+	}
+
+	if server.Get(0x3000, 0) != nil {
+		t.Error("PDO mappable object should not be restored")
+	}
+}
+
+func TestNodeBackupConfiguration(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 10)
+	server.Set(0x2000, 0, []byte{0x07, 0x00})
+
+	dcf, err := DicEDSParse([]byte(testConfigDCF))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := NewNode(10, network, dcf)
+	objectDic, err := node.BackupConfiguration()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data := objectDic.FindVariable(0x2000, 0).Data; !bytes.Equal(data, []byte{0x07, 0x00}) {
+		t.Errorf("unexpected backup value %X", data)
+	}
+
+	// Missing objects are not backed up
+	if data := objectDic.FindVariable(0x1017, 0).Data; data != nil {
+		t.Errorf("unexpected backup value %X", data)
+	}
+
+	// Node dictionary is left unchanged
+	if objectDic == dcf {
+		t.Fatal("backup should be a copy of the node dictionary")
+	}
+
+	if data := dcf.FindVariable(0x2000, 0).Data; bytes.Equal(data, []byte{0x07, 0x00}) {
+		t.Errorf("node dictionary value should not be changed, got %X", data)
+	}
+
+	if data := dcf.FindVariable(0x1017, 0).Data; data == nil {
+		t.Error("node dictionary value should not be wiped by a failed read")
+	}
+}
@@ -0,0 +1,131 @@
+package canopen
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// NodeEDSIndex is the object storing the device EDS
+	NodeEDSIndex uint16 = 0x1021
+	// NodeEDSFormatIndex is the object giving the NodeEDSIndex storage format
+	NodeEDSFormatIndex uint16 = 0x1022
+)
+
+// EDS storage formats of NodeEDSFormatIndex
+const (
+	EDSStorageASCII byte = 0x00
+	EDSStorageZip   byte = 0x01
+)
+
+// NoEDSError is returned when a node does not provide its EDS
+type NoEDSError struct {
+	NodeID int
+	Err    error
+}
+
+func (e *NoEDSError) Error() string {
+	return fmt.Sprintf("node %d does not provide an EDS: %v", e.NodeID, e.Err)
+}
+
+func (e *NoEDSError) Unwrap() error {
+	return e.Err
+}
+
+// isSDOAbortNotFound return true if err is an abort for a missing object or data
+func isSDOAbortNotFound(err error) bool {
+	var abortErr *SDOAbortError
+	if !errors.As(err, &abortErr) {
+		return false
+	}
+
+	switch abortErr.Code {
+	case SDOAbortObjectNotExist, SDOAbortSubIndexNotExist, SDOAbortNoData:
+		return true
+	}
+
+	return false
+}
+
+// UploadEDS read node EDS from objects 0x1021 and 0x1022 using SDO, and parse it.
+// A NoEDSError is returned if node does not provide its EDS
+func (node *Node) UploadEDS() (*DicObjectDic, error) {
+	sdoClient := node.sdo()
+
+	data, err := sdoClient.Read(NodeEDSIndex, 0)
+	if err != nil {
+		if isSDOAbortNotFound(err) {
+			return nil, &NoEDSError{NodeID: node.ID, Err: err}
+		}
+
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, &NoEDSError{NodeID: node.ID, Err: errors.New("empty EDS")}
+	}
+
+	// Storage format object is optional, default to ASCII
+	format := EDSStorageASCII
+	formatData, err := sdoClient.Read(NodeEDSFormatIndex, 0)
+	if err != nil && !isSDOAbortNotFound(err) {
+		return nil, err
+	}
+
+	if len(formatData) > 0 {
+		format = formatData[0]
+	}
+
+	switch format {
+	case EDSStorageASCII:
+	case EDSStorageZip:
+		if data, err = unzipEDS(data); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("node %d: unsupported EDS storage format 0x%02X", node.ID, format)
+	}
+
+	// Strings can be NUL terminated
+	data = bytes.TrimRight(data, "\x00")
+
+	objectDic, err := DicEDSParse(data)
+	if err != nil {
+		return nil, fmt.Errorf("node %d: invalid uploaded EDS: %v", node.ID, err)
+	}
+
+	return objectDic, nil
+}
+
+// unzipEDS return the EDS or DCF file of a zip archive, or its first file
+func unzipEDS(data []byte) ([]byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EDS archive: %v", err)
+	}
+
+	if len(archive.File) == 0 {
+		return nil, errors.New("empty EDS archive")
+	}
+
+	file := archive.File[0]
+	for _, f := range archive.File {
+		if ext := strings.ToLower(filepath.Ext(f.Name)); ext == ".eds" || ext == ".dcf" {
+			file = f
+			break
+		}
+	}
+
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
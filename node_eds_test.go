@@ -0,0 +1,84 @@
+package canopen
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+)
+
+const testUploadedEDS = "[1000]\r\nParameterName=Device type\r\nObjectType=0x7\r\nDataType=0x0007\r\nAccessType=ro\r\nDefaultValue=0x00000191\r\n"
+
+func TestNetworkAddNodeUploadEDS(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 6)
+	server.Set(NodeEDSIndex, 0, append([]byte(testUploadedEDS), 0x00))
+
+	node, err := network.AddNode(NewNode(6, nil, nil), nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if node.ObjectDic == nil || node.ObjectDic.FindIndex(0x1000) == nil {
+		t.Fatal("uploaded EDS not parsed")
+	}
+}
+
+func TestNodeUploadEDSZip(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 7)
+
+	buf := &bytes.Buffer{}
+	archive := zip.NewWriter(buf)
+	w, err := archive.Create("device.eds")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := w.Write([]byte(testUploadedEDS)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	server.Set(NodeEDSIndex, 0, buf.Bytes())
+	server.Set(NodeEDSFormatIndex, 0, []byte{EDSStorageZip})
+
+	node := NewNode(7, network, nil)
+	objectDic, err := node.UploadEDS()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if objectDic.FindIndex(0x1000) == nil {
+		t.Fatal("uploaded EDS not parsed")
+	}
+}
+
+func TestNodeUploadEDSMissing(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	newFakeSDOServer(transport, 8)
+
+	node := NewNode(8, nil, nil)
+	_, err := network.AddNode(node, nil, true)
+
+	var noEDSErr *NoEDSError
+	if !errors.As(err, &noEDSErr) || noEDSErr.NodeID != 8 {
+		t.Fatalf("expected NoEDSError, got %v", err)
+	}
+
+	var abortErr *SDOAbortError
+	if !errors.As(err, &abortErr) || abortErr.Code != SDOAbortObjectNotExist || abortErr.Index != NodeEDSIndex {
+		t.Fatalf("expected SDOAbortError, got %v", err)
+	}
+
+	if node.Network != nil || node.SDOClient != nil {
+		t.Fatal("node must not be attached to network after a failed upload")
+	}
+
+	if _, err := network.GetNode(8); err == nil {
+		t.Fatal("node must not be added to network after a failed upload")
+	}
+}
@@ -0,0 +1,55 @@
+package canopen
+
+import "fmt"
+
+// PDOField is a variable mapped in a PDO at Offset bits, used by generated bindings
+type PDOField struct {
+	Offset   int
+	DataType byte
+}
+
+// bounds return field start and end bytes in data
+func (field PDOField) bounds(data []byte) (int, int, error) {
+	size := DataTypeSize(field.DataType)
+	if size == 0 || field.Offset%8 != 0 {
+		return 0, 0, fmt.Errorf("unsupported PDO field, data type 0x%X at bit %d", field.DataType, field.Offset)
+	}
+
+	start := field.Offset / 8
+	end := start + size
+
+	if end > len(data) {
+		return 0, 0, fmt.Errorf("PDO data too short for field at bit %d: %d bytes", field.Offset, len(data))
+	}
+
+	return start, end, nil
+}
+
+// PDOFieldValue decode field value from PDO data
+func PDOFieldValue[T DicValueType](data []byte, field PDOField) (T, error) {
+	var r T
+
+	start, end, err := field.bounds(data)
+	if err != nil {
+		return r, err
+	}
+
+	return Value[T](&DicVariable{DataType: field.DataType, Data: data[start:end]})
+}
+
+// PDOSetFieldValue encode v as field in PDO data
+func PDOSetFieldValue[T DicValueType](data []byte, field PDOField, v T) error {
+	start, end, err := field.bounds(data)
+	if err != nil {
+		return err
+	}
+
+	variable := &DicVariable{DataType: field.DataType}
+	if err := SetValue(variable, v); err != nil {
+		return err
+	}
+
+	copy(data[start:end], variable.Data)
+
+	return nil
+}
@@ -2,11 +2,11 @@ package canopen
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"sync"
 	"time"
 
-	"github.com/angelodlfrtr/go-can"
 	"github.com/google/uuid"
 )
 
@@ -31,7 +31,7 @@ type PDOMap struct {
 	CobID      int
 	RTRAllowed bool
 	TransType  byte
-	EventTimer byte
+	EventTimer uint16
 
 	Map map[int]DicObject
 
@@ -120,11 +120,7 @@ func (m *PDOMap) Listen() error {
 	now := time.Now()
 	m.Timestamp = &now
 
-	filterFunc := func(frm *can.Frame) bool {
-		return frm.ArbitrationID == uint32(m.CobID)
-	}
-
-	framesChan := m.PDONode.Node.Network.AcquireFramesChan(&filterFunc)
+	framesChan := m.PDONode.Node.Network.AcquireCobIDsFramesChan(nil, uint32(m.CobID))
 
 	go func() {
 		for {
@@ -136,6 +132,7 @@ func (m *PDOMap) Listen() error {
 				m.Lock()
 				m.IsReceived = true
 				m.SetData(frm.GetData())
+				m.PDONode.Node.trace("PDO received", "cobId", m.CobID, "data", logData(m.Data))
 
 				// @TODO m.Period = frm.Timestamp - m.Timestamp;
 				now := time.Now()
@@ -176,6 +173,9 @@ func (m *PDOMap) Unlisten() {
 
 // AcquireChangesChan create a new PDOMapChangeChan
 func (m *PDOMap) AcquireChangesChan() *PDOMapChangeChan {
+	m.Lock()
+	defer m.Unlock()
+
 	// Create frame chan
 	chanID := uuid.Must(uuid.NewRandom()).String()
 	changesChan := &PDOMapChangeChan{
@@ -191,6 +191,9 @@ func (m *PDOMap) AcquireChangesChan() *PDOMapChangeChan {
 
 // ReleaseChangesChan release (close) a PDOMapChangeChan
 func (m *PDOMap) ReleaseChangesChan(id string) error {
+	m.Lock()
+	defer m.Unlock()
+
 	var changesChan *PDOMapChangeChan
 	var changesChanIndex *int
 
@@ -219,41 +222,79 @@ func (m *PDOMap) ReleaseChangesChan(id string) error {
 	return nil
 }
 
+// pdoMapContainsIndex return true if a map variable has given object index
+func pdoMapContainsIndex(m *PDOMap, index uint16) bool {
+	for _, object := range m.Map {
+		if object.GetIndex() == index {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pdoVarData extract a map variable data from map data, using variable offset and size.
+// Return nil if data is too short
+func pdoVarData(object DicObject, data []byte) []byte {
+	start := object.GetOffset() / 8
+	end := start + object.GetDataLen()/8
+
+	if start < 0 || end > len(data) {
+		return nil
+	}
+
+	return data[start:end]
+}
+
+// pdoReadValue read sub-index subIndex of object using SDO and return its value
+// converted to T
+func pdoReadValue[T DicValueType](object DicObject, subIndex uint16) (T, error) {
+	var r T
+
+	sub := object.FindIndex(subIndex)
+	if sub == nil {
+		return r, fmt.Errorf("0x%X sub %d not found", object.GetIndex(), subIndex)
+	}
+
+	if err := sub.Read(); err != nil {
+		return r, err
+	}
+
+	return Value[T](sub)
+}
+
 // Read map values
 func (m *PDOMap) Read() error {
 	// Get COB ID
-	if err := m.ComRecord.FindIndex(1).Read(); err != nil {
+	cobID, err := pdoReadValue[uint32](m.ComRecord, 1)
+	if err != nil {
 		return err
 	}
 
-	cobID := int(*m.ComRecord.FindIndex(1).GetUintVal())
-	m.CobID = cobID
+	m.CobID = int(cobID)
 
 	// Is enabled
 	m.Enabled = (int64(cobID) & MapPDONotValid) == 0
 
 	// Is RTRAllowed
-	m.RTRAllowed = (cobID & MapRTRNotAllowed) == 0
+	m.RTRAllowed = (m.CobID & MapRTRNotAllowed) == 0
 
 	// Get Trans type
-	if err := m.ComRecord.FindIndex(2).Read(); err != nil {
+	transType, err := pdoReadValue[uint8](m.ComRecord, 2)
+	if err != nil {
 		return err
 	}
 
-	transType := *m.ComRecord.FindIndex(2).GetUintVal()
-	m.TransType = byte(transType)
+	m.TransType = transType
 
 	// Get EventTimer
-	if transType > 254 {
-		comr := m.ComRecord.FindIndex(5)
-
-		if comr != nil {
-			if err := comr.Read(); err != nil {
-				return err
-			}
-
-			m.EventTimer = *comr.GetByteVal()
+	if transType > 254 && m.ComRecord.FindIndex(5) != nil {
+		eventTimer, err := pdoReadValue[uint16](m.ComRecord, 5)
+		if err != nil {
+			return err
 		}
+
+		m.EventTimer = eventTimer
 	}
 
 	// Init m.Map
@@ -261,20 +302,17 @@ func (m *PDOMap) Read() error {
 	offset := 0
 
 	// Nof entries
-	if err := m.MapArray.FindIndex(0).Read(); err != nil {
+	nofEntries, err := pdoReadValue[uint8](m.MapArray, 0)
+	if err != nil {
 		return err
 	}
 
-	nofEntries := int(m.MapArray.FindIndex(0).GetData()[0])
-
-	for i := 1; i <= (nofEntries + 1); i++ {
-		ii := uint16(i)
-		if err := m.MapArray.FindIndex(ii).Read(); err != nil {
+	for i := 1; i <= int(nofEntries); i++ {
+		val, err := pdoReadValue[uint32](m.MapArray, uint16(i))
+		if err != nil {
 			return err
 		}
 
-		val := *m.MapArray.FindIndex(ii).GetUintVal()
-
 		index := uint16(val >> 16)
 		subindex := uint16((val >> 8) & 0xFF)
 		size := val & 0xFF
@@ -284,16 +322,23 @@ func (m *PDOMap) Read() error {
 		}
 
 		dicVar := m.PDONode.Node.ObjectDic.FindIndex(index)
+		if dicVar == nil {
+			return fmt.Errorf("mapped object 0x%X not found", index)
+		}
+
+		if !dicVar.IsDicVariable() {
+			dicVar = dicVar.FindIndex(subindex)
+			if dicVar == nil {
+				return fmt.Errorf("mapped object 0x%X sub %d not found", index, subindex)
+			}
+		}
+
 		// Instead of dicVar.Size = size @TODO: use uint64 for size
 		dicVar.SetSize(int(size))
 
 		// Set sdo client
 		dicVar.SetSDO(m.PDONode.Node.SDOClient)
 
-		if !dicVar.IsDicVariable() {
-			dicVar = dicVar.FindIndex(subindex)
-		}
-
 		dicVar.SetOffset(offset)
 		// @TODO: check working
 		m.Map[i] = dicVar
@@ -343,7 +388,10 @@ func (m *PDOMap) Save() error {
 	}
 
 	if m.EventTimer != 0 {
-		m.ComRecord.FindIndex(5).SetData([]byte{m.EventTimer})
+		if err := SetValue(m.ComRecord.FindIndex(5), m.EventTimer); err != nil {
+			return err
+		}
+
 		if err := m.ComRecord.FindIndex(5).Save(); err != nil {
 			return err
 		}
@@ -352,9 +400,11 @@ func (m *PDOMap) Save() error {
 	if len(m.Map) > 0 {
 		for i, dicVar := range m.Map {
 			subIndex := i + 1
-			val := ((((dicVar.GetIndex() << 16) | uint16(dicVar.GetSubIndex())) << 8) | uint16(dicVar.GetDataLen()))
+			val := uint32(dicVar.GetIndex())<<16 | uint32(dicVar.GetSubIndex())<<8 | uint32(dicVar.GetDataLen())
 			mm := m.MapArray.FindIndex(uint16(subIndex))
-			mm.SetUintVal(uint64(val))
+			if err := mm.SetUintVal(uint64(val)); err != nil {
+				return err
+			}
 
 			if err := mm.Save(); err != nil {
 				return err
@@ -362,7 +412,9 @@ func (m *PDOMap) Save() error {
 		}
 
 		mapLen := len(m.Map)
-		m.MapArray.FindIndex(0).SetIntVal(int64(mapLen))
+		if err := m.MapArray.FindIndex(0).SetIntVal(int64(mapLen)); err != nil {
+			return err
+		}
 		if err := m.MapArray.FindIndex(0).Save(); err != nil {
 			return err
 		}
@@ -400,5 +452,6 @@ func (m *PDOMap) Transmit(rebuild bool) error {
 		m.RebuildData()
 	}
 
+	m.PDONode.Node.trace("PDO transmitted", "cobId", m.CobID, "data", logData(m.Data))
 	return m.PDONode.Node.Network.Send(uint32(m.CobID), m.Data)
 }
@@ -0,0 +1,109 @@
+package canopen
+
+import "testing"
+
+func TestPDOMapRead(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 4)
+
+	dic := NewDicObjectDic()
+	com := &DicRecord{Index: 0x1800, Name: "TPDO1 communication parameter"}
+	com.AddMember(&DicVariable{Index: 0x1800, SubIndex: 1, Name: "COB-ID", DataType: Unsigned32})
+	com.AddMember(&DicVariable{Index: 0x1800, SubIndex: 2, Name: "Transmission type", DataType: Unsigned8})
+	com.AddMember(&DicVariable{Index: 0x1800, SubIndex: 5, Name: "Event timer", DataType: Unsigned16})
+	dic.AddObject(com)
+
+	mapping := &DicRecord{Index: 0x1A00, Name: "TPDO1 mapping parameter"}
+	mapping.AddMember(&DicVariable{Index: 0x1A00, SubIndex: 0, Name: "Number of entries", DataType: Unsigned8})
+	mapping.AddMember(&DicVariable{Index: 0x1A00, SubIndex: 1, Name: "Mapping entry 1", DataType: Unsigned32})
+	mapping.AddMember(&DicVariable{Index: 0x1A00, SubIndex: 2, Name: "Mapping entry 2", DataType: Unsigned32})
+	dic.AddObject(mapping)
+
+	dic.AddObject(&DicVariable{Index: 0x2000, Name: "Status", DataType: Unsigned8})
+	inputs := &DicArray{Index: 0x2001, Name: "Inputs"}
+	inputs.AddMember(&DicVariable{Index: 0x2001, SubIndex: 0, Name: "Number of inputs", DataType: Unsigned8})
+	inputs.AddMember(&DicVariable{Index: 0x2001, SubIndex: 1, Name: "Input 1", DataType: Unsigned16})
+	dic.AddObject(inputs)
+
+	node, err := network.AddNode(NewNode(4, nil, nil), dic, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.Set(0x1800, 1, []byte{0x84, 0x01, 0x00, 0x40})
+	server.Set(0x1800, 2, []byte{0xFF})
+	server.Set(0x1800, 5, []byte{0xF4, 0x01})
+	server.Set(0x1A00, 0, []byte{0x02})
+	server.Set(0x1A00, 1, []byte{0x08, 0x00, 0x00, 0x20})
+	server.Set(0x1A00, 2, []byte{0x10, 0x01, 0x01, 0x20})
+
+	m := node.PDONode.TX.FindIndex(1)
+	if m == nil {
+		t.Fatal("TPDO1 not found")
+	}
+
+	if err := m.Read(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Unlisten()
+
+	if m.CobID != 0x40000184 || !m.Enabled || m.RTRAllowed {
+		t.Fatalf("invalid COB-ID 0x%X", m.CobID)
+	}
+
+	if m.TransType != 0xFF || m.EventTimer != 500 {
+		t.Fatalf("invalid transmission type %d or event timer %d", m.TransType, m.EventTimer)
+	}
+
+	if len(m.Map) != 2 {
+		t.Fatalf("invalid map len %d", len(m.Map))
+	}
+
+	for i, tt := range []struct {
+		index    uint16
+		subIndex uint8
+		offset   int
+	}{
+		{0x2000, 0, 0},
+		{0x2001, 1, 8},
+	} {
+		object := m.FindIndex(i + 1)
+		if object == nil || object.GetIndex() != tt.index || object.GetSubIndex() != tt.subIndex || object.GetOffset() != tt.offset {
+			t.Fatalf("invalid mapped object %d: %v", i+1, object)
+		}
+	}
+
+	if m.GetTotalSize() != 24 {
+		t.Fatalf("invalid total size %d", m.GetTotalSize())
+	}
+}
+
+func TestPDOMapReadMissingObject(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 4)
+
+	dic := NewDicObjectDic()
+	com := &DicRecord{Index: 0x1800, Name: "TPDO1 communication parameter"}
+	com.AddMember(&DicVariable{Index: 0x1800, SubIndex: 1, Name: "COB-ID", DataType: Unsigned32})
+	com.AddMember(&DicVariable{Index: 0x1800, SubIndex: 2, Name: "Transmission type", DataType: Unsigned8})
+	dic.AddObject(com)
+
+	mapping := &DicRecord{Index: 0x1A00, Name: "TPDO1 mapping parameter"}
+	mapping.AddMember(&DicVariable{Index: 0x1A00, SubIndex: 0, Name: "Number of entries", DataType: Unsigned8})
+	mapping.AddMember(&DicVariable{Index: 0x1A00, SubIndex: 1, Name: "Mapping entry 1", DataType: Unsigned32})
+	dic.AddObject(mapping)
+
+	node, err := network.AddNode(NewNode(4, nil, nil), dic, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.Set(0x1800, 1, []byte{0x84, 0x01, 0x00, 0x00})
+	server.Set(0x1800, 2, []byte{0xFF})
+	server.Set(0x1A00, 0, []byte{0x01})
+	server.Set(0x1A00, 1, []byte{0x08, 0x00, 0x00, 0x30})
+
+	if err := node.PDONode.TX.FindIndex(1).Read(); err == nil {
+		t.Fatal("expected error for unknown mapped object")
+	}
+}
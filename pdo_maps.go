@@ -13,6 +13,11 @@ func NewPDOMaps(comOffset, mapOffset int, pdoNode *PDONode) *PDOMaps {
 		Maps:    make(map[int]*PDOMap),
 	}
 
+	// No PDO maps without object dictionary
+	if pdoNode.Node.ObjectDic == nil {
+		return pdoMaps
+	}
+
 	for i := 0; i < 32; i++ {
 		if comSdo := pdoMaps.PDONode.Node.ObjectDic.FindIndex(uint16(comOffset + i)); comSdo != nil {
 			mapSdo := pdoMaps.PDONode.Node.ObjectDic.FindIndex(uint16(mapOffset + i))
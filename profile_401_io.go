@@ -0,0 +1,427 @@
+package canopen
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// CiA 401 generic I/O modules objects indexes
+const (
+	IODigitalInputs             uint16 = 0x6000
+	IODigitalInputsPolarity     uint16 = 0x6002
+	IODigitalInputsFilter       uint16 = 0x6003
+	IODigitalInputsIntEnable    uint16 = 0x6005
+	IODigitalInputsIntAnyChange uint16 = 0x6006
+	IODigitalInputsIntLowToHigh uint16 = 0x6007
+	IODigitalInputsIntHighToLow uint16 = 0x6008
+	IODigitalOutputs            uint16 = 0x6200
+	IODigitalOutputsPolarity    uint16 = 0x6202
+	IODigitalOutputsFilter      uint16 = 0x6208
+	IOAnalogInputs16            uint16 = 0x6401
+	IOAnalogOutputs16           uint16 = 0x6411
+)
+
+const (
+	ioDigitalLinesPerGroup        int   = 8
+	ioDigitalGroupsCountSubIndex  uint8 = 0
+	ioDigitalGroupsFirstSubIndex  uint8 = 1
+	ioAnalogChannelsFirstSubIndex uint8 = 1
+
+	// ioInputsChanSize is the buffer size of IOInputsChan
+	ioInputsChanSize int = 16
+)
+
+// IOBitSet is a set of digital lines. Line 0 is bit 0 of the first 8 bits group
+// (sub index 1 of 0x6000 / 0x6200), line 8 is bit 0 of the second group, etc.
+type IOBitSet []byte
+
+// NewIOBitSet return a IOBitSet able to hold at least lines lines
+func NewIOBitSet(lines int) IOBitSet {
+	return make(IOBitSet, (lines+ioDigitalLinesPerGroup-1)/ioDigitalLinesPerGroup)
+}
+
+// Len return the number of lines in set
+func (set IOBitSet) Len() int {
+	return len(set) * ioDigitalLinesPerGroup
+}
+
+// Get line state. Return false if line is out of set
+func (set IOBitSet) Get(line int) bool {
+	if line < 0 || line >= set.Len() {
+		return false
+	}
+
+	return set[line/ioDigitalLinesPerGroup]&(1<<uint(line%ioDigitalLinesPerGroup)) != 0
+}
+
+// Set line state. Do nothing if line is out of set
+func (set IOBitSet) Set(line int, v bool) {
+	if line < 0 || line >= set.Len() {
+		return
+	}
+
+	mask := byte(1 << uint(line%ioDigitalLinesPerGroup))
+
+	if v {
+		set[line/ioDigitalLinesPerGroup] |= mask
+	} else {
+		set[line/ioDigitalLinesPerGroup] &^= mask
+	}
+}
+
+// Changed return a IOBitSet where each line set is a line which differ between set and other
+func (set IOBitSet) Changed(other IOBitSet) IOBitSet {
+	l := len(set)
+	if len(other) > l {
+		l = len(other)
+	}
+
+	changed := make(IOBitSet, l)
+
+	for i := 0; i < l; i++ {
+		var a, b byte
+
+		if i < len(set) {
+			a = set[i]
+		}
+
+		if i < len(other) {
+			b = other[i]
+		}
+
+		changed[i] = a ^ b
+	}
+
+	return changed
+}
+
+// IOInputsChange is sent on IOInputsChan when digital inputs change
+type IOInputsChange struct {
+	// Inputs contain the new inputs state
+	Inputs IOBitSet
+
+	// Changed contain lines which changed since last state
+	Changed IOBitSet
+}
+
+// IOInputsChan contain a chan where digital inputs changes are sent. C is
+// buffered, changes are dropped while its buffer is full
+type IOInputsChan struct {
+	ID string
+	C  chan *IOInputsChange
+}
+
+// IOModule is a CiA 401 generic I/O module wrapper over a Node
+type IOModule struct {
+	sync.Mutex
+
+	Node *Node
+
+	// Inputs contain last known digital inputs state, updated from TPDOs
+	Inputs IOBitSet
+
+	InputsChans []*IOInputsChan
+
+	tpdos profileTPDOListener
+}
+
+// NewIOModule return a IOModule for given node
+func NewIOModule(node *Node) *IOModule {
+	return &IOModule{
+		Node:        node,
+		InputsChans: []*IOInputsChan{},
+	}
+}
+
+func (module *IOModule) sdoClient() (*SDOClient, error) {
+	if module.Node == nil || module.Node.SDOClient == nil {
+		return nil, errors.New("node not initialized, add it to a network first")
+	}
+
+	return module.Node.SDOClient, nil
+}
+
+// readGroups read all 8 bits groups of given index
+func (module *IOModule) readGroups(index uint16) (IOBitSet, error) {
+	sdoClient, err := module.sdoClient()
+	if err != nil {
+		return nil, err
+	}
+
+	nof, err := sdoClient.Read(index, ioDigitalGroupsCountSubIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(nof) == 0 {
+		return nil, fmt.Errorf("empty number of entries for object 0x%04X", index)
+	}
+
+	set := make(IOBitSet, int(nof[0]))
+
+	for i := range set {
+		data, err := sdoClient.Read(index, ioDigitalGroupsFirstSubIndex+uint8(i))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data) == 0 {
+			return nil, fmt.Errorf("empty data for object 0x%04Xsub%d", index, i+1)
+		}
+
+		set[i] = data[0]
+	}
+
+	return set, nil
+}
+
+// writeGroup write a 8 bits group value. group start from 1
+func (module *IOModule) writeGroup(index uint16, group uint8, v byte) error {
+	sdoClient, err := module.sdoClient()
+	if err != nil {
+		return err
+	}
+
+	if group < ioDigitalGroupsFirstSubIndex {
+		return errors.New("group start from 1")
+	}
+
+	return sdoClient.Write(index, group, false, []byte{v})
+}
+
+// ReadDigitalInputs read all digital inputs (0x6000)
+func (module *IOModule) ReadDigitalInputs() (IOBitSet, error) {
+	set, err := module.readGroups(IODigitalInputs)
+	if err != nil {
+		return nil, err
+	}
+
+	module.Lock()
+	module.Inputs = set
+	module.Unlock()
+
+	return set, nil
+}
+
+// ReadDigitalOutputs read all digital outputs (0x6200)
+func (module *IOModule) ReadDigitalOutputs() (IOBitSet, error) {
+	return module.readGroups(IODigitalOutputs)
+}
+
+// WriteDigitalOutputs write all digital outputs groups in set (0x6200)
+func (module *IOModule) WriteDigitalOutputs(set IOBitSet) error {
+	for i, v := range set {
+		if err := module.writeGroup(IODigitalOutputs, ioDigitalGroupsFirstSubIndex+uint8(i), v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetDigitalOutput set a single output line. The line group is read before being written
+func (module *IOModule) SetDigitalOutput(line int, v bool) error {
+	sdoClient, err := module.sdoClient()
+	if err != nil {
+		return err
+	}
+
+	if line < 0 {
+		return errors.New("invalid line")
+	}
+
+	group := ioDigitalGroupsFirstSubIndex + uint8(line/ioDigitalLinesPerGroup)
+
+	data, err := sdoClient.Read(IODigitalOutputs, group)
+	if err != nil {
+		return err
+	}
+
+	if len(data) == 0 {
+		return fmt.Errorf("empty data for object 0x%04Xsub%d", IODigitalOutputs, group)
+	}
+
+	set := IOBitSet{data[0]}
+	set.Set(line%ioDigitalLinesPerGroup, v)
+
+	return module.writeGroup(IODigitalOutputs, group, set[0])
+}
+
+// ReadAnalogInput read a 16 bits analog input (0x6401). channel start from 1
+func (module *IOModule) ReadAnalogInput(channel uint8) (int16, error) {
+	return module.readAnalog(IOAnalogInputs16, channel)
+}
+
+// ReadAnalogOutput read a 16 bits analog output (0x6411). channel start from 1
+func (module *IOModule) ReadAnalogOutput(channel uint8) (int16, error) {
+	return module.readAnalog(IOAnalogOutputs16, channel)
+}
+
+// WriteAnalogOutput write a 16 bits analog output (0x6411). channel start from 1
+func (module *IOModule) WriteAnalogOutput(channel uint8, v int16) error {
+	sdoClient, err := module.sdoClient()
+	if err != nil {
+		return err
+	}
+
+	if channel < ioAnalogChannelsFirstSubIndex {
+		return errors.New("channel start from 1")
+	}
+
+	data := make([]byte, 2)
+	binary.LittleEndian.PutUint16(data, uint16(v))
+
+	return sdoClient.Write(IOAnalogOutputs16, channel, false, data)
+}
+
+func (module *IOModule) readAnalog(index uint16, channel uint8) (int16, error) {
+	sdoClient, err := module.sdoClient()
+	if err != nil {
+		return 0, err
+	}
+
+	if channel < ioAnalogChannelsFirstSubIndex {
+		return 0, errors.New("channel start from 1")
+	}
+
+	data, err := sdoClient.Read(index, channel)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(data) < 2 {
+		return 0, fmt.Errorf("invalid data length %d for object 0x%04Xsub%d", len(data), index, channel)
+	}
+
+	return int16(binary.LittleEndian.Uint16(data)), nil
+}
+
+// SetInputsPolarity set polarity mask of a digital inputs group (0x6002). A bit set invert the input
+func (module *IOModule) SetInputsPolarity(group uint8, mask byte) error {
+	return module.writeGroup(IODigitalInputsPolarity, group, mask)
+}
+
+// SetOutputsPolarity set polarity mask of a digital outputs group (0x6202). A bit set invert the output
+func (module *IOModule) SetOutputsPolarity(group uint8, mask byte) error {
+	return module.writeGroup(IODigitalOutputsPolarity, group, mask)
+}
+
+// SetInputsFilter set filter mask of a digital inputs group (0x6003). A bit set enable the input filter
+func (module *IOModule) SetInputsFilter(group uint8, mask byte) error {
+	return module.writeGroup(IODigitalInputsFilter, group, mask)
+}
+
+// SetOutputsFilter set filter mask of a digital outputs group (0x6208).
+// A bit cleared lock the output at its current value
+func (module *IOModule) SetOutputsFilter(group uint8, mask byte) error {
+	return module.writeGroup(IODigitalOutputsFilter, group, mask)
+}
+
+// SetInterruptEnabled enable or disable inputs interrupts (0x6005), ie TPDO transmission on inputs change
+func (module *IOModule) SetInterruptEnabled(enabled bool) error {
+	sdoClient, err := module.sdoClient()
+	if err != nil {
+		return err
+	}
+
+	var v byte
+	if enabled {
+		v = 0x01
+	}
+
+	return sdoClient.Write(IODigitalInputsIntEnable, 0, false, []byte{v})
+}
+
+// SetInterruptMasks set interrupts masks of a digital inputs group (0x6006, 0x6007 and 0x6008)
+func (module *IOModule) SetInterruptMasks(group uint8, anyChange, lowToHigh, highToLow byte) error {
+	if err := module.writeGroup(IODigitalInputsIntAnyChange, group, anyChange); err != nil {
+		return err
+	}
+
+	if err := module.writeGroup(IODigitalInputsIntLowToHigh, group, lowToHigh); err != nil {
+		return err
+	}
+
+	return module.writeGroup(IODigitalInputsIntHighToLow, group, highToLow)
+}
+
+// AcquireInputsChan create a new IOInputsChan
+func (module *IOModule) AcquireInputsChan() *IOInputsChan {
+	module.Lock()
+	defer module.Unlock()
+
+	chanID := uuid.Must(uuid.NewRandom()).String()
+	inputsChan := &IOInputsChan{
+		ID: chanID,
+		C:  make(chan *IOInputsChange, ioInputsChanSize),
+	}
+
+	module.InputsChans = append(module.InputsChans, inputsChan)
+
+	return inputsChan
+}
+
+// ReleaseInputsChan release (close) a IOInputsChan
+func (module *IOModule) ReleaseInputsChan(id string) error {
+	module.Lock()
+	defer module.Unlock()
+
+	for idx, ic := range module.InputsChans {
+		if ic.ID == id {
+			close(ic.C)
+			module.InputsChans = append(module.InputsChans[:idx], module.InputsChans[idx+1:]...)
+			return nil
+		}
+	}
+
+	return errors.New("no IOInputsChan found with specified ID")
+}
+
+// Listen for digital inputs changes in node TPDOs. node.PDONode.Read() must have been called before
+func (module *IOModule) Listen() error {
+	return module.tpdos.Listen(module.Node, IODigitalInputs, module.handleMapData)
+}
+
+func (module *IOModule) handleMapData(m *PDOMap, data []byte) {
+	module.Lock()
+	defer module.Unlock()
+
+	inputs := append(IOBitSet{}, module.Inputs...)
+
+	for _, object := range m.Map {
+		if object.GetIndex() != IODigitalInputs || object.GetSubIndex() < ioDigitalGroupsFirstSubIndex {
+			continue
+		}
+
+		v := pdoVarData(object, data)
+		if len(v) == 0 {
+			continue
+		}
+
+		group := int(object.GetSubIndex() - ioDigitalGroupsFirstSubIndex)
+		for len(inputs) <= group {
+			inputs = append(inputs, 0)
+		}
+
+		inputs[group] = v[0]
+	}
+
+	changed := inputs.Changed(module.Inputs)
+	module.Inputs = inputs
+
+	for _, inputsChan := range module.InputsChans {
+		select {
+		case inputsChan.C <- &IOInputsChange{Inputs: inputs, Changed: changed}:
+		default:
+		}
+	}
+}
+
+// Unlisten for digital inputs changes
+func (module *IOModule) Unlisten() {
+	module.tpdos.Unlisten()
+}
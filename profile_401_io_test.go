@@ -0,0 +1,148 @@
+package canopen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIOBitSet(t *testing.T) {
+	set := NewIOBitSet(12)
+
+	if set.Len() != 16 {
+		t.Fatalf("invalid len %d", set.Len())
+	}
+
+	set.Set(0, true)
+	set.Set(9, true)
+	set.Set(20, true)
+
+	if !set.Get(0) || !set.Get(9) || set.Get(1) || set.Get(20) {
+		t.Fatalf("invalid set %v", set)
+	}
+
+	set.Set(0, false)
+	if set.Get(0) {
+		t.Fatal("line 0 should be cleared")
+	}
+
+	changed := set.Changed(IOBitSet{0x00, 0x00, 0x01})
+	if !changed.Get(9) || !changed.Get(16) || changed.Get(0) {
+		t.Fatalf("invalid changed %v", changed)
+	}
+}
+
+func newIOTestNode(t *testing.T) (*Node, *fakeSDOServer, *fakeTransport) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 3)
+
+	dic := NewDicObjectDic()
+	inputs := &DicArray{Index: IODigitalInputs, Name: "Read input 8-bit"}
+	inputs.AddMember(&DicVariable{Index: IODigitalInputs, SubIndex: 0, Name: "Number of inputs", DataType: Unsigned8})
+	inputs.AddMember(&DicVariable{Index: IODigitalInputs, SubIndex: 1, Name: "Inputs 1h-8h", DataType: Unsigned8})
+	inputs.AddMember(&DicVariable{Index: IODigitalInputs, SubIndex: 2, Name: "Inputs 9h-10h", DataType: Unsigned8})
+	dic.AddObject(inputs)
+
+	node := NewNode(3, nil, nil)
+	if _, err := network.AddNode(node, dic, false); err != nil {
+		t.Fatal(err)
+	}
+
+	return node, server, transport
+}
+
+func TestIOModuleDigital(t *testing.T) {
+	node, server, _ := newIOTestNode(t)
+	module := NewIOModule(node)
+
+	server.Set(IODigitalInputs, 0, []byte{0x02})
+	server.Set(IODigitalInputs, 1, []byte{0x81})
+	server.Set(IODigitalInputs, 2, []byte{0x02})
+
+	inputs, err := module.ReadDigitalInputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !inputs.Get(0) || !inputs.Get(7) || !inputs.Get(9) || inputs.Get(8) {
+		t.Fatalf("invalid inputs %v", inputs)
+	}
+
+	server.Set(IODigitalOutputs, 1, []byte{0x01})
+	if err := module.SetDigitalOutput(3, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := server.Get(IODigitalOutputs, 1); v[0] != 0x09 {
+		t.Fatalf("invalid outputs 0x%X", v[0])
+	}
+
+	if err := module.WriteAnalogOutput(2, -100); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := module.ReadAnalogOutput(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v != -100 {
+		t.Fatalf("invalid analog output %d", v)
+	}
+}
+
+func TestIOModuleInputsEvents(t *testing.T) {
+	node, _, transport := newIOTestNode(t)
+	module := NewIOModule(node)
+
+	// Map inputs groups in TPDO1
+	inputs := node.ObjectDic.FindIndex(IODigitalInputs)
+	group1 := inputs.FindIndex(1)
+	group1.SetOffset(0)
+	group2 := inputs.FindIndex(2)
+	group2.SetOffset(8)
+
+	m := NewPDOMap(node.PDONode, nil, nil)
+	m.CobID = 0x183
+	m.Map = map[int]DicObject{1: group1, 2: group2}
+	node.PDONode.TX.Maps[1] = m
+
+	if err := m.Listen(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := module.Listen(); err != nil {
+		t.Fatal(err)
+	}
+	defer module.Unlisten()
+
+	inputsChan := module.AcquireInputsChan()
+	defer module.ReleaseInputsChan(inputsChan.ID)
+
+	transport.Inject(0x183, []byte{0x04, 0x01})
+
+	select {
+	case change := <-inputsChan.C:
+		if !change.Inputs.Get(2) || !change.Inputs.Get(8) || !change.Changed.Get(2) {
+			t.Fatalf("invalid change %v", change)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no inputs change received")
+	}
+}
+
+func TestIOModuleInputsChanBuffer(t *testing.T) {
+	node, _, _ := newIOTestNode(t)
+	module := NewIOModule(node)
+
+	inputsChan := module.AcquireInputsChan()
+	defer module.ReleaseInputsChan(inputsChan.ID)
+
+	// Changes are queued while the consumer is not receiving
+	for i := 0; i < ioInputsChanSize+2; i++ {
+		module.handleMapData(&PDOMap{}, nil)
+	}
+
+	if len(inputsChan.C) != ioInputsChanSize {
+		t.Errorf("expected %d queued changes, got %d", ioInputsChanSize, len(inputsChan.C))
+	}
+}
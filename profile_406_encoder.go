@@ -0,0 +1,271 @@
+package canopen
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CiA 406 encoders objects indexes
+const (
+	EncoderOperatingParameters       uint16 = 0x6000
+	EncoderMeasuringUnitsPerRevision uint16 = 0x6001
+	EncoderTotalMeasuringRange       uint16 = 0x6002
+	EncoderPresetValue               uint16 = 0x6003
+	EncoderPositionValue             uint16 = 0x6004
+	EncoderSpeedValue                uint16 = 0x6030
+)
+
+// Operating parameters (0x6000) bits
+const (
+	EncoderCodeSequenceCCW uint16 = 1 << 0
+	EncoderScalingEnabled  uint16 = 1 << 2
+)
+
+const (
+	encoderSpeedSubIndex    uint8 = 1
+	encoderPositionDataSize int   = 4
+
+	// encoderPositionChanSize is the buffer size of EncoderPositionChan
+	encoderPositionChanSize int = 16
+)
+
+// EncoderPosition is sent on EncoderPositionChan for each position received in TPDOs
+type EncoderPosition struct {
+	Position  uint32
+	Timestamp time.Time
+}
+
+// EncoderPositionChan contain a chan where encoder positions are sent. C is
+// buffered, positions are dropped while its buffer is full
+type EncoderPositionChan struct {
+	ID string
+	C  chan *EncoderPosition
+}
+
+// Encoder is a CiA 406 encoder wrapper over a Node
+type Encoder struct {
+	sync.Mutex
+
+	Node *Node
+
+	// Position contain last position received from TPDOs
+	Position *EncoderPosition
+
+	PositionChans []*EncoderPositionChan
+
+	tpdos profileTPDOListener
+}
+
+// NewEncoder return an Encoder for given node
+func NewEncoder(node *Node) *Encoder {
+	return &Encoder{
+		Node:          node,
+		PositionChans: []*EncoderPositionChan{},
+	}
+}
+
+func (encoder *Encoder) sdoClient() (*SDOClient, error) {
+	if encoder.Node == nil || encoder.Node.SDOClient == nil {
+		return nil, errors.New("node not initialized, add it to a network first")
+	}
+
+	return encoder.Node.SDOClient, nil
+}
+
+func (encoder *Encoder) readUint(index uint16, subIndex uint8, size int) (uint64, error) {
+	sdoClient, err := encoder.sdoClient()
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := sdoClient.Read(index, subIndex)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(data) < size {
+		return 0, fmt.Errorf("invalid data length %d for object 0x%04Xsub%d", len(data), index, subIndex)
+	}
+
+	buf := make([]byte, 8)
+	copy(buf, data[:size])
+
+	return binary.LittleEndian.Uint64(buf), nil
+}
+
+func (encoder *Encoder) writeUint(index uint16, subIndex uint8, size int, v uint64) error {
+	sdoClient, err := encoder.sdoClient()
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, v)
+
+	return sdoClient.Write(index, subIndex, false, buf[:size])
+}
+
+// ReadPosition read position value (0x6004)
+func (encoder *Encoder) ReadPosition() (uint32, error) {
+	v, err := encoder.readUint(EncoderPositionValue, 0, 4)
+	return uint32(v), err
+}
+
+// ReadSpeed read speed value (0x6030 sub 1)
+func (encoder *Encoder) ReadSpeed() (int16, error) {
+	v, err := encoder.readUint(EncoderSpeedValue, encoderSpeedSubIndex, 2)
+	return int16(v), err
+}
+
+// ReadOperatingParameters read operating parameters (0x6000)
+func (encoder *Encoder) ReadOperatingParameters() (uint16, error) {
+	v, err := encoder.readUint(EncoderOperatingParameters, 0, 2)
+	return uint16(v), err
+}
+
+// WriteOperatingParameters write operating parameters (0x6000)
+func (encoder *Encoder) WriteOperatingParameters(params uint16) error {
+	return encoder.writeUint(EncoderOperatingParameters, 0, 2, uint64(params))
+}
+
+// SetCodeSequence set counting direction. ccw true mean position increase when turning counter clockwise
+func (encoder *Encoder) SetCodeSequence(ccw bool) error {
+	params, err := encoder.ReadOperatingParameters()
+	if err != nil {
+		return err
+	}
+
+	if ccw {
+		params |= EncoderCodeSequenceCCW
+	} else {
+		params &^= EncoderCodeSequenceCCW
+	}
+
+	return encoder.WriteOperatingParameters(params)
+}
+
+// ReadScaling read measuring units per revolution (0x6001) and total measuring range (0x6002)
+func (encoder *Encoder) ReadScaling() (unitsPerRevolution, totalRange uint32, err error) {
+	upr, err := encoder.readUint(EncoderMeasuringUnitsPerRevision, 0, 4)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tr, err := encoder.readUint(EncoderTotalMeasuringRange, 0, 4)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return uint32(upr), uint32(tr), nil
+}
+
+// SetScaling write measuring units per revolution (0x6001) and total measuring range (0x6002),
+// then enable scaling function in operating parameters (0x6000)
+func (encoder *Encoder) SetScaling(unitsPerRevolution, totalRange uint32) error {
+	if unitsPerRevolution == 0 || totalRange == 0 {
+		return errors.New("scaling values must be greater than 0")
+	}
+
+	if err := encoder.writeUint(EncoderMeasuringUnitsPerRevision, 0, 4, uint64(unitsPerRevolution)); err != nil {
+		return err
+	}
+
+	if err := encoder.writeUint(EncoderTotalMeasuringRange, 0, 4, uint64(totalRange)); err != nil {
+		return err
+	}
+
+	params, err := encoder.ReadOperatingParameters()
+	if err != nil {
+		return err
+	}
+
+	return encoder.WriteOperatingParameters(params | EncoderScalingEnabled)
+}
+
+// ReadPreset read preset value (0x6003)
+func (encoder *Encoder) ReadPreset() (uint32, error) {
+	v, err := encoder.readUint(EncoderPresetValue, 0, 4)
+	return uint32(v), err
+}
+
+// SetPreset write preset value (0x6003). The encoder position is set to preset immediately
+func (encoder *Encoder) SetPreset(preset uint32) error {
+	return encoder.writeUint(EncoderPresetValue, 0, 4, uint64(preset))
+}
+
+// AcquirePositionChan create a new EncoderPositionChan
+func (encoder *Encoder) AcquirePositionChan() *EncoderPositionChan {
+	encoder.Lock()
+	defer encoder.Unlock()
+
+	chanID := uuid.Must(uuid.NewRandom()).String()
+	positionChan := &EncoderPositionChan{
+		ID: chanID,
+		C:  make(chan *EncoderPosition, encoderPositionChanSize),
+	}
+
+	encoder.PositionChans = append(encoder.PositionChans, positionChan)
+
+	return positionChan
+}
+
+// ReleasePositionChan release (close) a EncoderPositionChan
+func (encoder *Encoder) ReleasePositionChan(id string) error {
+	encoder.Lock()
+	defer encoder.Unlock()
+
+	for idx, pc := range encoder.PositionChans {
+		if pc.ID == id {
+			close(pc.C)
+			encoder.PositionChans = append(encoder.PositionChans[:idx], encoder.PositionChans[idx+1:]...)
+			return nil
+		}
+	}
+
+	return errors.New("no EncoderPositionChan found with specified ID")
+}
+
+// Listen for positions in node TPDOs. node.PDONode.Read() must have been called before
+func (encoder *Encoder) Listen() error {
+	return encoder.tpdos.Listen(encoder.Node, EncoderPositionValue, encoder.handleMapData)
+}
+
+func (encoder *Encoder) handleMapData(m *PDOMap, data []byte) {
+	for _, object := range m.Map {
+		if object.GetIndex() != EncoderPositionValue {
+			continue
+		}
+
+		v := pdoVarData(object, data)
+		if len(v) < encoderPositionDataSize {
+			continue
+		}
+
+		position := &EncoderPosition{
+			Position:  binary.LittleEndian.Uint32(v),
+			Timestamp: time.Now(),
+		}
+
+		encoder.Lock()
+		encoder.Position = position
+
+		for _, positionChan := range encoder.PositionChans {
+			select {
+			case positionChan.C <- position:
+			default:
+			}
+		}
+
+		encoder.Unlock()
+	}
+}
+
+// Unlisten for positions
+func (encoder *Encoder) Unlisten() {
+	encoder.tpdos.Unlisten()
+}
@@ -0,0 +1,136 @@
+package canopen
+
+import (
+	"testing"
+	"time"
+)
+
+func newEncoderTestNode(t *testing.T) (*Node, *fakeSDOServer, *fakeTransport) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 4)
+
+	dic := NewDicObjectDic()
+	dic.AddObject(&DicVariable{Index: EncoderPositionValue, Name: "Position value", DataType: Unsigned32})
+
+	node := NewNode(4, nil, nil)
+	if _, err := network.AddNode(node, dic, false); err != nil {
+		t.Fatal(err)
+	}
+
+	return node, server, transport
+}
+
+func TestEncoderParameters(t *testing.T) {
+	node, server, _ := newEncoderTestNode(t)
+	encoder := NewEncoder(node)
+
+	// Code sequence CCW and an unrelated bit set
+	server.Set(EncoderOperatingParameters, 0, []byte{0x09, 0x00})
+
+	if err := encoder.SetScaling(4096, 1<<24); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := server.Get(EncoderOperatingParameters, 0); v[0] != 0x0D || v[1] != 0x00 {
+		t.Errorf("unexpected operating parameters % X", v)
+	}
+
+	upr, tr, err := encoder.ReadScaling()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if upr != 4096 || tr != 1<<24 {
+		t.Errorf("unexpected scaling %d %d", upr, tr)
+	}
+
+	if err := encoder.SetScaling(0, 1); err == nil {
+		t.Error("expected invalid scaling error")
+	}
+
+	if err := encoder.SetCodeSequence(false); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := server.Get(EncoderOperatingParameters, 0); v[0] != 0x0C {
+		t.Errorf("unexpected operating parameters % X", v)
+	}
+
+	if err := encoder.SetCodeSequence(true); err != nil {
+		t.Fatal(err)
+	}
+
+	if params, err := encoder.ReadOperatingParameters(); err != nil || params != 0x0D {
+		t.Errorf("unexpected operating parameters 0x%X %v", params, err)
+	}
+
+	if err := encoder.SetPreset(1000); err != nil {
+		t.Fatal(err)
+	}
+
+	if preset, err := encoder.ReadPreset(); err != nil || preset != 1000 {
+		t.Errorf("unexpected preset %d %v", preset, err)
+	}
+
+	server.Set(EncoderPositionValue, 0, []byte{0x78, 0x56, 0x34, 0x12})
+	server.Set(EncoderSpeedValue, 1, []byte{0xF6, 0xFF})
+
+	if position, err := encoder.ReadPosition(); err != nil || position != 0x12345678 {
+		t.Errorf("unexpected position 0x%X %v", position, err)
+	}
+
+	if speed, err := encoder.ReadSpeed(); err != nil || speed != -10 {
+		t.Errorf("unexpected speed %d %v", speed, err)
+	}
+}
+
+func TestEncoderPositionEvents(t *testing.T) {
+	node, _, transport := newEncoderTestNode(t)
+	encoder := NewEncoder(node)
+
+	if err := encoder.Listen(); err == nil {
+		t.Fatal("expected no TPDO error")
+	}
+
+	// Map position value in TPDO1
+	position := node.ObjectDic.FindIndex(EncoderPositionValue)
+	position.SetOffset(0)
+
+	m := NewPDOMap(node.PDONode, nil, nil)
+	m.CobID = 0x184
+	m.Map = map[int]DicObject{1: position}
+	node.PDONode.TX.Maps[1] = m
+
+	if err := m.Listen(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := encoder.Listen(); err != nil {
+		t.Fatal(err)
+	}
+	defer encoder.Unlisten()
+
+	positionChan := encoder.AcquirePositionChan()
+	defer encoder.ReleasePositionChan(positionChan.ID)
+
+	// Positions are queued until received
+	transport.Inject(0x184, []byte{0x10, 0x27, 0x00, 0x00})
+	time.Sleep(10 * time.Millisecond)
+	transport.Inject(0x184, []byte{0x20, 0x4E, 0x00, 0x00})
+	time.Sleep(10 * time.Millisecond)
+
+	for _, expected := range []uint32{10000, 20000} {
+		select {
+		case p := <-positionChan.C:
+			if p.Position != expected {
+				t.Errorf("expected position %d, got %d", expected, p.Position)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("position %d not received", expected)
+		}
+	}
+
+	if encoder.Position == nil || encoder.Position.Position != 20000 {
+		t.Errorf("unexpected last position %v", encoder.Position)
+	}
+}
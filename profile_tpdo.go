@@ -0,0 +1,89 @@
+package canopen
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// profileTPDOListener forward data changes of the node TPDOs mapping an object
+// to a handler, for device profiles helpers
+type profileTPDOListener struct {
+	sync.Mutex
+
+	listening    bool
+	pdoChans     map[*PDOMap]*PDOMapChangeChan
+	chanChanStop chan bool
+}
+
+// Listen call handle with the data of each TPDO of node mapping index, when it
+// change. node.PDONode.Read() must have been called before
+func (listener *profileTPDOListener) Listen(node *Node, index uint16, handle func(*PDOMap, []byte)) error {
+	listener.Lock()
+	defer listener.Unlock()
+
+	if listener.listening {
+		return nil
+	}
+
+	if node == nil || node.PDONode == nil {
+		return errors.New("node not initialized, add it to a network first")
+	}
+
+	listener.pdoChans = map[*PDOMap]*PDOMapChangeChan{}
+
+	for _, m := range node.PDONode.TX.Maps {
+		if !pdoMapContainsIndex(m, index) {
+			continue
+		}
+
+		listener.pdoChans[m] = m.AcquireChangesChan()
+	}
+
+	if len(listener.pdoChans) == 0 {
+		return fmt.Errorf("no TPDO map object 0x%04X", index)
+	}
+
+	listener.listening = true
+	listener.chanChanStop = make(chan bool)
+
+	for m, changesChan := range listener.pdoChans {
+		go listener.listenMap(m, changesChan, handle)
+	}
+
+	return nil
+}
+
+func (listener *profileTPDOListener) listenMap(m *PDOMap, changesChan *PDOMapChangeChan, handle func(*PDOMap, []byte)) {
+	for {
+		select {
+		case <-listener.chanChanStop:
+			return
+		case data, ok := <-changesChan.C:
+			if !ok {
+				return
+			}
+
+			handle(m, data)
+		}
+	}
+}
+
+// Unlisten stop forwarding TPDOs data
+func (listener *profileTPDOListener) Unlisten() {
+	listener.Lock()
+	defer listener.Unlock()
+
+	if !listener.listening {
+		return
+	}
+
+	close(listener.chanChanStop)
+
+	for m, changesChan := range listener.pdoChans {
+		m.ReleaseChangesChan(changesChan.ID)
+	}
+
+	listener.pdoChans = nil
+	listener.listening = false
+}
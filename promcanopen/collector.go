@@ -0,0 +1,95 @@
+// Package promcanopen export canopen network metrics to Prometheus
+package promcanopen
+
+import (
+	"fmt"
+	"strconv"
+
+	canopen "github.com/angelodlfrtr/go-canopen"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	framesDesc = prometheus.NewDesc(
+		"canopen_frames_total", "Received frames by service.", []string{"service"}, nil)
+	framesPerSecondDesc = prometheus.NewDesc(
+		"canopen_frames_per_second", "Received frames during the last complete second by service.", []string{"service"}, nil)
+	droppedFramesDesc = prometheus.NewDesc(
+		"canopen_frames_dropped_total", "Frames not delivered to a busy network frames chan.", nil, nil)
+	sdoLatencyDesc = prometheus.NewDesc(
+		"canopen_sdo_latency_seconds", "SDO transfers duration by node.", []string{"node"}, nil)
+	sdoAbortsDesc = prometheus.NewDesc(
+		"canopen_sdo_aborts_total", "Aborted SDO transfers by node.", []string{"node"}, nil)
+	sdoTimeoutsDesc = prometheus.NewDesc(
+		"canopen_sdo_timeouts_total", "Timed out SDO transfers by node.", []string{"node"}, nil)
+	heartbeatAgeDesc = prometheus.NewDesc(
+		"canopen_heartbeat_age_seconds", "Time since the last heartbeat by node.", []string{"node"}, nil)
+	pdoFramesDesc = prometheus.NewDesc(
+		"canopen_pdo_frames_total", "Received PDO frames by COB-ID.", []string{"cob_id"}, nil)
+	pdoPeriodDesc = prometheus.NewDesc(
+		"canopen_pdo_period_seconds", "Smoothed PDO reception period by COB-ID.", []string{"cob_id"}, nil)
+	pdoJitterDesc = prometheus.NewDesc(
+		"canopen_pdo_jitter_seconds", "Smoothed PDO reception jitter by COB-ID.", []string{"cob_id"}, nil)
+)
+
+// Collector is a prometheus.Collector of canopen metrics
+type Collector struct {
+	Source canopen.MetricsSource
+}
+
+// NewCollector return a Collector of source, eg: canopen.NewMetrics(network)
+func NewCollector(source canopen.MetricsSource) *Collector {
+	return &Collector{Source: source}
+}
+
+// Describe implement prometheus.Collector
+func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		framesDesc, framesPerSecondDesc, droppedFramesDesc,
+		sdoLatencyDesc, sdoAbortsDesc, sdoTimeoutsDesc,
+		heartbeatAgeDesc, pdoFramesDesc, pdoPeriodDesc, pdoJitterDesc,
+	} {
+		ch <- desc
+	}
+}
+
+// Collect implement prometheus.Collector
+func (collector *Collector) Collect(ch chan<- prometheus.Metric) {
+	snapshot := collector.Source.Snapshot()
+
+	for service, count := range snapshot.Frames {
+		ch <- prometheus.MustNewConstMetric(framesDesc, prometheus.CounterValue, float64(count), string(service))
+	}
+
+	for service, rate := range snapshot.FramesPerSecond {
+		ch <- prometheus.MustNewConstMetric(framesPerSecondDesc, prometheus.GaugeValue, rate, string(service))
+	}
+
+	ch <- prometheus.MustNewConstMetric(droppedFramesDesc, prometheus.CounterValue, float64(snapshot.DroppedFrames))
+
+	for nodeID, sdo := range snapshot.SDO {
+		node := strconv.Itoa(nodeID)
+
+		buckets := map[float64]uint64{}
+		for i, bound := range sdo.Latency.Bounds {
+			buckets[bound.Seconds()] = sdo.Latency.Counts[i]
+		}
+
+		ch <- prometheus.MustNewConstHistogram(
+			sdoLatencyDesc, sdo.Latency.Count, sdo.Latency.Sum.Seconds(), buckets, node)
+		ch <- prometheus.MustNewConstMetric(sdoAbortsDesc, prometheus.CounterValue, float64(sdo.Aborts), node)
+		ch <- prometheus.MustNewConstMetric(sdoTimeoutsDesc, prometheus.CounterValue, float64(sdo.Timeouts), node)
+	}
+
+	for nodeID, age := range snapshot.HeartbeatAge {
+		ch <- prometheus.MustNewConstMetric(heartbeatAgeDesc, prometheus.GaugeValue, age.Seconds(), strconv.Itoa(nodeID))
+	}
+
+	for cobID, pdo := range snapshot.PDO {
+		id := fmt.Sprintf("0x%03X", cobID)
+
+		ch <- prometheus.MustNewConstMetric(pdoFramesDesc, prometheus.CounterValue, float64(pdo.Frames), id)
+		ch <- prometheus.MustNewConstMetric(pdoPeriodDesc, prometheus.GaugeValue, pdo.Period.Seconds(), id)
+		ch <- prometheus.MustNewConstMetric(pdoJitterDesc, prometheus.GaugeValue, pdo.Jitter.Seconds(), id)
+	}
+}
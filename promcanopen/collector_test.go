@@ -0,0 +1,76 @@
+package promcanopen
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	canopen "github.com/angelodlfrtr/go-canopen"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// testSource is a canopen.MetricsSource of a fixed snapshot
+type testSource canopen.MetricsSnapshot
+
+func (source testSource) Snapshot() canopen.MetricsSnapshot {
+	return canopen.MetricsSnapshot(source)
+}
+
+func TestCollector(t *testing.T) {
+	source := testSource{
+		Frames:          map[canopen.MonitorEventType]uint64{canopen.MonitorPDO: 10, canopen.MonitorSDO: 4},
+		FramesPerSecond: map[canopen.MonitorEventType]float64{canopen.MonitorPDO: 2},
+		DroppedFrames:   1,
+		SDO: map[int]canopen.MetricsSDO{5: {
+			Latency: canopen.MetricsHistogram{
+				Count:  2,
+				Sum:    3 * time.Millisecond,
+				Bounds: []time.Duration{time.Millisecond, 10 * time.Millisecond},
+				Counts: []uint64{1, 2},
+			},
+			Aborts: 1,
+		}},
+		HeartbeatAge: map[int]time.Duration{5: 500 * time.Millisecond},
+		PDO:          map[uint32]canopen.MetricsPDO{0x185: {Frames: 10, Period: 10 * time.Millisecond}},
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(NewCollector(source)); err != nil {
+		t.Fatal(err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, family := range families {
+		names = append(names, family.GetName())
+
+		switch family.GetName() {
+		case "canopen_sdo_latency_seconds":
+			histogram := family.GetMetric()[0].GetHistogram()
+			if histogram.GetSampleCount() != 2 || histogram.GetBucket()[0].GetCumulativeCount() != 1 {
+				t.Errorf("unexpected SDO latency %v", histogram)
+			}
+		case "canopen_pdo_period_seconds":
+			metric := family.GetMetric()[0]
+			if metric.GetLabel()[0].GetValue() != "0x185" || metric.GetGauge().GetValue() != 0.01 {
+				t.Errorf("unexpected PDO period %v", metric)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	expected := "canopen_frames_dropped_total canopen_frames_per_second canopen_frames_total " +
+		"canopen_heartbeat_age_seconds canopen_pdo_frames_total canopen_pdo_jitter_seconds " +
+		"canopen_pdo_period_seconds canopen_sdo_aborts_total canopen_sdo_latency_seconds " +
+		"canopen_sdo_timeouts_total"
+
+	if strings.Join(names, " ") != expected {
+		t.Errorf("unexpected metrics %v", names)
+	}
+}
@@ -0,0 +1,320 @@
+package canopen
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angelodlfrtr/go-can"
+)
+
+// RecordFormat is a bus traffic log format
+type RecordFormat string
+
+// Record formats
+const (
+	// RecordFormatCandump is the can-utils candump -L format, eg:
+	// (1602500000.123456) can0 705#05
+	RecordFormatCandump RecordFormat = "candump"
+	// RecordFormatASC is the Vector ASCII log format
+	RecordFormatASC RecordFormat = "asc"
+)
+
+// recordASCDateLayout is the date layout of ASC headers
+const recordASCDateLayout = "Mon Jan 2 03:04:05.000 pm 2006"
+
+// ParseRecordFormat return the format named s
+func ParseRecordFormat(s string) (RecordFormat, error) {
+	switch format := RecordFormat(strings.ToLower(s)); format {
+	case RecordFormatCandump, RecordFormatASC:
+		return format, nil
+	}
+
+	return "", fmt.Errorf("unknown record format %q", s)
+}
+
+// RecordFormatFromPath return the format of a log file from its extension,
+// ASC for .asc files, candump otherwise
+func RecordFormatFromPath(path string) RecordFormat {
+	if strings.ToLower(filepath.Ext(path)) == ".asc" {
+		return RecordFormatASC
+	}
+
+	return RecordFormatCandump
+}
+
+// RecordedFrame is a frame with its reception time
+type RecordedFrame struct {
+	Time  time.Time
+	Frame *can.Frame
+}
+
+// RecordWriter write frames to W in Format
+type RecordWriter struct {
+	W      io.Writer
+	Format RecordFormat
+
+	// Interface is the candump interface name, default to can0
+	Interface string
+
+	start      time.Time
+	headerDone bool
+}
+
+// NewRecordWriter return a RecordWriter
+func NewRecordWriter(w io.Writer, format RecordFormat) *RecordWriter {
+	return &RecordWriter{W: w, Format: format, Interface: "can0"}
+}
+
+// WriteFrame write frm received at t
+func (writer *RecordWriter) WriteFrame(t time.Time, frm *can.Frame) error {
+	switch writer.Format {
+	case RecordFormatCandump:
+		_, err := fmt.Fprintf(
+			writer.W, "(%d.%06d) %s %s#%X\n",
+			t.Unix(), t.Nanosecond()/1000, writer.Interface, recordFormatID(frm.ArbitrationID, 3), frm.GetData(),
+		)
+
+		return err
+	case RecordFormatASC:
+		if !writer.headerDone {
+			writer.headerDone = true
+			// Header date has a millisecond resolution
+			writer.start = t.Truncate(time.Millisecond)
+
+			date := t.Format(recordASCDateLayout)
+			if _, err := fmt.Fprintf(
+				writer.W,
+				"date %s\nbase hex  timestamps absolute\nno internal events logged\nBegin Triggerblock %s\n   0.000000 Start of measurement\n",
+				date, date,
+			); err != nil {
+				return err
+			}
+		}
+
+		id := recordFormatID(frm.ArbitrationID, 1)
+		if frm.ArbitrationID > 0x7FF {
+			id += "x"
+		}
+
+		data := make([]string, 0, frm.DLC)
+		for _, b := range frm.GetData() {
+			data = append(data, fmt.Sprintf("%02X", b))
+		}
+
+		_, err := fmt.Fprintf(
+			writer.W, "%11.6f 1  %-15s Rx   d %d %s\n",
+			t.Sub(writer.start).Seconds(), id, frm.DLC, strings.Join(data, " "),
+		)
+
+		return err
+	}
+
+	return errors.New("unknown record format")
+}
+
+// Close write the log footer, if any
+func (writer *RecordWriter) Close() error {
+	if writer.Format == RecordFormatASC && writer.headerDone {
+		_, err := fmt.Fprintln(writer.W, "End TriggerBlock")
+		return err
+	}
+
+	return nil
+}
+
+// recordFormatID format an arbitration id, with 8 digits for extended ids
+func recordFormatID(id uint32, digits int) string {
+	if id > 0x7FF {
+		digits = 8
+	}
+
+	return fmt.Sprintf("%0*X", digits, id)
+}
+
+// ReadRecord read frames logged in format
+func ReadRecord(r io.Reader, format RecordFormat) ([]RecordedFrame, error) {
+	switch format {
+	case RecordFormatCandump:
+		return readCandumpRecord(r)
+	case RecordFormatASC:
+		return readASCRecord(r)
+	}
+
+	return nil, errors.New("unknown record format")
+}
+
+// ReadRecordFile read frames of a log file, see RecordFormatFromPath
+func ReadRecordFile(path string) ([]RecordedFrame, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return ReadRecord(file, RecordFormatFromPath(path))
+}
+
+// readCandumpRecord read candump -L lines, eg: (1602500000.123456) can0 705#05
+func readCandumpRecord(r io.Reader) ([]RecordedFrame, error) {
+	frames := []RecordedFrame{}
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if len(fields) < 3 || !strings.HasPrefix(fields[0], "(") || !strings.HasSuffix(fields[0], ")") {
+			return nil, fmt.Errorf("line %d: invalid candump line", line)
+		}
+
+		t, err := parseRecordTimestamp(strings.Trim(fields[0], "()"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		idStr, dataStr, ok := strings.Cut(fields[2], "#")
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid frame %q", line, fields[2])
+		}
+
+		id, err := strconv.ParseUint(idStr, 16, 29)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid id %q", line, idStr)
+		}
+
+		// Remote frames have no data
+		if strings.HasPrefix(dataStr, "R") {
+			dataStr = ""
+		}
+
+		if len(dataStr)%2 != 0 || len(dataStr) > 16 {
+			return nil, fmt.Errorf("line %d: invalid data %q", line, dataStr)
+		}
+
+		frm := &can.Frame{ArbitrationID: uint32(id), DLC: uint8(len(dataStr) / 2)}
+		for i := 0; i < int(frm.DLC); i++ {
+			b, err := strconv.ParseUint(dataStr[2*i:2*i+2], 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid data %q", line, dataStr)
+			}
+
+			frm.Data[i] = byte(b)
+		}
+
+		frames = append(frames, RecordedFrame{Time: t, Frame: frm})
+	}
+
+	return frames, scanner.Err()
+}
+
+// parseRecordTimestamp parse seconds.microseconds since epoch
+func parseRecordTimestamp(s string) (time.Time, error) {
+	secStr, fracStr, _ := strings.Cut(s, ".")
+
+	sec, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+	}
+
+	// Fraction padded to nanoseconds
+	var nsec int64
+	if fracStr != "" {
+		if len(fracStr) > 9 {
+			fracStr = fracStr[:9]
+		}
+
+		nsec, err = strconv.ParseInt(fracStr+strings.Repeat("0", 9-len(fracStr)), 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+		}
+	}
+
+	return time.Unix(sec, nsec), nil
+}
+
+// readASCRecord read CAN frames lines of a Vector ASC log, other lines and
+// events are ignored
+func readASCRecord(r io.Reader) ([]RecordedFrame, error) {
+	frames := []RecordedFrame{}
+	scanner := bufio.NewScanner(r)
+
+	start := time.Unix(0, 0)
+	base := 16
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		fields := strings.Fields(text)
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "date":
+			if t, err := time.ParseInLocation(recordASCDateLayout, strings.TrimSpace(text[len("date"):]), time.Local); err == nil {
+				start = t
+			}
+
+			continue
+		case "base":
+			if len(fields) > 1 && fields[1] == "dec" {
+				base = 10
+			}
+
+			continue
+		}
+
+		// Frames lines: time channel id Rx|Tx d dlc data...
+		if len(fields) < 6 || (fields[3] != "Rx" && fields[3] != "Tx") || fields[4] != "d" {
+			continue
+		}
+
+		offset, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+
+		extended := strings.HasSuffix(strings.ToLower(fields[2]), "x")
+		idStr := strings.TrimRight(fields[2], "xX")
+
+		id, err := strconv.ParseUint(idStr, base, 29)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid id %q", line, fields[2])
+		}
+
+		if !extended && id > 0x7FF {
+			return nil, fmt.Errorf("line %d: invalid standard id %q", line, fields[2])
+		}
+
+		dlc, err := strconv.ParseUint(fields[5], 16, 8)
+		if err != nil || dlc > 8 || len(fields) < 6+int(dlc) {
+			return nil, fmt.Errorf("line %d: invalid dlc %q", line, fields[5])
+		}
+
+		frm := &can.Frame{ArbitrationID: uint32(id), DLC: uint8(dlc)}
+		for i := 0; i < int(dlc); i++ {
+			b, err := strconv.ParseUint(fields[6+i], base, 8)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid data %q", line, fields[6+i])
+			}
+
+			frm.Data[i] = byte(b)
+		}
+
+		t := start.Add(time.Duration(math.Round(offset*1e6)) * time.Microsecond)
+		frames = append(frames, RecordedFrame{Time: t, Frame: frm})
+	}
+
+	return frames, scanner.Err()
+}
@@ -0,0 +1,86 @@
+package canopen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angelodlfrtr/go-can"
+)
+
+func TestRecordFormats(t *testing.T) {
+	start := time.Date(2020, 10, 12, 14, 30, 0, 250000000, time.Local)
+	frames := []RecordedFrame{
+		{Time: start, Frame: &can.Frame{ArbitrationID: 0x705, DLC: 1, Data: [8]byte{0x05}}},
+		{Time: start.Add(1500 * time.Microsecond), Frame: &can.Frame{ArbitrationID: 0x080}},
+		{Time: start.Add(2 * time.Second), Frame: &can.Frame{ArbitrationID: 0x18FF0001, DLC: 8, Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}},
+	}
+
+	for _, format := range []RecordFormat{RecordFormatCandump, RecordFormatASC} {
+		out := &bytes.Buffer{}
+		writer := NewRecordWriter(out, format)
+
+		for _, frame := range frames {
+			if err := writer.WriteFrame(frame.Time, frame.Frame); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		read, err := ReadRecord(out, format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+
+		if len(read) != len(frames) {
+			t.Fatalf("%s: expected %d frames, got %d", format, len(frames), len(read))
+		}
+
+		for i, frame := range read {
+			if !frame.Time.Equal(frames[i].Time) || *frame.Frame != *frames[i].Frame {
+				t.Errorf("%s: unexpected frame %d %v %v", format, i, frame.Time, frame.Frame)
+			}
+		}
+	}
+}
+
+func TestReadRecord(t *testing.T) {
+	candump := "(1602513000.000100) can0 705#05\n(1602513000.5) vcan0 123#R\n"
+
+	frames, err := ReadRecord(strings.NewReader(candump), RecordFormatCandump)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(frames) != 2 || frames[1].Frame.ArbitrationID != 0x123 || frames[1].Frame.DLC != 0 || frames[1].Time.Sub(frames[0].Time) != 499900*time.Microsecond {
+		t.Errorf("unexpected candump frames %+v", frames)
+	}
+
+	if _, err := ReadRecord(strings.NewReader("(1.0) can0 705#0\n"), RecordFormatCandump); err == nil {
+		t.Error("expected invalid data error")
+	}
+
+	asc := `date Mon Oct 12 02:30:00.000 pm 2020
+base dec  timestamps absolute
+Begin Triggerblock Mon Oct 12 02:30:00.000 pm 2020
+   0.000000 Start of measurement
+   0.010000 1  Statistic: D 0 R 0 XD 0 XR 0 E 0 O 0 B 0.00%
+   0.020000 1  1797            Rx   d 1 5
+   0.030000 CAN 1 Status:chip status error active
+End TriggerBlock
+`
+
+	frames, err = ReadRecord(strings.NewReader(asc), RecordFormatASC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := time.Date(2020, 10, 12, 14, 30, 0, 20000000, time.Local)
+	if len(frames) != 1 || frames[0].Frame.ArbitrationID != 0x705 || frames[0].Frame.Data[0] != 5 || !frames[0].Time.Equal(expected) {
+		t.Errorf("unexpected ASC frames %+v", frames)
+	}
+}
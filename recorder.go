@@ -0,0 +1,100 @@
+package canopen
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// Recorder write frames received by a network to a log
+type Recorder struct {
+	sync.Mutex
+
+	Network *Network
+	Writer  *RecordWriter
+
+	framesChanID string
+	done         chan struct{}
+	err          error
+}
+
+// NewRecorder return a Recorder of network frames to w in format
+func NewRecorder(network *Network, w io.Writer, format RecordFormat) *Recorder {
+	return &Recorder{
+		Network: network,
+		Writer:  NewRecordWriter(w, format),
+	}
+}
+
+// Start recording
+func (recorder *Recorder) Start() error {
+	recorder.Lock()
+	defer recorder.Unlock()
+
+	if recorder.done != nil {
+		return errors.New("already recording")
+	}
+
+	framesChan := recorder.Network.AcquireFramesChan(nil)
+	recorder.framesChanID = framesChan.ID
+	recorder.done = make(chan struct{})
+
+	// Network drop frames of busy chans, frames are buffered while written
+	frames := make(chan RecordedFrame, 1024)
+
+	go func() {
+		defer close(frames)
+
+		for frm := range framesChan.C {
+			select {
+			case frames <- RecordedFrame{Time: time.Now(), Frame: frm}:
+			default:
+			}
+		}
+	}()
+
+	go func() {
+		defer close(recorder.done)
+
+		for frame := range frames {
+			if err := recorder.Writer.WriteFrame(frame.Time, frame.Frame); err != nil {
+				recorder.Lock()
+				if recorder.err == nil {
+					recorder.err = err
+				}
+				recorder.Unlock()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop recording, and return the first write error if any
+func (recorder *Recorder) Stop() error {
+	recorder.Lock()
+	done := recorder.done
+	recorder.Unlock()
+
+	if done == nil {
+		return errors.New("not recording")
+	}
+
+	recorder.Network.ReleaseFramesChan(recorder.framesChanID)
+	<-done
+
+	recorder.Lock()
+	defer recorder.Unlock()
+
+	recorder.done = nil
+
+	if err := recorder.Writer.Close(); err != nil && recorder.err == nil {
+		recorder.err = err
+	}
+
+	err := recorder.err
+	recorder.err = nil
+
+	return err
+}
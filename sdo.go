@@ -2,6 +2,7 @@ package canopen
 
 import (
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/angelodlfrtr/go-can"
@@ -24,12 +25,24 @@ const (
 	SDONoMoreData    uint8 = 0x1
 )
 
+// ErrSDOTimeout is returned when the SDO server does not answer
+var ErrSDOTimeout = errors.New("timeout execeded")
+
 // SDOClient represent an SDO client
 type SDOClient struct {
 	Node      *Node
 	RXCobID   uint32
 	TXCobID   uint32
 	SendQueue []string
+
+	// Timeout of the first attempt of a request, doubled on each retry, and
+	// number of attempts. Defaults to 500ms and 4 when zero
+	Timeout time.Duration
+	Retries int
+
+	// Strict refuse reads of write only objects and writes of read only or
+	// constant objects of node object dictionary, without using the bus
+	Strict bool
 }
 
 func NewSDOClient(node *Node) *SDOClient {
@@ -43,6 +56,7 @@ func NewSDOClient(node *Node) *SDOClient {
 
 // SendRequest to network bus
 func (sdoClient *SDOClient) SendRequest(req []byte) error {
+	sdoClient.Node.trace("SDO request", "cobId", sdoClient.RXCobID, "data", logData(req))
 	return sdoClient.Node.Network.Send(sdoClient.RXCobID, req)
 }
 
@@ -75,15 +89,28 @@ func (sdoClient *SDOClient) Send(
 	// Set default timeout
 	if timeout == nil {
 		dtm := time.Duration(500) * time.Millisecond
+		if sdoClient.Timeout > 0 {
+			dtm = sdoClient.Timeout
+		}
+
 		timeout = &dtm
 	}
 
 	if retryCount == nil {
 		rtc := 4
+		if sdoClient.Retries > 0 {
+			rtc = sdoClient.Retries
+		}
+
 		retryCount = &rtc
 	}
 
-	framesChan := sdoClient.Node.Network.AcquireFramesChan(expectFunc)
+	// Accept expected responses and aborts from the server only
+	filterFunc := func(frm *can.Frame) bool {
+		return frm.Data[0] == SDOResponseAbort || (*expectFunc)(frm)
+	}
+
+	framesChan := sdoClient.Node.Network.AcquireCobIDsFramesChan(&filterFunc, sdoClient.TXCobID)
 
 	// Retry loop
 	remainingCount := *retryCount
@@ -122,20 +149,103 @@ func (sdoClient *SDOClient) Send(
 
 	// If no frm, timeout execeded
 	if frm == nil {
-		return nil, errors.New("timeout execeded")
+		sdoClient.Node.trace("SDO timeout", "cobId", sdoClient.TXCobID, "attempts", *retryCount)
+		return nil, ErrSDOTimeout
+	}
+
+	sdoClient.Node.trace("SDO response", "cobId", frm.ArbitrationID, "data", logData(frm.GetData()))
+
+	if frm.Data[0] == SDOResponseAbort {
+		return nil, newSDOAbortError(frm.Data)
 	}
 
 	return frm, nil
 }
 
-// Read sdo
+// checkAccess check index / subIndex access in node object dictionary, in strict mode
+func (sdoClient *SDOClient) checkAccess(index uint16, subIndex uint8, write bool) error {
+	if !sdoClient.Strict || sdoClient.Node == nil || sdoClient.Node.ObjectDic == nil {
+		return nil
+	}
+
+	if variable := sdoClient.Node.ObjectDic.FindVariable(index, subIndex); variable != nil {
+		return variable.CheckAccess(write)
+	}
+
+	return nil
+}
+
+// lockChannel wait for transfers on sdoClient SDO channel to end, and return
+// the func releasing it. Transfers are serialized per request COB-ID on the
+// network, so clients of the same node, or copies of a client, share the channel
+func (sdoClient *SDOClient) lockChannel() func() {
+	if sdoClient.Node == nil || sdoClient.Node.Network == nil {
+		return func() {}
+	}
+
+	channel := sdoClient.Node.Network.sdoChannel(sdoClient.RXCobID)
+	channel.Lock()
+
+	return channel.Unlock
+}
+
+// sdoChannel return the mutex of the SDO channel of request cobID
+func (network *Network) sdoChannel(cobID uint32) *sync.Mutex {
+	network.Lock()
+	defer network.Unlock()
+
+	if network.sdoChannels == nil {
+		network.sdoChannels = map[uint32]*sync.Mutex{}
+	}
+
+	channel, ok := network.sdoChannels[cobID]
+	if !ok {
+		channel = &sync.Mutex{}
+		network.sdoChannels[cobID] = channel
+	}
+
+	return channel
+}
+
+// Read sdo. Transfers on the same SDO channel are serialized
 func (sdoClient *SDOClient) Read(index uint16, subIndex uint8) ([]byte, error) {
+	if err := sdoClient.checkAccess(index, subIndex, false); err != nil {
+		return nil, err
+	}
+
+	unlock := sdoClient.lockChannel()
+	defer unlock()
+
+	start := time.Now()
 	reader := NewSDOReader(sdoClient, index, subIndex)
-	return reader.ReadAll()
+	data, err := reader.ReadAll()
+	sdoClient.observe(start, err)
+
+	return data, err
 }
 
-// Write sdo
+// Write sdo. Transfers on the same SDO channel are serialized
 func (sdoClient *SDOClient) Write(index uint16, subIndex uint8, forceSegment bool, data []byte) error {
+	if err := sdoClient.checkAccess(index, subIndex, true); err != nil {
+		return err
+	}
+
+	unlock := sdoClient.lockChannel()
+	defer unlock()
+
+	start := time.Now()
 	writer := NewSDOWriter(sdoClient, index, subIndex, forceSegment)
-	return writer.Write(data)
+	err := writer.Write(data)
+	sdoClient.observe(start, err)
+
+	return err
+}
+
+// observe add a transfer started at start to the network metrics, if enabled
+func (sdoClient *SDOClient) observe(start time.Time, err error) {
+	if sdoClient.Node == nil || sdoClient.Node.Network == nil || sdoClient.Node.Network.Metrics == nil {
+		return
+	}
+
+	sdoClient.Node.Network.Metrics.observeSDO(sdoClient.Node.ID, time.Since(start), err)
 }
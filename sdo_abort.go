@@ -0,0 +1,91 @@
+package canopen
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// SDOResponseAbort is the command of an abort transfer frame
+const SDOResponseAbort uint8 = 4 << 5
+
+// SDO abort codes
+const (
+	SDOAbortToggleBit         uint32 = 0x05030000
+	SDOAbortTimeout           uint32 = 0x05040000
+	SDOAbortInvalidCommand    uint32 = 0x05040001
+	SDOAbortOutOfMemory       uint32 = 0x05040005
+	SDOAbortUnsupportedAccess uint32 = 0x06010000
+	SDOAbortWriteOnly         uint32 = 0x06010001
+	SDOAbortReadOnly          uint32 = 0x06010002
+	SDOAbortObjectNotExist    uint32 = 0x06020000
+	SDOAbortNotMappable       uint32 = 0x06040041
+	SDOAbortPDOLength         uint32 = 0x06040042
+	SDOAbortIncompatible      uint32 = 0x06040043
+	SDOAbortHardware          uint32 = 0x06060000
+	SDOAbortTypeMismatch      uint32 = 0x06070010
+	SDOAbortTypeTooLong       uint32 = 0x06070012
+	SDOAbortTypeTooShort      uint32 = 0x06070013
+	SDOAbortSubIndexNotExist  uint32 = 0x06090011
+	SDOAbortInvalidValue      uint32 = 0x06090030
+	SDOAbortValueTooHigh      uint32 = 0x06090031
+	SDOAbortValueTooLow       uint32 = 0x06090032
+	SDOAbortGeneral           uint32 = 0x08000000
+	SDOAbortDataTransfer      uint32 = 0x08000020
+	SDOAbortLocalControl      uint32 = 0x08000021
+	SDOAbortDeviceState       uint32 = 0x08000022
+	SDOAbortNoObjectDic       uint32 = 0x08000023
+	SDOAbortNoData            uint32 = 0x08000024
+)
+
+var sdoAbortDescriptions = map[uint32]string{
+	SDOAbortToggleBit:         "toggle bit not alternated",
+	SDOAbortTimeout:           "SDO protocol timed out",
+	SDOAbortInvalidCommand:    "client/server command specifier not valid or unknown",
+	SDOAbortOutOfMemory:       "out of memory",
+	SDOAbortUnsupportedAccess: "unsupported access to an object",
+	SDOAbortWriteOnly:         "attempt to read a write only object",
+	SDOAbortReadOnly:          "attempt to write a read only object",
+	SDOAbortObjectNotExist:    "object does not exist in the object dictionary",
+	SDOAbortNotMappable:       "object cannot be mapped to the PDO",
+	SDOAbortPDOLength:         "number and length of objects to be mapped exceed PDO length",
+	SDOAbortIncompatible:      "general parameter incompatibility",
+	SDOAbortHardware:          "access failed due to a hardware error",
+	SDOAbortTypeMismatch:      "data type does not match, length of service parameter does not match",
+	SDOAbortTypeTooLong:       "data type does not match, length of service parameter too high",
+	SDOAbortTypeTooShort:      "data type does not match, length of service parameter too low",
+	SDOAbortSubIndexNotExist:  "sub-index does not exist",
+	SDOAbortInvalidValue:      "invalid value for parameter",
+	SDOAbortValueTooHigh:      "value of parameter written too high",
+	SDOAbortValueTooLow:       "value of parameter written too low",
+	SDOAbortGeneral:           "general error",
+	SDOAbortDataTransfer:      "data cannot be transferred or stored to the application",
+	SDOAbortLocalControl:      "data cannot be transferred or stored to the application because of local control",
+	SDOAbortDeviceState:       "data cannot be transferred or stored to the application because of the present device state",
+	SDOAbortNoObjectDic:       "object dictionary dynamic generation fails or no object dictionary is present",
+	SDOAbortNoData:            "no data available",
+}
+
+// SDOAbortError is returned when the SDO server abort a transfer
+type SDOAbortError struct {
+	Index    uint16
+	SubIndex uint8
+	Code     uint32
+}
+
+func (e *SDOAbortError) Error() string {
+	des, ok := sdoAbortDescriptions[e.Code]
+	if !ok {
+		des = "unknown abort code"
+	}
+
+	return fmt.Sprintf("SDO abort 0x%08X on 0x%04X sub %d: %s", e.Code, e.Index, e.SubIndex, des)
+}
+
+// newSDOAbortError return the abort error of an abort frame data
+func newSDOAbortError(data [8]byte) *SDOAbortError {
+	return &SDOAbortError{
+		Index:    binary.LittleEndian.Uint16(data[1:]),
+		SubIndex: data[3],
+		Code:     binary.LittleEndian.Uint32(data[4:]),
+	}
+}
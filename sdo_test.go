@@ -0,0 +1,50 @@
+package canopen
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSDOClientConcurrentTransfers(t *testing.T) {
+	network, transport := newTestNetwork(t)
+	server := newFakeSDOServer(transport, 5)
+
+	node := NewNode(5, network, nil)
+	node.SDOClient = NewSDOClient(node)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+
+	for i := 0; i < 8; i++ {
+		value := []byte(fmt.Sprintf("Segmented value %d", i))
+		server.Set(0x2000+uint16(i), 0, value)
+
+		// Copies of the node client, eg: with another timeout, share its channel
+		client := *node.SDOClient
+		client.Timeout = time.Second
+
+		wg.Add(1)
+		go func(index uint16, value []byte) {
+			defer wg.Done()
+
+			data, err := client.Read(index, 0)
+			if err == nil && !bytes.Equal(data, value) {
+				err = fmt.Errorf("0x%04X: unexpected value %q", index, data)
+			}
+
+			errs <- err
+		}(0x2000+uint16(i), value)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
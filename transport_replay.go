@@ -0,0 +1,158 @@
+package canopen
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/angelodlfrtr/go-can"
+)
+
+// ReplayTransport is a can.Transport feeding recorded frames, eg: from
+// ReadRecordFile. Frames written to the transport are discarded
+type ReplayTransport struct {
+	sync.Mutex
+
+	Frames []RecordedFrame
+
+	// Speed scale the recorded timing, 2 replay twice faster. With 0 frames
+	// are replayed without delay
+	Speed float64
+
+	// readChan is never closed, as the network read it until stopped
+	readChan chan *can.Frame
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+// NewReplayTransport return a transport replaying frames at speed
+func NewReplayTransport(frames []RecordedFrame, speed float64) *ReplayTransport {
+	return &ReplayTransport{
+		Frames:   frames,
+		Speed:    speed,
+		readChan: make(chan *can.Frame),
+		done:     make(chan struct{}),
+	}
+}
+
+// Open start replaying frames
+func (t *ReplayTransport) Open() error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.stopChan != nil {
+		return nil
+	}
+
+	t.stopChan = make(chan struct{})
+
+	select {
+	case <-t.done:
+		// Replayed again
+		t.done = make(chan struct{})
+	default:
+	}
+
+	go t.replay(t.stopChan, t.done)
+
+	return nil
+}
+
+// replay frames until all are sent or stopChan is closed
+func (t *ReplayTransport) replay(stopChan, done chan struct{}) {
+	defer close(done)
+
+	if len(t.Frames) == 0 {
+		return
+	}
+
+	start := time.Now()
+	first := t.Frames[0].Time
+
+	for _, frame := range t.Frames {
+		if t.Speed > 0 {
+			at := start.Add(time.Duration(float64(frame.Time.Sub(first)) / t.Speed))
+
+			if wait := time.Until(at); wait > 0 {
+				timer := time.NewTimer(wait)
+
+				select {
+				case <-stopChan:
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+		}
+
+		frm := *frame.Frame
+
+		select {
+		case <-stopChan:
+			return
+		case t.readChan <- &frm:
+		}
+	}
+}
+
+// Close stop replaying frames
+func (t *ReplayTransport) Close() error {
+	t.Lock()
+	stopChan, done := t.stopChan, t.done
+	t.stopChan = nil
+	t.Unlock()
+
+	if stopChan == nil {
+		return nil
+	}
+
+	close(stopChan)
+	<-done
+
+	return nil
+}
+
+// Write discard frm, as the recorded bus cannot answer
+func (t *ReplayTransport) Write(frm *can.Frame) error {
+	return nil
+}
+
+// ReadChan return the channel of replayed frames
+func (t *ReplayTransport) ReadChan() chan *can.Frame {
+	return t.readChan
+}
+
+// Done return a channel closed once all frames are replayed, or the transport closed
+func (t *ReplayTransport) Done() <-chan struct{} {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.done
+}
+
+// newReplayTransport create a replay transport from replay:///path/to/file.log?speed=1
+func newReplayTransport(uri *url.URL) (can.Transport, error) {
+	path := transportURIPath(uri)
+	if path == "" {
+		return nil, fmt.Errorf("missing log file in %q", uri)
+	}
+
+	speed := 1.0
+	if s := uri.Query().Get("speed"); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid speed %q", s)
+		}
+
+		speed = v
+	}
+
+	frames, err := ReadRecordFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReplayTransport(frames, speed), nil
+}
@@ -0,0 +1,83 @@
+package canopen
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/angelodlfrtr/go-can"
+)
+
+func TestReplayTransport(t *testing.T) {
+	log := "(1602513000.000000) can0 705#7F\n(1602513000.020000) can0 080#\n(1602513000.040000) can0 705#05\n"
+
+	path := filepath.Join(t.TempDir(), "capture.log")
+	if err := os.WriteFile(path, []byte(log), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replayed twice faster
+	tr, err := NewTransport("replay://" + path + "?speed=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transport := tr.(*ReplayTransport)
+	network, err := NewNetwork(can.Bus{Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := network.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer network.Stop()
+
+	out := &bytes.Buffer{}
+	recorder := NewRecorder(network, out, RecordFormatCandump)
+	if err := recorder.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Give the recorder some time to wait on its frames chan, as network
+	// publish is not blocking
+	time.Sleep(5 * time.Millisecond)
+
+	start := time.Now()
+	if err := transport.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-transport.Done():
+	case <-time.After(time.Second):
+		t.Fatal("replay not done")
+	}
+
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed > 200*time.Millisecond {
+		t.Errorf("unexpected replay duration %s", elapsed)
+	}
+
+	// Let the recorder write the last frame
+	time.Sleep(10 * time.Millisecond)
+
+	if err := recorder.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	frames, err := ReadRecord(out, RecordFormatCandump)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(frames) != 3 || frames[2].Frame.ArbitrationID != 0x705 || frames[2].Frame.Data[0] != 0x05 {
+		t.Errorf("unexpected recorded frames %+v", frames)
+	}
+
+	if err := transport.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
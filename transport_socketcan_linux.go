@@ -0,0 +1,276 @@
+//go:build linux
+
+package canopen
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/angelodlfrtr/go-can"
+	"golang.org/x/sys/unix"
+)
+
+// socketCANFrameSize is the size of struct can_frame
+const socketCANFrameSize = 16
+
+// socketCANByteOrder is the host byte order of can_frame ids
+var socketCANByteOrder binary.ByteOrder = binary.LittleEndian
+
+func init() {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 0 {
+		socketCANByteOrder = binary.BigEndian
+	}
+}
+
+// SocketCANTransport is a can.Transport over a Linux CAN_RAW socket. It is a
+// FilterTransport, the network COB-IDs are set as kernel filters
+type SocketCANTransport struct {
+	sync.Mutex
+
+	// Interface is the CAN interface, eg: can0 or vcan0
+	Interface string
+
+	// NoFilters disable kernel filters, all frames are received
+	NoFilters bool
+
+	file     *os.File
+	fd       int
+	stopChan chan struct{}
+	filters  []uint32
+	readChan chan *can.Frame
+}
+
+// NewSocketCANTransport return a transport, not opened, on iface
+func NewSocketCANTransport(iface string) *SocketCANTransport {
+	return &SocketCANTransport{
+		Interface: iface,
+		readChan:  make(chan *can.Frame),
+	}
+}
+
+// Open the socket and bind it to the interface
+func (t *SocketCANTransport) Open() error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.file != nil {
+		return nil
+	}
+
+	iface, err := net.InterfaceByName(t.Interface)
+	if err != nil {
+		return fmt.Errorf("socketcan %s: %v", t.Interface, err)
+	}
+
+	fd, err := unix.Socket(unix.AF_CAN, unix.SOCK_RAW, unix.CAN_RAW)
+	if err != nil {
+		return fmt.Errorf("socketcan %s: %v", t.Interface, err)
+	}
+
+	if err := unix.Bind(fd, &unix.SockaddrCAN{Ifindex: iface.Index}); err != nil {
+		unix.Close(fd)
+		return fmt.Errorf("socketcan %s: %v", t.Interface, err)
+	}
+
+	// Non blocking descriptors use the runtime poller, so Close unblock Read
+	if err := unix.SetNonblock(fd, true); err != nil {
+		unix.Close(fd)
+		return fmt.Errorf("socketcan %s: %v", t.Interface, err)
+	}
+
+	t.fd = fd
+	t.file = os.NewFile(uintptr(fd), t.Interface)
+
+	if t.filters != nil {
+		if err := t.applyFilters(); err != nil {
+			t.file.Close()
+			t.file = nil
+			return err
+		}
+	}
+
+	t.stopChan = make(chan struct{})
+	go t.read(t.file, t.stopChan)
+
+	return nil
+}
+
+// read frames from file until closed. Error frames and remote frames are ignored
+func (t *SocketCANTransport) read(file *os.File, stopChan chan struct{}) {
+	buf := make([]byte, socketCANFrameSize)
+
+	for {
+		n, err := file.Read(buf)
+		if err != nil {
+			if errors.Is(err, os.ErrClosed) {
+				return
+			}
+
+			// Interface down, avoid spinning
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+
+		if n != socketCANFrameSize {
+			continue
+		}
+
+		id := socketCANByteOrder.Uint32(buf)
+		if id&(unix.CAN_ERR_FLAG|unix.CAN_RTR_FLAG) != 0 {
+			continue
+		}
+
+		frm := &can.Frame{DLC: buf[4]}
+		if frm.DLC > 8 {
+			frm.DLC = 8
+		}
+
+		if id&unix.CAN_EFF_FLAG != 0 {
+			frm.ArbitrationID = id & unix.CAN_EFF_MASK
+		} else {
+			frm.ArbitrationID = id & unix.CAN_SFF_MASK
+		}
+
+		copy(frm.Data[:], buf[8:16])
+
+		select {
+		case t.readChan <- frm:
+		case <-stopChan:
+			return
+		}
+	}
+}
+
+// Close the socket. ReadChan is not closed
+func (t *SocketCANTransport) Close() error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.file == nil {
+		return nil
+	}
+
+	close(t.stopChan)
+	err := t.file.Close()
+	t.file = nil
+
+	return err
+}
+
+// Write frm, ids greater than 0x7FF are sent as extended frames
+func (t *SocketCANTransport) Write(frm *can.Frame) error {
+	t.Lock()
+	file := t.file
+	t.Unlock()
+
+	if file == nil {
+		return errors.New("socketcan transport not opened")
+	}
+
+	if frm.DLC > 8 {
+		return fmt.Errorf("invalid DLC %d", frm.DLC)
+	}
+
+	id := frm.ArbitrationID
+	if id > unix.CAN_SFF_MASK {
+		id = id&unix.CAN_EFF_MASK | unix.CAN_EFF_FLAG
+	}
+
+	buf := make([]byte, socketCANFrameSize)
+	socketCANByteOrder.PutUint32(buf, id)
+	buf[4] = frm.DLC
+	copy(buf[8:], frm.Data[:])
+
+	_, err := file.Write(buf)
+	return err
+}
+
+// ReadChan return the channel of received frames
+func (t *SocketCANTransport) ReadChan() chan *can.Frame {
+	return t.readChan
+}
+
+// SetFilters set kernel filters to receive cobIDs only, all frames if cobIDs is nil
+func (t *SocketCANTransport) SetFilters(cobIDs []uint32) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.NoFilters {
+		return nil
+	}
+
+	if cobIDs == nil {
+		t.filters = nil
+	} else {
+		t.filters = append([]uint32{}, cobIDs...)
+	}
+
+	if t.file == nil {
+		return nil
+	}
+
+	return t.applyFilters()
+}
+
+// applyFilters set the socket CAN_RAW_FILTER option. t must be locked
+func (t *SocketCANTransport) applyFilters() error {
+	filters := []unix.CanFilter{}
+
+	if t.filters == nil {
+		// Match all frames
+		filters = append(filters, unix.CanFilter{Id: 0, Mask: 0})
+	}
+
+	for _, cobID := range t.filters {
+		if cobID > unix.CAN_SFF_MASK {
+			filters = append(filters, unix.CanFilter{
+				Id:   cobID&unix.CAN_EFF_MASK | unix.CAN_EFF_FLAG,
+				Mask: unix.CAN_EFF_MASK | unix.CAN_EFF_FLAG | unix.CAN_RTR_FLAG,
+			})
+
+			continue
+		}
+
+		filters = append(filters, unix.CanFilter{
+			Id:   cobID,
+			Mask: unix.CAN_SFF_MASK | unix.CAN_EFF_FLAG | unix.CAN_RTR_FLAG,
+		})
+	}
+
+	if err := unix.SetsockoptCanRawFilter(t.fd, unix.SOL_CAN_RAW, unix.CAN_RAW_FILTER, filters); err != nil {
+		return fmt.Errorf("socketcan %s: set filters: %v", t.Interface, err)
+	}
+
+	return nil
+}
+
+// newSocketCanTransport create a transport from socketcan://can0, with
+// ?filters=false to disable kernel filters
+func newSocketCanTransport(uri *url.URL) (can.Transport, error) {
+	iface := transportURIPath(uri)
+	if iface == "" {
+		return nil, fmt.Errorf("missing interface in %q", uri)
+	}
+
+	t := NewSocketCANTransport(iface)
+
+	if s := uri.Query().Get("filters"); s != "" {
+		filters, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filters %q", s)
+		}
+
+		t.NoFilters = !filters
+	}
+
+	return t, nil
+}
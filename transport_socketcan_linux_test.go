@@ -0,0 +1,132 @@
+//go:build linux
+
+package canopen
+
+import (
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/angelodlfrtr/go-can"
+)
+
+// openTestSocketCAN open a transport on $CANOPEN_VCAN, default to vcan0. The
+// test is skipped without interface, create one with:
+//
+//	modprobe vcan && ip link add dev vcan0 type vcan && ip link set up vcan0
+func openTestSocketCAN(t *testing.T) *SocketCANTransport {
+	iface := os.Getenv("CANOPEN_VCAN")
+	if iface == "" {
+		iface = "vcan0"
+	}
+
+	if _, err := net.InterfaceByName(iface); err != nil {
+		t.Skipf("no %s interface", iface)
+	}
+
+	transport := NewSocketCANTransport(iface)
+	if err := transport.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { transport.Close() })
+
+	return transport
+}
+
+// readTestFrame return the next frame of transport, or nil after a timeout
+func readTestFrame(transport *SocketCANTransport) *can.Frame {
+	select {
+	case frm := <-transport.ReadChan():
+		return frm
+	case <-time.After(50 * time.Millisecond):
+		return nil
+	}
+}
+
+func TestSocketCANTransportURI(t *testing.T) {
+	tr, err := NewTransport("socketcan://can1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s, ok := tr.(*SocketCANTransport); !ok || s.Interface != "can1" || s.NoFilters {
+		t.Errorf("unexpected socketcan transport %#v", tr)
+	}
+
+	tr, err = NewTransport("socketcan://vcan0?filters=false")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s, ok := tr.(*SocketCANTransport); !ok || s.Interface != "vcan0" || !s.NoFilters {
+		t.Errorf("unexpected socketcan transport %#v", tr)
+	}
+}
+
+func TestSocketCANTransport(t *testing.T) {
+	a := openTestSocketCAN(t)
+	b := openTestSocketCAN(t)
+
+	frames := []*can.Frame{
+		{ArbitrationID: 0x705, DLC: 1, Data: [8]byte{0x05}},
+		{ArbitrationID: 0x18FF0001, DLC: 8, Data: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+	}
+
+	for _, frm := range frames {
+		if err := a.Write(frm); err != nil {
+			t.Fatal(err)
+		}
+
+		if received := readTestFrame(b); received == nil || *received != *frm {
+			t.Errorf("expected frame %v, got %v", frm, received)
+		}
+	}
+
+	if err := b.SetFilters([]uint32{0x123}); err != nil {
+		t.Fatal(err)
+	}
+
+	a.Write(&can.Frame{ArbitrationID: 0x705, DLC: 1})
+	a.Write(&can.Frame{ArbitrationID: 0x123, DLC: 2, Data: [8]byte{0xAA, 0xBB}})
+
+	if received := readTestFrame(b); received == nil || received.ArbitrationID != 0x123 {
+		t.Errorf("expected filtered frame 0x123, got %v", received)
+	}
+
+	if received := readTestFrame(b); received != nil {
+		t.Errorf("unexpected frame %v", received)
+	}
+}
+
+func TestSocketCANNetwork(t *testing.T) {
+	a := openTestSocketCAN(t)
+	b := openTestSocketCAN(t)
+
+	network, err := NewNetwork(can.Bus{Transport: b})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := network.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer network.Stop()
+
+	framesChan := network.AcquireCobIDsFramesChan(nil, 0x585)
+	defer network.ReleaseFramesChan(framesChan.ID)
+
+	a.Write(&can.Frame{ArbitrationID: 0x586, DLC: 1})
+	a.Write(&can.Frame{ArbitrationID: 0x585, DLC: 1, Data: [8]byte{0x60}})
+
+	select {
+	case frm := <-framesChan.C:
+		if frm.ArbitrationID != 0x585 {
+			t.Errorf("unexpected frame %v", frm)
+		}
+	case <-time.After(100 * time.Millisecond):
+		t.Error("frame not received")
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package canopen
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/angelodlfrtr/go-can"
+	"github.com/angelodlfrtr/go-can/transports"
+)
+
+func newSocketCanTransport(uri *url.URL) (can.Transport, error) {
+	iface := transportURIPath(uri)
+	if iface == "" {
+		return nil, fmt.Errorf("missing interface in %q", uri)
+	}
+
+	return &transports.SocketCan{Interface: iface}, nil
+}
@@ -0,0 +1,119 @@
+package canopen
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/angelodlfrtr/go-can"
+	"github.com/angelodlfrtr/go-can/transports"
+)
+
+// TransportFactory create a transport from an URI
+type TransportFactory func(uri *url.URL) (can.Transport, error)
+
+var (
+	transportFactoriesMutex sync.Mutex
+	transportFactories      = map[string]TransportFactory{
+		"socketcan": newSocketCanTransport,
+		"usbcan":    newUSBCanAnalyzerTransport,
+		"tcpcan":    newTCPCanTransport,
+		"virtual":   newVirtualTransport,
+		"replay":    newReplayTransport,
+	}
+)
+
+// RegisterTransport register factory for URIs with scheme, replacing any
+// previously registered factory
+func RegisterTransport(scheme string, factory TransportFactory) {
+	transportFactoriesMutex.Lock()
+	defer transportFactoriesMutex.Unlock()
+
+	transportFactories[strings.ToLower(scheme)] = factory
+}
+
+// TransportSchemes return registered transport schemes
+func TransportSchemes() []string {
+	transportFactoriesMutex.Lock()
+	defer transportFactoriesMutex.Unlock()
+
+	schemes := make([]string, 0, len(transportFactories))
+	for scheme := range transportFactories {
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
+
+// NewTransport return a transport, not opened, from an URI. Builtin schemes are:
+//
+//	socketcan://can0?filters=true
+//	usbcan:///dev/ttyUSB0?baudrate=2000000
+//	tcpcan://192.168.1.56:7777
+//	virtual://name
+//	replay:///path/to/capture.log?speed=1
+func NewTransport(uri string) (can.Transport, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transport URI %q: %v", uri, err)
+	}
+
+	transportFactoriesMutex.Lock()
+	factory, ok := transportFactories[strings.ToLower(u.Scheme)]
+	transportFactoriesMutex.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown transport %q, must be one of %s", u.Scheme, strings.Join(TransportSchemes(), ", "))
+	}
+
+	return factory(u)
+}
+
+// transportURIPath return the host or the path of uri, eg: can0 for socketcan://can0
+func transportURIPath(uri *url.URL) string {
+	if uri.Host != "" {
+		return uri.Host + uri.Path
+	}
+
+	if uri.Opaque != "" {
+		return uri.Opaque
+	}
+
+	return uri.Path
+}
+
+func newUSBCanAnalyzerTransport(uri *url.URL) (can.Transport, error) {
+	port := transportURIPath(uri)
+	if port == "" {
+		return nil, fmt.Errorf("missing serial port in %q", uri)
+	}
+
+	baudRate := 2000000
+	if s := uri.Query().Get("baudrate"); s != "" {
+		b, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid baudrate %q", s)
+		}
+
+		baudRate = b
+	}
+
+	return &transports.USBCanAnalyzer{Port: port, BaudRate: baudRate}, nil
+}
+
+func newTCPCanTransport(uri *url.URL) (can.Transport, error) {
+	port, err := strconv.Atoi(uri.Port())
+	if err != nil {
+		return nil, fmt.Errorf("invalid port in %q", uri)
+	}
+
+	return &transports.TCPCan{Host: uri.Hostname(), Port: port}, nil
+}
+
+func newVirtualTransport(uri *url.URL) (can.Transport, error) {
+	return GetVirtualBus(transportURIPath(uri)).Transport(), nil
+}
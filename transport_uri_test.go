@@ -0,0 +1,69 @@
+package canopen
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/angelodlfrtr/go-can"
+	"github.com/angelodlfrtr/go-can/transports"
+)
+
+func TestNewTransport(t *testing.T) {
+	tr, err := NewTransport("usbcan:///dev/ttyUSB0?baudrate=115200")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u, ok := tr.(*transports.USBCanAnalyzer); !ok || u.Port != "/dev/ttyUSB0" || u.BaudRate != 115200 {
+		t.Errorf("unexpected usbcan transport %#v", tr)
+	}
+
+	if _, err := NewTransport("foo://bar"); err == nil {
+		t.Error("expected unknown transport error")
+	}
+
+	RegisterTransport("test", func(uri *url.URL) (can.Transport, error) {
+		return NewVirtualBus().Transport(), nil
+	})
+
+	if _, err := NewTransport("test://"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVirtualBus(t *testing.T) {
+	a, err := NewTransport("virtual://TestVirtualBus")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := GetVirtualBus("TestVirtualBus").Transport()
+
+	for _, tr := range []can.Transport{a, b} {
+		if err := tr.Open(); err != nil {
+			t.Fatal(err)
+		}
+
+		defer tr.Close()
+	}
+
+	if err := a.Write(&can.Frame{ArbitrationID: 0x181, DLC: 1, Data: [8]byte{0x2A}}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case frm := <-b.ReadChan():
+		if frm.ArbitrationID != 0x181 || frm.Data[0] != 0x2A {
+			t.Errorf("unexpected frame %v", frm)
+		}
+	default:
+		t.Fatal("frame not received")
+	}
+
+	// Sender does not receive its own frames
+	select {
+	case frm := <-a.ReadChan():
+		t.Errorf("unexpected frame %v", frm)
+	default:
+	}
+}
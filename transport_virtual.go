@@ -0,0 +1,127 @@
+package canopen
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/angelodlfrtr/go-can"
+)
+
+// virtualTransportBufferSize is the number of frames a VirtualTransport can
+// buffer before dropping frames
+const virtualTransportBufferSize = 1024
+
+var (
+	virtualBusesMutex sync.Mutex
+	virtualBuses      = map[string]*VirtualBus{}
+)
+
+// VirtualBus is an in memory CAN bus. Frames written by a transport of the bus
+// are received by its other opened transports
+type VirtualBus struct {
+	sync.Mutex
+
+	transports []*VirtualTransport
+}
+
+// NewVirtualBus return a new VirtualBus
+func NewVirtualBus() *VirtualBus {
+	return &VirtualBus{}
+}
+
+// GetVirtualBus return the VirtualBus named name, created on first use
+func GetVirtualBus(name string) *VirtualBus {
+	virtualBusesMutex.Lock()
+	defer virtualBusesMutex.Unlock()
+
+	bus, ok := virtualBuses[name]
+	if !ok {
+		bus = NewVirtualBus()
+		virtualBuses[name] = bus
+	}
+
+	return bus
+}
+
+// Transport return a new transport of bus, connected once opened
+func (bus *VirtualBus) Transport() *VirtualTransport {
+	return &VirtualTransport{
+		Bus:      bus,
+		readChan: make(chan *can.Frame, virtualTransportBufferSize),
+	}
+}
+
+// publish frm to opened transports except sender. Frames are dropped for
+// transports with a full buffer. bus must be locked
+func (bus *VirtualBus) publish(sender *VirtualTransport, frm *can.Frame) {
+	for _, t := range bus.transports {
+		if t == sender {
+			continue
+		}
+
+		frmCopy := *frm
+
+		select {
+		case t.readChan <- &frmCopy:
+		default:
+		}
+	}
+}
+
+// VirtualTransport is a can.Transport connected to a VirtualBus
+type VirtualTransport struct {
+	Bus *VirtualBus
+
+	// readChan is never closed, as it can be read concurrently with Close
+	readChan chan *can.Frame
+	opened   bool
+}
+
+// Open connect transport to its bus
+func (t *VirtualTransport) Open() error {
+	t.Bus.Lock()
+	defer t.Bus.Unlock()
+
+	if t.opened {
+		return nil
+	}
+
+	t.opened = true
+	t.Bus.transports = append(t.Bus.transports, t)
+
+	return nil
+}
+
+// Close disconnect transport from its bus
+func (t *VirtualTransport) Close() error {
+	t.Bus.Lock()
+	defer t.Bus.Unlock()
+
+	for i, tt := range t.Bus.transports {
+		if tt == t {
+			t.Bus.transports = append(t.Bus.transports[:i], t.Bus.transports[i+1:]...)
+			t.opened = false
+			break
+		}
+	}
+
+	return nil
+}
+
+// Write frm to other transports of the bus
+func (t *VirtualTransport) Write(frm *can.Frame) error {
+	t.Bus.Lock()
+	defer t.Bus.Unlock()
+
+	if !t.opened {
+		return errors.New("virtual transport not opened")
+	}
+
+	t.Bus.publish(t, frm)
+	return nil
+}
+
+// ReadChan return the channel of frames written by other transports
+func (t *VirtualTransport) ReadChan() chan *can.Frame {
+	return t.readChan
+}